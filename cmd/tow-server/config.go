@@ -38,10 +38,48 @@ type mailConfig struct {
 	Endpoint string `json:"endpoint"`
 }
 
+type storageConfig struct {
+	Backend           string `json:"backend"`
+	Path              string `json:"path"`
+	S3Endpoint        string `json:"s3Endpoint"`
+	S3Region          string `json:"s3Region"`
+	S3Bucket          string `json:"s3Bucket"`
+	S3AccessKey       string `json:"s3AccessKey"`
+	S3SecretKey       string `json:"s3SecretKey"`
+	FellowshipQuotaMB int64  `json:"fellowshipQuotaMB"`
+}
+
 type config struct {
 	Server   serverConfig   `json:"server"`
 	Database databaseConfig `json:"database"`
 	Mail     mailConfig     `json:"mail"`
+	Storage  storageConfig  `json:"storage"`
+}
+
+// redacted returns a copy of the config with its passwords, keys and other secrets blanked out, fit
+// for printing.
+func (config *config) redacted() config {
+	const hidden = "[redacted]"
+
+	c := *config
+	if c.Database.Password != "" {
+		c.Database.Password = hidden
+	}
+
+	c.Database.MasterKey = nil
+	if c.Mail.Key != "" {
+		c.Mail.Key = hidden
+	}
+
+	if c.Storage.S3AccessKey != "" {
+		c.Storage.S3AccessKey = hidden
+	}
+
+	if c.Storage.S3SecretKey != "" {
+		c.Storage.S3SecretKey = hidden
+	}
+
+	return c
 }
 
 func (config *config) GetListenAddress() string {
@@ -116,6 +154,41 @@ func (config *config) GetMailEndpoint() string {
 	return config.Mail.Endpoint
 }
 
+func (config *config) GetStorageBackend() string {
+	return config.Storage.Backend
+}
+
+func (config *config) GetStoragePath() string {
+	return config.Storage.Path
+}
+
+func (config *config) GetS3Endpoint() string {
+	return config.Storage.S3Endpoint
+}
+
+func (config *config) GetS3Region() string {
+	return config.Storage.S3Region
+}
+
+func (config *config) GetS3Bucket() string {
+	return config.Storage.S3Bucket
+}
+
+func (config *config) GetS3AccessKey() string {
+	return config.Storage.S3AccessKey
+}
+
+func (config *config) GetS3SecretKey() string {
+	return config.Storage.S3SecretKey
+}
+
+func (config *config) GetFellowshipStorageQuota() int64 {
+	if config.Storage.FellowshipQuotaMB <= 0 {
+		return 1024 << 20
+	}
+	return config.Storage.FellowshipQuotaMB << 20
+}
+
 func (c *config) Validate() error {
 	if c.Server.Domain == "" {
 		return fmt.Errorf("server domain is required")
@@ -132,6 +205,9 @@ func (c *config) Validate() error {
 	if c.Mail.Endpoint == "" {
 		return fmt.Errorf("mail endpoint is required")
 	}
+	if c.Storage.Backend != "local" && c.Storage.Backend != "s3" {
+		return fmt.Errorf("storage backend must be local or s3")
+	}
 	return nil
 }
 
@@ -169,6 +245,15 @@ func getConfig() *config {
 	flag.StringVar(&config.Mail.Domain, "maildomain", config.Mail.Domain, "Mail domain")
 	flag.StringVar(&config.Mail.Endpoint, "mailendpoint", config.Mail.Endpoint, "Mail API endpoint")
 
+	flag.StringVar(&config.Storage.Backend, "storageBackend", config.Storage.Backend, "Attachment storage backend (local or s3)")
+	flag.StringVar(&config.Storage.Path, "storagePath", config.Storage.Path, "Directory for local attachment storage")
+	flag.StringVar(&config.Storage.S3Endpoint, "s3Endpoint", config.Storage.S3Endpoint, "S3-compatible endpoint URL")
+	flag.StringVar(&config.Storage.S3Region, "s3Region", config.Storage.S3Region, "S3 region")
+	flag.StringVar(&config.Storage.S3Bucket, "s3Bucket", config.Storage.S3Bucket, "S3 bucket")
+	flag.StringVar(&config.Storage.S3AccessKey, "s3AccessKey", config.Storage.S3AccessKey, "S3 access key")
+	flag.StringVar(&config.Storage.S3SecretKey, "s3SecretKey", config.Storage.S3SecretKey, "S3 secret key")
+	flag.Int64Var(&config.Storage.FellowshipQuotaMB, "fellowshipQuotaMB", config.Storage.FellowshipQuotaMB, "Attachment storage per fellowship in MB (default 1024)")
+
 	flag.Parse()
 
 	// Apply flag overrides that need post-processing
@@ -194,7 +279,7 @@ func getConfig() *config {
 		os.Exit(1)
 	}
 
-	fmt.Printf("Config: %+v\n", config)
+	fmt.Printf("Config: %+v\n", config.redacted())
 
 	return config
 }
@@ -284,6 +369,21 @@ func getEnvConfig() *config {
 	maildomain := os.Getenv("MAIL_DOMAIN")
 	mailendpoint := os.Getenv("MAIL_ENDPOINT")
 
+	storageBackend := os.Getenv("STORAGE_BACKEND")
+	if storageBackend == "" {
+		storageBackend = "local"
+	}
+
+	storagePath := os.Getenv("STORAGE_PATH")
+	if storagePath == "" {
+		storagePath = "./data/attachments"
+	}
+
+	fellowshipQuotaMB, err := strconv.ParseInt(os.Getenv("FELLOWSHIP_QUOTA_MB"), 10, 64)
+	if err != nil {
+		fellowshipQuotaMB = 0 // zero triggers the default in GetFellowshipStorageQuota
+	}
+
 	return &config{
 		Server: serverConfig{
 			ListenAddress:                 listenAddress,
@@ -309,6 +409,16 @@ func getEnvConfig() *config {
 			Domain:   maildomain,
 			Endpoint: mailendpoint,
 		},
+		Storage: storageConfig{
+			Backend:           storageBackend,
+			Path:              storagePath,
+			S3Endpoint:        os.Getenv("S3_ENDPOINT"),
+			S3Region:          os.Getenv("S3_REGION"),
+			S3Bucket:          os.Getenv("S3_BUCKET"),
+			S3AccessKey:       os.Getenv("S3_ACCESS_KEY"),
+			S3SecretKey:       os.Getenv("S3_SECRET_KEY"),
+			FellowshipQuotaMB: fellowshipQuotaMB,
+		},
 	}
 }
 
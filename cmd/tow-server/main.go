@@ -11,11 +11,21 @@ import (
 	"time"
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/attachments"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/calendar"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/circles"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/comments"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/events"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/feed"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/fellowships"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/middleware"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/moderation"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/songs"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/users"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/blob"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/cache"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/circletypes"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/contentcheck"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/db/postgresql"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
 )
@@ -61,16 +71,65 @@ func appMain(ctx context.Context, logger *slog.Logger, config *config) error {
 		os.Exit(1)
 	}
 
-	const storeCacheTTL = 5 * time.Minute
+	const (
+		storeCacheTTL          = 5 * time.Minute
+		prayerReminderInterval = 1 * time.Hour
+		publishInterval        = 1 * time.Minute
+		imageSweepInterval     = 5 * time.Minute
+		uploadSweepInterval    = 1 * time.Hour
+
+		// Content checks on new posts.
+		postRateLimit       = 10
+		postRateWindow      = 10 * time.Minute
+		duplicatePostWindow = 24 * time.Hour
+		newMemberAge        = 7 * 24 * time.Hour
+		newMemberMaxLinks   = 1
+	)
+
+	blobStore, err := blob.New(config)
+	if err != nil {
+		return fmt.Errorf("failed to set up attachment storage: %w", err)
+	}
 
 	logger.Info("setting up services")
 	tokensService := service.NewTokensService(ctx, config, postgresql.NewKeyStore(config, db))
 	mailService := service.NewMailService(config, config, logger)
-	userService := service.NewUserService(cache.NewUserStore(postgresql.NewUserStore(db), storeCacheTTL), tokensService, *mailService)
-	fellowshipService := service.NewFellowshipService(cache.NewFellowshipStore(postgresql.NewFellowshipStore(db), storeCacheTTL))
-	feedService := service.NewFeedService(postgresql.NewFeedStore(db), cache.NewFellowshipStore(postgresql.NewFellowshipStore(db), storeCacheTTL), postgresql.NewCircleStore(db))
-
-	rt := api.ComposeRouters(users.NewRouter(userService), fellowships.NewRouter(fellowshipService), feed.NewRouter(feedService))
+	userStore := cache.NewUserStore(postgresql.NewUserStore(db), storeCacheTTL)
+	userService := service.NewUserService(userStore, tokensService, *mailService)
+	notificationService := service.NewNotificationService(userStore, mailService, logger)
+	fellowshipStore := cache.NewFellowshipStore(postgresql.NewFellowshipStore(db), storeCacheTTL)
+	circleStore := postgresql.NewCircleStore(db)
+	fellowshipService := service.NewFellowshipService(fellowshipStore)
+	circleService := service.NewCircleService(circleStore, fellowshipStore)
+	feedStore := postgresql.NewFeedStore(db)
+	attachmentStore := postgresql.NewAttachmentStore(db)
+	moderationStore := postgresql.NewModerationStore(db)
+	contentChecks := contentcheck.NewChain(
+		contentcheck.RateLimit(feedStore, postRateLimit, postRateWindow),
+		contentcheck.Duplicates(feedStore, duplicatePostWindow),
+		contentcheck.BlockedWords(moderationStore),
+		contentcheck.NewMemberLinks(fellowshipStore, newMemberMaxLinks, newMemberAge),
+	)
+	feedService := service.NewFeedService(feedStore, userStore, fellowshipStore, circleStore, postgresql.NewReactionStore(db), attachmentStore, moderationStore, circletypes.Default(), contentChecks, notificationService, logger)
+	imageProcessor := service.NewImageProcessor(attachmentStore, blobStore, logger)
+	attachmentService := service.NewAttachmentService(attachmentStore, blobStore, circleStore, tokensService, feedService, imageProcessor, config, config, logger)
+	commentStore := postgresql.NewCommentStore(db)
+	commentService := service.NewCommentService(commentStore, feedService)
+	moderationService := service.NewModerationService(moderationStore, commentStore, feedStore, fellowshipStore, feedService, notificationService, logger)
+	readService := service.NewReadService(postgresql.NewReadStore(db), feedService)
+	pollService := service.NewPollService(postgresql.NewPollStore(db), feedService)
+	eventStore := postgresql.NewEventStore(db)
+	eventService := service.NewEventService(eventStore, fellowshipStore, circleStore, feedService)
+	calendarService := service.NewCalendarService(postgresql.NewCalendarStore(db), eventStore, userStore, fellowshipStore, eventService, config)
+	songService := service.NewSongService(postgresql.NewSongStore(db), fellowshipStore, fellowshipService)
+	prayerReminderService := service.NewPrayerReminderService(feedStore, mailService, logger)
+
+	go prayerReminderService.Run(ctx, prayerReminderInterval)
+	go service.NewPostPublisher(feedService, logger).Run(ctx, publishInterval)
+	go imageProcessor.Run(ctx, imageSweepInterval)
+	go attachmentService.Run(ctx, uploadSweepInterval)
+
+	rt := api.ComposeRouters(users.NewRouter(userService), fellowships.NewRouter(fellowshipService), circles.NewRouter(circleService), feed.NewRouter(feedService, readService, pollService), comments.NewRouter(commentService), attachments.NewRouter(attachmentService), moderation.NewRouter(moderationService), events.NewRouter(eventService), calendar.NewRouter(calendarService), songs.NewRouter(songService))
 
 	middlewares := []api.MiddlewareFunc{middleware.AuthMiddleware(userService)}
 
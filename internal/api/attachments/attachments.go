@@ -0,0 +1,9 @@
+package attachments
+
+import (
+	"github.com/google/uuid"
+)
+
+type AttachmentIdRequest struct {
+	AttachmentId uuid.UUID `json:"attachmentId"`
+}
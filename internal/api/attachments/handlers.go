@@ -0,0 +1,133 @@
+package attachments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type attachmentService interface {
+	Upload(ctx context.Context, user domain.User, fellowshipId, circleId uuid.UUID, name string, size int64, content io.Reader) (*domain.Attachment, error)
+	URL(ctx context.Context, user domain.User, attachmentId uuid.UUID) (*domain.AttachmentURL, error)
+	Delete(ctx context.Context, user domain.User, attachmentId uuid.UUID) error
+}
+
+type downloadService interface {
+	Open(ctx context.Context, attachmentId uuid.UUID, token string, variant domain.AttachmentVariant) (*domain.Attachment, io.ReadCloser, error)
+}
+
+// upload stores the raw request body as an attachment. The target and file name are passed as
+// the fellowshipId or circleId and name query parameters.
+func upload(a attachmentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		if r.ContentLength < 0 {
+			return &api.Error{Code: http.StatusLengthRequired, Message: "content length required", Err: fmt.Errorf("upload without content length")}
+		}
+
+		query := r.URL.Query()
+		fellowshipId, err := api.QueryUUID(query, "fellowshipId")
+		if err != nil {
+			return err
+		}
+
+		circleId, err := api.QueryUUID(query, "circleId")
+		if err != nil {
+			return err
+		}
+
+		attachment, err := a.Upload(r.Context(), *user, fellowshipId, circleId, query.Get("name"), r.ContentLength, r.Body)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, attachment, http.StatusCreated)
+		return nil
+	}
+}
+
+func attachmentURL(a attachmentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var request AttachmentIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		link, err := a.URL(r.Context(), *user, request.AttachmentId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, link, http.StatusOK)
+		return nil
+	}
+}
+
+// download streams an attachment to anyone holding a signed link to it. Images can be downloaded as
+// their web version or thumbnail with the variant query parameter.
+func download(a downloadService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		attachmentId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		query := r.URL.Query()
+		attachment, content, err := a.Open(r.Context(), attachmentId, query.Get("token"), domain.AttachmentVariant(query.Get("variant")))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", attachment.ContentType)
+		if attachment.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Name}))
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		w.WriteHeader(http.StatusOK)
+
+		// The status has been sent, so a failed copy can only be reported by cutting the response short.
+		_, err = io.Copy(w, content)
+		return err
+	}
+}
+
+func deleteAttachment(a attachmentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var request AttachmentIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := a.Delete(r.Context(), *user, request.AttachmentId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
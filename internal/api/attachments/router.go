@@ -0,0 +1,47 @@
+package attachments
+
+import (
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(attachmentService *service.AttachmentService) *Router {
+	return &Router{attachmentService: attachmentService}
+}
+
+type Router struct {
+	attachmentService *service.AttachmentService
+}
+
+func (r *Router) Routes() []api.Route {
+	uploadLimit := api.WithBodyLimit(domain.AttachmentMaxSize)
+	attachmentIdLimit := api.WithBodyLimit(512)
+
+	return []api.Route{
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/attachments/upload",
+			Handler: uploadLimit(http.MethodPost, "/api/attachments/upload", upload(r.attachmentService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/attachments/url",
+			Handler: attachmentIdLimit(http.MethodPost, "/api/attachments/url", attachmentURL(r.attachmentService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/attachments/delete",
+			Handler: attachmentIdLimit(http.MethodPost, "/api/attachments/delete", deleteAttachment(r.attachmentService)),
+		},
+		{
+			// Downloads are authorised by the signed token in the link rather than a session.
+			Method:  http.MethodGet,
+			Pattern: "/api/attachments/{id}",
+			Handler: download(r.attachmentService),
+			Public:  true,
+		},
+	}
+}
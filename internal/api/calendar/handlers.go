@@ -0,0 +1,104 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/ical"
+	"github.com/google/uuid"
+)
+
+type feedTokenService interface {
+	NewFeed(ctx context.Context, user domain.User) (*domain.CalendarFeed, error)
+	RevokeFeed(ctx context.Context, user domain.User) error
+}
+
+type feedService interface {
+	UserFeed(ctx context.Context, token string) (*ical.Calendar, error)
+	FellowshipFeed(ctx context.Context, fellowshipId uuid.UUID) (*ical.Calendar, error)
+}
+
+func newFeed(c feedTokenService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		feed, err := c.NewFeed(r.Context(), *user)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, feed, http.StatusCreated)
+		return nil
+	}
+}
+
+func revokeFeed(c feedTokenService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		if err := c.RevokeFeed(r.Context(), *user); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func userFeed(c feedService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		calendar, err := c.UserFeed(r.Context(), r.PathValue("token"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		return respondCalendar(w, calendar, "private")
+	}
+}
+
+func fellowshipFeed(c feedService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		fellowshipId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		calendar, err := c.FellowshipFeed(r.Context(), fellowshipId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		return respondCalendar(w, calendar, "public")
+	}
+}
+
+// respondCalendar encodes the calendar before sending anything, so encoding errors can still be
+// reported with an error status.
+func respondCalendar(w http.ResponseWriter, calendar *ical.Calendar, cacheScope string) error {
+	var body bytes.Buffer
+	if err := calendar.Encode(&body, time.Now()); err != nil {
+		return &api.Error{Code: http.StatusInternalServerError, Message: "failed to encode calendar", Err: err}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	w.Header().Set("Content-Disposition", `inline; filename="events.ics"`)
+	w.Header().Set("Cache-Control", cacheScope+", max-age=300")
+	w.WriteHeader(http.StatusOK)
+
+	// The status has been sent, so a failed write can only be reported by cutting the response short.
+	_, err := body.WriteTo(w)
+	return err
+}
@@ -0,0 +1,47 @@
+package calendar
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(calendarService *service.CalendarService) *Router {
+	return &Router{calendarService: calendarService}
+}
+
+type Router struct {
+	calendarService *service.CalendarService
+}
+
+func (r *Router) Routes() []api.Route {
+	feedRateLimit := api.RateLimitMiddleware(30, 1*time.Minute)
+
+	return []api.Route{
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/calendar/feed",
+			Handler: newFeed(r.calendarService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/calendar/revoke",
+			Handler: revokeFeed(r.calendarService),
+		},
+		{
+			// Calendar apps cannot send a session, so user feeds are authorised by the token in the link.
+			Method:  http.MethodGet,
+			Pattern: "/api/calendar/user/{token}/events.ics",
+			Handler: feedRateLimit(http.MethodGet, "/api/calendar/user/{token}/events.ics", userFeed(r.calendarService)),
+			Public:  true,
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/calendar/fellowship/{id}/events.ics",
+			Handler: feedRateLimit(http.MethodGet, "/api/calendar/fellowship/{id}/events.ics", fellowshipFeed(r.calendarService)),
+			Public:  true,
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package circles
+
+import (
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type CircleRequest struct {
+	CircleId uuid.UUID `json:"circleId"`
+}
+
+type RespondRequest struct {
+	CircleId uuid.UUID `json:"circleId"`
+	UserId   uuid.UUID `json:"userId"`
+	Approve  bool      `json:"approve"`
+}
+
+type InviteRequest struct {
+	CircleId uuid.UUID `json:"circleId"`
+	UserId   uuid.UUID `json:"userId"`
+}
+
+type AccessModeRequest struct {
+	CircleId   uuid.UUID               `json:"circleId"`
+	AccessMode domain.CircleAccessMode `json:"accessMode"`
+}
@@ -0,0 +1,148 @@
+package circles
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type circleService interface {
+	List(ctx context.Context, user domain.User) ([]domain.Circle, error)
+	Join(ctx context.Context, user domain.User, circleId uuid.UUID) error
+}
+
+type circleModerationService interface {
+	JoinRequests(ctx context.Context, user domain.User, circleId uuid.UUID) ([]domain.CircleJoinRequest, error)
+	RespondToJoinRequest(ctx context.Context, user domain.User, circleId uuid.UUID, userId uuid.UUID, approve bool) error
+	Invite(ctx context.Context, user domain.User, circleId uuid.UUID, userId uuid.UUID) error
+	SetAccessMode(ctx context.Context, user domain.User, circleId uuid.UUID, mode domain.CircleAccessMode) error
+}
+
+func list(c circleService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		circles, err := c.List(r.Context(), *user)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, circles, http.StatusOK)
+		return nil
+	}
+}
+
+func join(c circleService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var circleRequest CircleRequest
+		if err := json.NewDecoder(r.Body).Decode(&circleRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := c.Join(r.Context(), *user, circleRequest.CircleId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func joinRequests(c circleModerationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		circleId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		requests, err := c.JoinRequests(r.Context(), *user, circleId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, requests, http.StatusOK)
+		return nil
+	}
+}
+
+func respond(c circleModerationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var respondRequest RespondRequest
+		if err := json.NewDecoder(r.Body).Decode(&respondRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := c.RespondToJoinRequest(r.Context(), *user, respondRequest.CircleId, respondRequest.UserId, respondRequest.Approve)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func invite(c circleModerationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var inviteRequest InviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&inviteRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := c.Invite(r.Context(), *user, inviteRequest.CircleId, inviteRequest.UserId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func setAccessMode(c circleModerationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var accessModeRequest AccessModeRequest
+		if err := json.NewDecoder(r.Body).Decode(&accessModeRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := c.SetAccessMode(r.Context(), *user, accessModeRequest.CircleId, accessModeRequest.AccessMode); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package circles
+
+import (
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(circleService *service.CircleService) *Router {
+	return &Router{circleService: circleService}
+}
+
+type Router struct {
+	circleService *service.CircleService
+}
+
+func (r *Router) Routes() []api.Route {
+	requestLimit := api.WithBodyLimit(512)
+
+	return []api.Route{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/circles",
+			Handler: list(r.circleService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/circles/list",
+			Handler: requestLimit(http.MethodPost, "/api/circles/list", list(r.circleService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/circles/join",
+			Handler: requestLimit(http.MethodPost, "/api/circles/join", join(r.circleService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/circles/{id}/requests",
+			Handler: joinRequests(r.circleService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/circles/respond",
+			Handler: requestLimit(http.MethodPost, "/api/circles/respond", respond(r.circleService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/circles/invite",
+			Handler: requestLimit(http.MethodPost, "/api/circles/invite", invite(r.circleService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/circles/accessmode",
+			Handler: requestLimit(http.MethodPost, "/api/circles/accessmode", setAccessMode(r.circleService)),
+		},
+	}
+}
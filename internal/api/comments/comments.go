@@ -0,0 +1,26 @@
+package comments
+
+import (
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ListResponse struct {
+	Comments   []domain.Comment `json:"comments"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+type AddRequest struct {
+	PostId   uuid.UUID  `json:"postId"`
+	ParentId *uuid.UUID `json:"parentId"`
+	Body     string     `json:"body"`
+}
+
+type EditRequest struct {
+	CommentId uuid.UUID `json:"commentId"`
+	Body      string    `json:"body"`
+}
+
+type CommentIdRequest struct {
+	CommentId uuid.UUID `json:"commentId"`
+}
@@ -0,0 +1,110 @@
+package comments
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type commentService interface {
+	List(ctx context.Context, user domain.User, postId uuid.UUID, limit *int, cursor string) ([]domain.Comment, string, error)
+	Add(ctx context.Context, user domain.User, postId uuid.UUID, parentId *uuid.UUID, body string) error
+	Edit(ctx context.Context, user domain.User, commentId uuid.UUID, body string) error
+	Delete(ctx context.Context, user domain.User, commentId uuid.UUID) error
+}
+
+func list(c commentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		postId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		query := r.URL.Query()
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		comments, nextCursor, err := c.List(r.Context(), *user, postId, limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, ListResponse{Comments: comments, NextCursor: nextCursor}, http.StatusOK)
+		return nil
+	}
+}
+
+func add(c commentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var addRequest AddRequest
+		if err := json.NewDecoder(r.Body).Decode(&addRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := c.Add(r.Context(), *user, addRequest.PostId, addRequest.ParentId, addRequest.Body); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func edit(c commentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var editRequest EditRequest
+		if err := json.NewDecoder(r.Body).Decode(&editRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := c.Edit(r.Context(), *user, editRequest.CommentId, editRequest.Body); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func deleteComment(c commentService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var commentIdRequest CommentIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&commentIdRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := c.Delete(r.Context(), *user, commentIdRequest.CommentId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
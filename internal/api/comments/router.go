@@ -0,0 +1,44 @@
+package comments
+
+import (
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(commentService *service.CommentService) *Router {
+	return &Router{commentService: commentService}
+}
+
+type Router struct {
+	commentService *service.CommentService
+}
+
+func (r *Router) Routes() []api.Route {
+	commentLimit := api.WithBodyLimit(16384)
+	commentIdLimit := api.WithBodyLimit(512)
+
+	return []api.Route{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed/{id}/comments",
+			Handler: list(r.commentService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/comments/add",
+			Handler: commentLimit(http.MethodPost, "/api/comments/add", add(r.commentService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/comments/edit",
+			Handler: commentLimit(http.MethodPost, "/api/comments/edit", edit(r.commentService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/comments/delete",
+			Handler: commentIdLimit(http.MethodPost, "/api/comments/delete", deleteComment(r.commentService)),
+		},
+	}
+}
@@ -28,6 +28,116 @@ func MapDomainError(err error) *Error {
 		return &Error{Code: http.StatusUnauthorized, ErrorCode: "invalid_token", Message: "invalid token", Err: err}
 	case errors.Is(err, domain.ErrUserNotFound):
 		return &Error{Code: http.StatusNotFound, ErrorCode: "user_not_found", Message: "user not found", Err: err}
+	case errors.Is(err, domain.ErrFellowshipNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "fellowship_not_found", Message: "fellowship not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidFellowshipParent):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_fellowship_parent", Message: "invalid parent fellowship", Err: err}
+	case errors.Is(err, domain.ErrInvalidFellowshipDescription):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_fellowship_description", Message: "invalid fellowship description", Err: err}
+	case errors.Is(err, domain.ErrInvalidTimezone):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_timezone", Message: "invalid timezone", Err: err}
+	case errors.Is(err, domain.ErrInsufficientAccess):
+		return &Error{Code: http.StatusForbidden, ErrorCode: "insufficient_access", Message: "insufficient access", Err: err}
+	case errors.Is(err, domain.ErrNotMember):
+		return &Error{Code: http.StatusForbidden, ErrorCode: "not_member", Message: "not a member", Err: err}
+	case errors.Is(err, domain.ErrCircleNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "circle_not_found", Message: "circle not found", Err: err}
+	case errors.Is(err, domain.ErrAlreadyMember):
+		return &Error{Code: http.StatusConflict, ErrorCode: "already_member", Message: "already a member", Err: err}
+	case errors.Is(err, domain.ErrJoinRequestNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "join_request_not_found", Message: "join request not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidCircleMember):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_circle_member", Message: "user is not a member of the circle's fellowship", Err: err}
+	case errors.Is(err, domain.ErrInvalidAccessMode):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_access_mode", Message: "invalid circle access mode", Err: err}
+	case errors.Is(err, domain.ErrPostNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "post_not_found", Message: "post not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidPostTarget):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_post_target", Message: "post must target either a fellowship or a circle", Err: err}
+	case errors.Is(err, domain.ErrInvalidPostKind):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_post_kind", Message: "post kind not allowed in this circle", Err: err}
+	case errors.Is(err, domain.ErrInvalidPostDetails):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_post_details", Message: "invalid post details", Err: err}
+	case errors.Is(err, domain.ErrInvalidPostFormat):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_post_format", Message: "invalid post format", Err: err}
+	case errors.Is(err, domain.ErrInvalidReaction):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_reaction", Message: "reaction not allowed on this post", Err: err}
+	case errors.Is(err, domain.ErrInvalidPin):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_pin", Message: "post cannot be pinned", Err: err}
+	case errors.Is(err, domain.ErrInvalidTag):
+		return &Error{Code: http.StatusBadRequest, ErrorCode: "invalid_tag", Message: "invalid tag", Err: err}
+	case errors.Is(err, domain.ErrNotPrayerRequest):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "not_prayer_request", Message: "post is not a prayer request", Err: err}
+	case errors.Is(err, domain.ErrInvalidPrayerStatus):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_prayer_status", Message: "invalid prayer status", Err: err}
+	case errors.Is(err, domain.ErrPollNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "poll_not_found", Message: "poll not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidPoll):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_poll", Message: "invalid poll", Err: err}
+	case errors.Is(err, domain.ErrInvalidVote):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_vote", Message: "invalid vote", Err: err}
+	case errors.Is(err, domain.ErrAlreadyVoted):
+		return &Error{Code: http.StatusConflict, ErrorCode: "already_voted", Message: "already voted", Err: err}
+	case errors.Is(err, domain.ErrPollClosed):
+		return &Error{Code: http.StatusConflict, ErrorCode: "poll_closed", Message: "poll closed", Err: err}
+	case errors.Is(err, domain.ErrEventNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "event_not_found", Message: "event not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidEvent):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_event", Message: "invalid event", Err: err}
+	case errors.Is(err, domain.ErrInvalidRecurrence):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_recurrence", Message: "invalid recurrence rule", Err: err}
+	case errors.Is(err, domain.ErrOccurrenceNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "occurrence_not_found", Message: "event occurrence not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidRSVP):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_rsvp", Message: "invalid rsvp", Err: err}
+	case errors.Is(err, domain.ErrInvalidEventRange):
+		return &Error{Code: http.StatusBadRequest, ErrorCode: "invalid_event_range", Message: "invalid event range", Err: err}
+	case errors.Is(err, domain.ErrCalendarFeedNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "calendar_feed_not_found", Message: "calendar feed not found", Err: err}
+	case errors.Is(err, domain.ErrSongNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "song_not_found", Message: "song not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidSong):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_song", Message: "invalid song", Err: err}
+	case errors.Is(err, domain.ErrDuplicateSong):
+		return &Error{Code: http.StatusConflict, ErrorCode: "duplicate_song", Message: "song with this CCLI number already in library", Err: err}
+	case errors.Is(err, domain.ErrReportNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "report_not_found", Message: "report not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidReport):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_report", Message: "invalid report", Err: err}
+	case errors.Is(err, domain.ErrAlreadyReported):
+		return &Error{Code: http.StatusConflict, ErrorCode: "already_reported", Message: "already reported", Err: err}
+	case errors.Is(err, domain.ErrReportResolved):
+		return &Error{Code: http.StatusConflict, ErrorCode: "report_resolved", Message: "report already resolved", Err: err}
+	case errors.Is(err, domain.ErrInvalidModerationAction):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_moderation_action", Message: "invalid moderation action", Err: err}
+	case errors.Is(err, domain.ErrInvalidBlockedWord):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_blocked_word", Message: "invalid blocked word", Err: err}
+	case errors.Is(err, domain.ErrPostRejected):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "post_rejected", Message: "post rejected by the fellowship's content rules", Err: err}
+	case errors.Is(err, domain.ErrPostRateLimited):
+		return &Error{Code: http.StatusTooManyRequests, ErrorCode: "post_rate_limited", Message: "posting too often", Err: err}
+	case errors.Is(err, domain.ErrAttachmentNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "attachment_not_found", Message: "attachment not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidAttachment):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_attachment", Message: "invalid attachment", Err: err}
+	case errors.Is(err, domain.ErrAttachmentTooLarge):
+		return &Error{Code: http.StatusRequestEntityTooLarge, ErrorCode: "attachment_too_large", Message: "attachment too large", Err: err}
+	case errors.Is(err, domain.ErrUnsupportedAttachment):
+		return &Error{Code: http.StatusUnsupportedMediaType, ErrorCode: "unsupported_attachment", Message: "unsupported attachment type", Err: err}
+	case errors.Is(err, domain.ErrAttachmentNotReady):
+		return &Error{Code: http.StatusConflict, ErrorCode: "attachment_not_ready", Message: "attachment not ready", Err: err}
+	case errors.Is(err, domain.ErrStorageQuotaExceeded):
+		return &Error{Code: http.StatusRequestEntityTooLarge, ErrorCode: "storage_quota_exceeded", Message: "storage quota exceeded", Err: err}
+	case errors.Is(err, domain.ErrCommentNotFound):
+		return &Error{Code: http.StatusNotFound, ErrorCode: "comment_not_found", Message: "comment not found", Err: err}
+	case errors.Is(err, domain.ErrInvalidComment):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_comment", Message: "invalid comment", Err: err}
+	case errors.Is(err, domain.ErrInvalidCommentParent):
+		return &Error{Code: http.StatusUnprocessableEntity, ErrorCode: "invalid_comment_parent", Message: "invalid parent comment", Err: err}
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return &Error{Code: http.StatusBadRequest, ErrorCode: "invalid_cursor", Message: "invalid cursor", Err: err}
+	case errors.Is(err, domain.ErrInvalidSearchQuery):
+		return &Error{Code: http.StatusBadRequest, ErrorCode: "invalid_search_query", Message: "invalid search query", Err: err}
 	default:
 		return &Error{Code: http.StatusInternalServerError, Message: "internal error", Err: err}
 	}
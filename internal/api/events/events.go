@@ -0,0 +1,50 @@
+package events
+
+import (
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// EventRequest describes an event. Times are RFC 3339 and are kept in the fellowship's timezone, and
+// RRule is an optional RFC 5545 recurrence rule such as "FREQ=WEEKLY;BYDAY=SU".
+type EventRequest struct {
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	CircleId     uuid.UUID `json:"circleId"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Location     string    `json:"location"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	RRule        string    `json:"rrule"`
+}
+
+type EditEventRequest struct {
+	EventId uuid.UUID `json:"eventId"`
+	EventRequest
+}
+
+type EventIdRequest struct {
+	EventId uuid.UUID `json:"eventId"`
+}
+
+// ExceptionRequest cancels an occurrence, or moves it when Start and End are given.
+type ExceptionRequest struct {
+	EventId    uuid.UUID  `json:"eventId"`
+	Occurrence time.Time  `json:"occurrence"`
+	Cancelled  bool       `json:"cancelled"`
+	Start      *time.Time `json:"start"`
+	End        *time.Time `json:"end"`
+}
+
+type OccurrenceRequest struct {
+	EventId    uuid.UUID `json:"eventId"`
+	Occurrence time.Time `json:"occurrence"`
+}
+
+type RSVPRequest struct {
+	EventId    uuid.UUID           `json:"eventId"`
+	Occurrence time.Time           `json:"occurrence"`
+	Response   domain.RSVPResponse `json:"response"`
+}
@@ -0,0 +1,252 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type eventService interface {
+	Create(ctx context.Context, user domain.User, input domain.EventInput) (*domain.Event, error)
+	Get(ctx context.Context, user domain.User, eventId uuid.UUID) (*domain.Event, error)
+	Edit(ctx context.Context, user domain.User, eventId uuid.UUID, input domain.EventInput) (*domain.Event, error)
+	Delete(ctx context.Context, user domain.User, eventId uuid.UUID) error
+	SetException(ctx context.Context, user domain.User, eventId uuid.UUID, exception domain.EventException) error
+	ClearException(ctx context.Context, user domain.User, eventId uuid.UUID, occurrence time.Time) error
+	Occurrences(ctx context.Context, user domain.User, filter domain.FeedFilter, from time.Time, to time.Time) (*domain.EventOccurrences, error)
+}
+
+type rsvpService interface {
+	RSVP(ctx context.Context, user domain.User, eventId uuid.UUID, occurrence time.Time, response domain.RSVPResponse) error
+	RSVPs(ctx context.Context, user domain.User, eventId uuid.UUID, occurrence time.Time) ([]domain.EventRSVP, error)
+}
+
+func (e EventRequest) input() domain.EventInput {
+	return domain.EventInput{FellowshipId: e.FellowshipId, CircleId: e.CircleId, Title: e.Title, Description: e.Description, Location: e.Location,
+		Start: e.Start, End: e.End, RRule: e.RRule}
+}
+
+func create(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var eventRequest EventRequest
+		if err := json.NewDecoder(r.Body).Decode(&eventRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		event, err := e.Create(r.Context(), *user, eventRequest.input())
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, event, http.StatusCreated)
+		return nil
+	}
+}
+
+func get(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		eventId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		event, err := e.Get(r.Context(), *user, eventId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, event, http.StatusOK)
+		return nil
+	}
+}
+
+func edit(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var editRequest EditEventRequest
+		if err := json.NewDecoder(r.Body).Decode(&editRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		event, err := e.Edit(r.Context(), *user, editRequest.EventId, editRequest.input())
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, event, http.StatusOK)
+		return nil
+	}
+}
+
+func deleteEvent(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var deleteRequest EventIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&deleteRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := e.Delete(r.Context(), *user, deleteRequest.EventId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func setException(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var exceptionRequest ExceptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&exceptionRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		exception := domain.EventException{Occurrence: exceptionRequest.Occurrence, Cancelled: exceptionRequest.Cancelled, Start: exceptionRequest.Start, End: exceptionRequest.End}
+		if err := e.SetException(r.Context(), *user, exceptionRequest.EventId, exception); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func clearException(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var occurrenceRequest OccurrenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&occurrenceRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := e.ClearException(r.Context(), *user, occurrenceRequest.EventId, occurrenceRequest.Occurrence); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func occurrences(e eventService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		fellowshipId, err := api.QueryUUID(query, "fellowshipId")
+		if err != nil {
+			return err
+		}
+
+		circleId, err := api.QueryUUID(query, "circleId")
+		if err != nil {
+			return err
+		}
+
+		from, err := api.QueryTime(query, "from")
+		if err != nil {
+			return err
+		}
+
+		to, err := api.QueryTime(query, "to")
+		if err != nil {
+			return err
+		}
+
+		occurrences, err := e.Occurrences(r.Context(), *user, domain.FeedFilter{FellowshipId: fellowshipId, CircleId: circleId}, from, to)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, occurrences, http.StatusOK)
+		return nil
+	}
+}
+
+func rsvp(e rsvpService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var rsvpRequest RSVPRequest
+		if err := json.NewDecoder(r.Body).Decode(&rsvpRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := e.RSVP(r.Context(), *user, rsvpRequest.EventId, rsvpRequest.Occurrence, rsvpRequest.Response); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func rsvps(e rsvpService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		eventId, err := api.QueryUUID(query, "eventId")
+		if err != nil {
+			return err
+		}
+
+		occurrence, err := api.QueryTime(query, "occurrence")
+		if err != nil {
+			return err
+		}
+
+		rsvps, err := e.RSVPs(r.Context(), *user, eventId, occurrence)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, rsvps, http.StatusOK)
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(eventService *service.EventService) *Router {
+	return &Router{eventService: eventService}
+}
+
+type Router struct {
+	eventService *service.EventService
+}
+
+func (r *Router) Routes() []api.Route {
+	eventLimit := api.WithBodyLimit(8192)
+	eventIdLimit := api.WithBodyLimit(512)
+
+	return []api.Route{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/events/occurrences",
+			Handler: occurrences(r.eventService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/events/{id}",
+			Handler: get(r.eventService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/events/create",
+			Handler: eventLimit(http.MethodPost, "/api/events/create", create(r.eventService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/events/edit",
+			Handler: eventLimit(http.MethodPost, "/api/events/edit", edit(r.eventService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/events/delete",
+			Handler: eventIdLimit(http.MethodPost, "/api/events/delete", deleteEvent(r.eventService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/events/exception",
+			Handler: eventIdLimit(http.MethodPost, "/api/events/exception", setException(r.eventService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/events/clearexception",
+			Handler: eventIdLimit(http.MethodPost, "/api/events/clearexception", clearException(r.eventService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/events/rsvp",
+			Handler: eventIdLimit(http.MethodPost, "/api/events/rsvp", rsvp(r.eventService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/events/rsvps",
+			Handler: rsvps(r.eventService),
+		},
+	}
+}
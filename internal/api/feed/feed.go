@@ -1,20 +1,97 @@
 package feed
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
 	"github.com/google/uuid"
 )
 
 type ListRequest struct {
-	Limit  *int       `json:"limit"`
-	Before *time.Time `json:"before"`
-	After  *time.Time `json:"after"`
+	Limit        *int                 `json:"limit"`
+	Cursor       string               `json:"cursor"`
+	FellowshipId uuid.UUID            `json:"fellowshipId"`
+	CircleId     uuid.UUID            `json:"circleId"`
+	AuthorId     uuid.UUID            `json:"authorId"`
+	Kind         domain.PostKind      `json:"kind"`
+	PrayerStatus *domain.PrayerStatus `json:"prayerStatus"`
+	Tag          string               `json:"tag"`
+}
+
+// ListResponse is a page of a feed. Pinned is only filled on the first page, and pinned posts are
+// also listed in Posts in their usual place so paging is not disturbed when a pin expires. Clients
+// showing both should skip the posts in Posts whose ids are in Pinned.
+type ListResponse struct {
+	Pinned     []domain.Post `json:"pinned"`
+	Posts      []domain.Post `json:"posts"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	PrevCursor string        `json:"prevCursor,omitempty"`
+}
+
+func newListResponse(page *domain.FeedPage) ListResponse {
+	return ListResponse{Pinned: page.Pinned, Posts: page.Posts, NextCursor: page.NextCursor, PrevCursor: page.PrevCursor}
+}
+
+type SearchResponse struct {
+	Results    []domain.PostSearchResult `json:"results"`
+	NextCursor string                    `json:"nextCursor,omitempty"`
 }
 
 type PostRequest struct {
-	FellowshipId uuid.UUID `json:"fellowshipId"`
-	CircleId     uuid.UUID `json:"circleId"`
-	Heading      string    `json:"heading"`
-	Article      string    `json:"article"`
+	FellowshipId uuid.UUID         `json:"fellowshipId"`
+	CircleId     uuid.UUID         `json:"circleId"`
+	Kind         domain.PostKind   `json:"kind"`
+	Heading      string            `json:"heading"`
+	Article      string            `json:"article"`
+	Format       domain.PostFormat `json:"format"`
+	Details      json.RawMessage   `json:"details"`
+	FollowUp     *time.Time        `json:"followUp"`
+	Draft        bool              `json:"draft"`
+	PublishAt    *time.Time        `json:"publishAt"`
+	Attachments  []uuid.UUID       `json:"attachments"`
+	Poll         *domain.Poll      `json:"poll"`
+}
+
+type EditRequest struct {
+	PostId  uuid.UUID       `json:"postId"`
+	Heading string          `json:"heading"`
+	Article string          `json:"article"`
+	Details json.RawMessage `json:"details"`
+}
+
+type PostIdRequest struct {
+	PostId uuid.UUID `json:"postId"`
+}
+
+type VoteRequest struct {
+	PostId  uuid.UUID `json:"postId"`
+	Options []int     `json:"options"`
+}
+
+type ReactRequest struct {
+	PostId uuid.UUID           `json:"postId"`
+	Kind   domain.ReactionKind `json:"kind"`
+}
+
+type ReactResponse struct {
+	Reacted bool `json:"reacted"`
+}
+
+type PrayerStateRequest struct {
+	PostId    uuid.UUID           `json:"postId"`
+	Status    domain.PrayerStatus `json:"status"`
+	Testimony string              `json:"testimony"`
+	FollowUp  *time.Time          `json:"followUp"`
+}
+
+type PinRequest struct {
+	PostId      uuid.UUID `json:"postId"`
+	PinnedUntil time.Time `json:"pinnedUntil"`
+	Urgent      bool      `json:"urgent"`
+}
+
+type PublishRequest struct {
+	PostId    uuid.UUID  `json:"postId"`
+	PublishAt *time.Time `json:"publishAt"`
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
@@ -13,8 +14,46 @@ import (
 )
 
 type feedService interface {
-	List(ctx context.Context, user domain.User, limit *int, before *time.Time, after *time.Time) ([]domain.Post, error)
-	Post(ctx context.Context, user domain.User, fellowshipId uuid.UUID, circleId uuid.UUID, heading string, article string) error
+	List(ctx context.Context, user domain.User, filter domain.FeedFilter, limit *int, cursor string) (*domain.FeedPage, error)
+	Post(ctx context.Context, user domain.User, input domain.PostInput) (bool, error)
+}
+
+type postSearchService interface {
+	Search(ctx context.Context, user domain.User, query string, filter domain.FeedFilter, limit *int, cursor string) ([]domain.PostSearchResult, string, error)
+}
+
+type postEditService interface {
+	Edit(ctx context.Context, user domain.User, postId uuid.UUID, edit domain.PostEdit) (bool, error)
+	Delete(ctx context.Context, user domain.User, postId uuid.UUID) error
+	Revisions(ctx context.Context, user domain.User, postId uuid.UUID) ([]domain.PostRevision, error)
+}
+
+type prayerService interface {
+	SetPrayerState(ctx context.Context, user domain.User, postId uuid.UUID, state domain.PrayerState) error
+}
+
+type draftService interface {
+	Drafts(ctx context.Context, user domain.User) ([]domain.Post, error)
+	Publish(ctx context.Context, user domain.User, postId uuid.UUID, publishAt *time.Time) (bool, error)
+}
+
+type pinService interface {
+	Pin(ctx context.Context, user domain.User, postId uuid.UUID, until time.Time, urgent bool) error
+	Unpin(ctx context.Context, user domain.User, postId uuid.UUID) error
+}
+
+type readService interface {
+	MarkRead(ctx context.Context, user domain.User, postId uuid.UUID) error
+	Unread(ctx context.Context, user domain.User) ([]domain.UnreadCount, error)
+}
+
+type pollService interface {
+	Vote(ctx context.Context, user domain.User, postId uuid.UUID, options []int) error
+	Results(ctx context.Context, user domain.User, postId uuid.UUID) (*domain.PollResults, error)
+}
+
+type reactionService interface {
+	React(ctx context.Context, user domain.User, postId uuid.UUID, kind domain.ReactionKind) (bool, error)
 }
 
 func list(f feedService) api.HandlerFunc {
@@ -29,14 +68,23 @@ func list(f feedService) api.HandlerFunc {
 			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
 		}
 
-		posts, err := f.List(r.Context(), *user, listRequest.Limit, listRequest.Before, listRequest.After)
+		filter := domain.FeedFilter{
+			FellowshipId: listRequest.FellowshipId,
+			CircleId:     listRequest.CircleId,
+			AuthorId:     listRequest.AuthorId,
+			Kind:         listRequest.Kind,
+			PrayerStatus: listRequest.PrayerStatus,
+			Tag:          listRequest.Tag,
+		}
+
+		page, err := f.List(r.Context(), *user, filter, listRequest.Limit, listRequest.Cursor)
 		if err != nil {
 			return api.MapDomainError(err)
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 
-		if err := json.NewEncoder(w).Encode(posts); err != nil {
+		if err := json.NewEncoder(w).Encode(newListResponse(page)); err != nil {
 			return &api.Error{Code: http.StatusInternalServerError, Message: "failed to encode posts", Err: err}
 		}
 
@@ -44,6 +92,89 @@ func list(f feedService) api.HandlerFunc {
 	}
 }
 
+func get(f feedService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		filter, err := queryFilter(query)
+		if err != nil {
+			return err
+		}
+
+		page, err := f.List(r.Context(), *user, filter, limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, newListResponse(page), http.StatusOK)
+		return nil
+	}
+}
+
+func search(f postSearchService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		filter, err := queryFilter(query)
+		if err != nil {
+			return err
+		}
+
+		results, nextCursor, err := f.Search(r.Context(), *user, query.Get("q"), filter, limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, SearchResponse{Results: results, NextCursor: nextCursor}, http.StatusOK)
+		return nil
+	}
+}
+
+// queryFilter reads the feed filter parameters shared by the GET feed endpoints.
+func queryFilter(query url.Values) (domain.FeedFilter, error) {
+	var err error
+	filter := domain.FeedFilter{Kind: domain.PostKind(query.Get("kind")), Tag: query.Get("tag")}
+
+	if filter.FellowshipId, err = api.QueryUUID(query, "fellowshipId"); err != nil {
+		return filter, err
+	}
+
+	if filter.CircleId, err = api.QueryUUID(query, "circleId"); err != nil {
+		return filter, err
+	}
+
+	if filter.AuthorId, err = api.QueryUUID(query, "authorId"); err != nil {
+		return filter, err
+	}
+
+	if raw := query.Get("prayerStatus"); raw != "" {
+		status := domain.PrayerStatus(raw)
+		filter.PrayerStatus = &status
+	}
+
+	return filter, nil
+}
+
 func post(f feedService) api.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
@@ -56,12 +187,322 @@ func post(f feedService) api.HandlerFunc {
 			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
 		}
 
-		err := f.Post(r.Context(), *user, postRequest.FellowshipId, postRequest.CircleId, postRequest.Heading, postRequest.Article)
+		held, err := f.Post(r.Context(), *user, domain.PostInput{
+			FellowshipId:  postRequest.FellowshipId,
+			CircleId:      postRequest.CircleId,
+			Kind:          postRequest.Kind,
+			Heading:       postRequest.Heading,
+			Article:       postRequest.Article,
+			Format:        postRequest.Format,
+			Details:       postRequest.Details,
+			FollowUp:      postRequest.FollowUp,
+			Draft:         postRequest.Draft,
+			PublishAt:     postRequest.PublishAt,
+			AttachmentIDs: postRequest.Attachments,
+			Poll:          postRequest.Poll,
+		})
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		// Posts held for review are accepted but not shown until a moderator approves them.
+		if held {
+			w.WriteHeader(http.StatusAccepted)
+			return nil
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func edit(f postEditService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var editRequest EditRequest
+		if err := json.NewDecoder(r.Body).Decode(&editRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		held, err := f.Edit(r.Context(), *user, editRequest.PostId, domain.PostEdit{
+			Heading: editRequest.Heading,
+			Article: editRequest.Article,
+			Details: editRequest.Details,
+		})
 		if err != nil {
 			return api.MapDomainError(err)
 		}
 
+		// Edits held for review are saved but the post is hidden until a moderator approves it.
+		if held {
+			w.WriteHeader(http.StatusAccepted)
+			return nil
+		}
+
 		w.WriteHeader(http.StatusOK)
 		return nil
 	}
 }
+
+func deletePost(f postEditService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var deleteRequest PostIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&deleteRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := f.Delete(r.Context(), *user, deleteRequest.PostId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func revisions(f postEditService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		postId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		postRevisions, err := f.Revisions(r.Context(), *user, postId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, postRevisions, http.StatusOK)
+		return nil
+	}
+}
+
+func react(f reactionService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var reactRequest ReactRequest
+		if err := json.NewDecoder(r.Body).Decode(&reactRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		reacted, err := f.React(r.Context(), *user, reactRequest.PostId, reactRequest.Kind)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, ReactResponse{Reacted: reacted}, http.StatusOK)
+		return nil
+	}
+}
+
+func setPrayerState(f prayerService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var prayerStateRequest PrayerStateRequest
+		if err := json.NewDecoder(r.Body).Decode(&prayerStateRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := f.SetPrayerState(r.Context(), *user, prayerStateRequest.PostId, domain.PrayerState{
+			Status:    prayerStateRequest.Status,
+			Testimony: prayerStateRequest.Testimony,
+			FollowUp:  prayerStateRequest.FollowUp,
+		})
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func pin(f pinService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var pinRequest PinRequest
+		if err := json.NewDecoder(r.Body).Decode(&pinRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := f.Pin(r.Context(), *user, pinRequest.PostId, pinRequest.PinnedUntil, pinRequest.Urgent); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func unpin(f pinService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var postIdRequest PostIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&postIdRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := f.Unpin(r.Context(), *user, postIdRequest.PostId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func drafts(f draftService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		posts, err := f.Drafts(r.Context(), *user)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, posts, http.StatusOK)
+		return nil
+	}
+}
+
+func publish(f draftService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var publishRequest PublishRequest
+		if err := json.NewDecoder(r.Body).Decode(&publishRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		held, err := f.Publish(r.Context(), *user, publishRequest.PostId, publishRequest.PublishAt)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		// Drafts held for review are published but not shown until a moderator approves them.
+		if held {
+			w.WriteHeader(http.StatusAccepted)
+			return nil
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func markRead(f readService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var readRequest PostIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&readRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := f.MarkRead(r.Context(), *user, readRequest.PostId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func unread(f readService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		counts, err := f.Unread(r.Context(), *user)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, counts, http.StatusOK)
+		return nil
+	}
+}
+
+func vote(p pollService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var voteRequest VoteRequest
+		if err := json.NewDecoder(r.Body).Decode(&voteRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := p.Vote(r.Context(), *user, voteRequest.PostId, voteRequest.Options); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func pollResults(p pollService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		postId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		results, err := p.Results(r.Context(), *user, postId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, results, http.StatusOK)
+		return nil
+	}
+}
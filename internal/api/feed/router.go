@@ -7,19 +7,33 @@ import (
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
 )
 
-func NewRouter(feedService *service.FeedService) *Router {
-	return &Router{feedService: feedService}
+func NewRouter(feedService *service.FeedService, readService *service.ReadService, pollService *service.PollService) *Router {
+	return &Router{feedService: feedService, readService: readService, pollService: pollService}
 }
 
 type Router struct {
 	feedService *service.FeedService
+	readService *service.ReadService
+	pollService *service.PollService
 }
 
 func (r *Router) Routes() []api.Route {
-	listLimit := api.WithBodyLimit(512)
+	listLimit := api.WithBodyLimit(1024)
 	postLimit := api.WithBodyLimit(65536)
+	postIdLimit := api.WithBodyLimit(512)
+	voteLimit := api.WithBodyLimit(1024)
 
 	return []api.Route{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed",
+			Handler: get(r.feedService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed/search",
+			Handler: search(r.feedService),
+		},
 		{
 			Method:  http.MethodPost,
 			Pattern: "/api/feed/list",
@@ -30,5 +44,70 @@ func (r *Router) Routes() []api.Route {
 			Pattern: "/api/feed/post",
 			Handler: postLimit(http.MethodPost, "/api/feed/post", post(r.feedService)),
 		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/edit",
+			Handler: postLimit(http.MethodPost, "/api/feed/edit", edit(r.feedService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/delete",
+			Handler: postIdLimit(http.MethodPost, "/api/feed/delete", deletePost(r.feedService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed/{id}/revisions",
+			Handler: revisions(r.feedService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed/drafts",
+			Handler: drafts(r.feedService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/publish",
+			Handler: postIdLimit(http.MethodPost, "/api/feed/publish", publish(r.feedService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/pin",
+			Handler: postIdLimit(http.MethodPost, "/api/feed/pin", pin(r.feedService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/unpin",
+			Handler: postIdLimit(http.MethodPost, "/api/feed/unpin", unpin(r.feedService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/prayerstatus",
+			Handler: postLimit(http.MethodPost, "/api/feed/prayerstatus", setPrayerState(r.feedService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/react",
+			Handler: postIdLimit(http.MethodPost, "/api/feed/react", react(r.feedService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/read",
+			Handler: postIdLimit(http.MethodPost, "/api/feed/read", markRead(r.readService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed/unread",
+			Handler: unread(r.readService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/feed/vote",
+			Handler: voteLimit(http.MethodPost, "/api/feed/vote", vote(r.pollService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/feed/{id}/poll",
+			Handler: pollResults(r.pollService),
+		},
 	}
 }
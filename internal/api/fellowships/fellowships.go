@@ -1 +1,43 @@
 package fellowships
+
+import "github.com/google/uuid"
+
+// PublicFellowship is what anonymous search shows of a fellowship. It leaves out its creator and
+// its place in the fellowship tree.
+type PublicFellowship struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+}
+
+type SearchResponse struct {
+	Fellowships []PublicFellowship `json:"fellowships"`
+	NextCursor  string             `json:"nextCursor,omitempty"`
+}
+
+type SetParentRequest struct {
+	FellowshipId uuid.UUID  `json:"fellowshipId"`
+	ParentId     *uuid.UUID `json:"parentId"`
+}
+
+type SetTimezoneRequest struct {
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	Timezone     string    `json:"timezone"`
+}
+
+type SetDetailsRequest struct {
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	Description  string    `json:"description"`
+	IsPublic     bool      `json:"isPublic"`
+}
+
+type WorshipLeaderRequest struct {
+	FellowshipId  uuid.UUID `json:"fellowshipId"`
+	UserId        uuid.UUID `json:"userId"`
+	WorshipLeader bool      `json:"worshipLeader"`
+}
+
+type FollowParentNoticesRequest struct {
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	Follow       bool      `json:"follow"`
+}
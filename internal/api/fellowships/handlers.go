@@ -8,10 +8,28 @@ import (
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
 )
 
 type fellowshipService interface {
 	List(ctx context.Context, user domain.User) ([]domain.Fellowship, error)
+	Get(ctx context.Context, user domain.User, fellowshipId uuid.UUID) (*domain.Fellowship, error)
+}
+
+type fellowshipHierarchyService interface {
+	Children(ctx context.Context, user domain.User, fellowshipId uuid.UUID) ([]domain.Fellowship, error)
+	SetParent(ctx context.Context, user domain.User, fellowshipId uuid.UUID, parentId *uuid.UUID) error
+	FollowParentNotices(ctx context.Context, user domain.User, fellowshipId uuid.UUID, follow bool) error
+}
+
+type fellowshipSettingsService interface {
+	SetTimezone(ctx context.Context, user domain.User, fellowshipId uuid.UUID, timezone string) error
+	SetDetails(ctx context.Context, user domain.User, fellowshipId uuid.UUID, description string, isPublic bool) error
+	SetWorshipLeader(ctx context.Context, user domain.User, fellowshipId uuid.UUID, userId uuid.UUID, leader bool) error
+}
+
+type fellowshipSearchService interface {
+	Search(ctx context.Context, query string, limit *int, cursor string) ([]domain.Fellowship, string, error)
 }
 
 func list(f fellowshipService) api.HandlerFunc {
@@ -35,3 +53,181 @@ func list(f fellowshipService) api.HandlerFunc {
 		return nil
 	}
 }
+
+func get(f fellowshipService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		fellowshipId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		fellowship, err := f.Get(r.Context(), *user, fellowshipId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, fellowship, http.StatusOK)
+		return nil
+	}
+}
+
+func getChildren(f fellowshipHierarchyService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		fellowshipId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		fellowships, err := f.Children(r.Context(), *user, fellowshipId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, fellowships, http.StatusOK)
+		return nil
+	}
+}
+
+func search(f fellowshipSearchService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		query := r.URL.Query()
+
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		fellowships, nextCursor, err := f.Search(r.Context(), query.Get("q"), limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		results := make([]PublicFellowship, 0, len(fellowships))
+		for _, fellowship := range fellowships {
+			results = append(results, PublicFellowship{Id: fellowship.Id, Name: fellowship.Name, Description: fellowship.Description})
+		}
+
+		api.RespondJSON(w, SearchResponse{Fellowships: results, NextCursor: nextCursor}, http.StatusOK)
+		return nil
+	}
+}
+
+func setParent(f fellowshipHierarchyService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var setParentRequest SetParentRequest
+		if err := json.NewDecoder(r.Body).Decode(&setParentRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := f.SetParent(r.Context(), *user, setParentRequest.FellowshipId, setParentRequest.ParentId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func setTimezone(f fellowshipSettingsService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var setTimezoneRequest SetTimezoneRequest
+		if err := json.NewDecoder(r.Body).Decode(&setTimezoneRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := f.SetTimezone(r.Context(), *user, setTimezoneRequest.FellowshipId, setTimezoneRequest.Timezone)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func setDetails(f fellowshipSettingsService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var setDetailsRequest SetDetailsRequest
+		if err := json.NewDecoder(r.Body).Decode(&setDetailsRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := f.SetDetails(r.Context(), *user, setDetailsRequest.FellowshipId, setDetailsRequest.Description, setDetailsRequest.IsPublic)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func setWorshipLeader(f fellowshipSettingsService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var worshipLeaderRequest WorshipLeaderRequest
+		if err := json.NewDecoder(r.Body).Decode(&worshipLeaderRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := f.SetWorshipLeader(r.Context(), *user, worshipLeaderRequest.FellowshipId, worshipLeaderRequest.UserId, worshipLeaderRequest.WorshipLeader)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func followParentNotices(f fellowshipHierarchyService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var followRequest FollowParentNoticesRequest
+		if err := json.NewDecoder(r.Body).Decode(&followRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		err := f.FollowParentNotices(r.Context(), *user, followRequest.FellowshipId, followRequest.Follow)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
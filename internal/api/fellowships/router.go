@@ -2,6 +2,7 @@ package fellowships
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
@@ -17,12 +18,61 @@ type Router struct {
 
 func (r *Router) Routes() []api.Route {
 	listLimit := api.WithBodyLimit(512)
+	manageLimit := api.WithBodyLimit(512)
+	detailsLimit := api.WithBodyLimit(8 << 10)
+	searchRateLimit := api.RateLimitMiddleware(30, 1*time.Minute)
 
 	return []api.Route{
 		{
-			Method:   http.MethodPost,
-			Pattern:  "/api/fellowships/list",
-			Handler:  listLimit(http.MethodPost, "/api/fellowships/list", list(r.fellowshipService)),
+			Method:  http.MethodGet,
+			Pattern: "/api/fellowships",
+			Handler: list(r.fellowshipService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/fellowships/{id}",
+			Handler: get(r.fellowshipService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/fellowships/{id}/children",
+			Handler: getChildren(r.fellowshipService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/fellowships/list",
+			Handler: listLimit(http.MethodPost, "/api/fellowships/list", list(r.fellowshipService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/fellowships/setparent",
+			Handler: manageLimit(http.MethodPost, "/api/fellowships/setparent", setParent(r.fellowshipService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/fellowships/settimezone",
+			Handler: manageLimit(http.MethodPost, "/api/fellowships/settimezone", setTimezone(r.fellowshipService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/fellowships/setdetails",
+			Handler: detailsLimit(http.MethodPost, "/api/fellowships/setdetails", setDetails(r.fellowshipService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/fellowships/worshipleader",
+			Handler: manageLimit(http.MethodPost, "/api/fellowships/worshipleader", setWorshipLeader(r.fellowshipService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/fellowships/followparentnotices",
+			Handler: manageLimit(http.MethodPost, "/api/fellowships/followparentnotices", followParentNotices(r.fellowshipService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/fellowships/search",
+			Handler: searchRateLimit(http.MethodGet, "/api/fellowships/search", search(r.fellowshipService)),
+			Public:  true,
 		},
 	}
 }
@@ -4,6 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Handler interface {
@@ -16,17 +21,69 @@ func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) error {
 	return h(w, r)
 }
 
-func DiscardError(m string, h Handler) http.Handler {
+// DiscardError adapts h to http.Handler. Routes are registered with method patterns, so the
+// ServeMux has already matched the method and answers other methods with 405 Method Not Allowed.
+func DiscardError(h Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != m {
-			http.NotFound(w, r)
-			return
-		}
-
 		_ = h.ServeHTTP(w, r)
 	})
 }
 
+// QueryInt parses an optional integer query parameter, returning nil when it is absent.
+func QueryInt(query url.Values, key string) (*int, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, &Error{Code: http.StatusBadRequest, Message: "invalid " + key, Err: err}
+	}
+
+	return &n, nil
+}
+
+// QueryUUID parses an optional UUID query parameter, returning uuid.Nil when it is absent.
+func QueryUUID(query url.Values, key string) (uuid.UUID, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, &Error{Code: http.StatusBadRequest, Message: "invalid " + key, Err: err}
+	}
+
+	return id, nil
+}
+
+// QueryTime parses an optional RFC 3339 time query parameter, returning the zero time when it is absent.
+func QueryTime(query url.Values, key string) (time.Time, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, &Error{Code: http.StatusBadRequest, Message: "invalid " + key, Err: err}
+	}
+
+	return t, nil
+}
+
+// PathUUID parses a UUID path value of a route pattern such as /api/fellowships/{id}.
+func PathUUID(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		return uuid.Nil, &Error{Code: http.StatusBadRequest, Message: "invalid " + name, Err: err}
+	}
+
+	return id, nil
+}
+
 type Error struct {
 	Code      int    `json:"code"`
 	ErrorCode string `json:"errorCode,omitempty"`
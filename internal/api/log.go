@@ -4,9 +4,13 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// redacted replaces secrets in logged URLs.
+const redacted = "REDACTED"
+
 // WithLog middleware logs request details
 func WithLog(logger *slog.Logger) MiddlewareFunc {
 	return func(method, pattern string, h Handler) Handler {
@@ -18,7 +22,7 @@ func WithLog(logger *slog.Logger) MiddlewareFunc {
 				logger.Error("request failed",
 					slog.String("method", method),
 					slog.String("pattern", pattern),
-					slog.String("url", r.RequestURI),
+					slog.String("url", redactedURL(r)),
 					slog.Duration("duration", time.Since(start)),
 					slog.Any("error", err),
 				)
@@ -28,7 +32,7 @@ func WithLog(logger *slog.Logger) MiddlewareFunc {
 			logger.Info("request succeeded",
 				slog.String("method", method),
 				slog.String("pattern", pattern),
-				slog.String("url", r.RequestURI),
+				slog.String("url", redactedURL(r)),
 				slog.Duration("duration", time.Since(start)),
 			)
 			return err
@@ -36,6 +40,23 @@ func WithLog(logger *slog.Logger) MiddlewareFunc {
 	}
 }
 
+// redactedURL returns the request URI with the tokens it can carry, in a {token} path segment or a
+// token query parameter, replaced so that the logs cannot be used in their place.
+func redactedURL(r *http.Request) string {
+	u := *r.URL
+	if token := r.PathValue("token"); token != "" {
+		u.Path = strings.Replace(u.Path, token, redacted, 1)
+		u.RawPath = ""
+	}
+
+	if query := u.Query(); query.Has("token") {
+		query.Set("token", redacted)
+		u.RawQuery = query.Encode()
+	}
+
+	return u.RequestURI()
+}
+
 // WithHTTPErrStatus middleware handles HTTP error responses
 func WithHTTPErrStatus(method, pattern string, h Handler) Handler {
 	return HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
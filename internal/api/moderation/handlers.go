@@ -0,0 +1,164 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type moderationService interface {
+	Report(ctx context.Context, user domain.User, postId uuid.UUID, commentId *uuid.UUID, reason string) error
+	Reports(ctx context.Context, user domain.User, fellowshipId uuid.UUID, limit *int, cursor string) ([]domain.Report, string, error)
+	Act(ctx context.Context, user domain.User, reportId uuid.UUID, action domain.ModerationAction, note string) error
+	Log(ctx context.Context, user domain.User, fellowshipId uuid.UUID, limit *int, cursor string) ([]domain.ModerationLogEntry, string, error)
+	BlockedWords(ctx context.Context, user domain.User, fellowshipId uuid.UUID) ([]domain.BlockedWord, error)
+	SetBlockedWords(ctx context.Context, user domain.User, fellowshipId uuid.UUID, words []domain.BlockedWord) error
+}
+
+func report(m moderationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var reportRequest ReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&reportRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := m.Report(r.Context(), *user, reportRequest.PostId, reportRequest.CommentId, reportRequest.Reason); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func reports(m moderationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		fellowshipId, err := api.QueryUUID(query, "fellowshipId")
+		if err != nil {
+			return err
+		}
+
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		reports, nextCursor, err := m.Reports(r.Context(), *user, fellowshipId, limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, ReportsResponse{Reports: reports, NextCursor: nextCursor}, http.StatusOK)
+		return nil
+	}
+}
+
+func act(m moderationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var actionRequest ActionRequest
+		if err := json.NewDecoder(r.Body).Decode(&actionRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := m.Act(r.Context(), *user, actionRequest.ReportId, actionRequest.Action, actionRequest.Note); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func moderationLog(m moderationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		fellowshipId, err := api.QueryUUID(query, "fellowshipId")
+		if err != nil {
+			return err
+		}
+
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		entries, nextCursor, err := m.Log(r.Context(), *user, fellowshipId, limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, LogResponse{Entries: entries, NextCursor: nextCursor}, http.StatusOK)
+		return nil
+	}
+}
+
+func blockedWords(m moderationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		fellowshipId, err := api.QueryUUID(r.URL.Query(), "fellowshipId")
+		if err != nil {
+			return err
+		}
+
+		words, err := m.BlockedWords(r.Context(), *user, fellowshipId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, words, http.StatusOK)
+		return nil
+	}
+}
+
+func setBlockedWords(m moderationService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var blockedWordsRequest BlockedWordsRequest
+		if err := json.NewDecoder(r.Body).Decode(&blockedWordsRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := m.SetBlockedWords(r.Context(), *user, blockedWordsRequest.FellowshipId, blockedWordsRequest.Words); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package moderation
+
+import (
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ReportRequest struct {
+	PostId    uuid.UUID  `json:"postId"`
+	CommentId *uuid.UUID `json:"commentId"`
+	Reason    string     `json:"reason"`
+}
+
+type ReportsResponse struct {
+	Reports    []domain.Report `json:"reports"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+type ActionRequest struct {
+	ReportId uuid.UUID               `json:"reportId"`
+	Action   domain.ModerationAction `json:"action"`
+	Note     string                  `json:"note"`
+}
+
+type LogResponse struct {
+	Entries    []domain.ModerationLogEntry `json:"entries"`
+	NextCursor string                      `json:"nextCursor,omitempty"`
+}
+
+type BlockedWordsRequest struct {
+	FellowshipId uuid.UUID            `json:"fellowshipId"`
+	Words        []domain.BlockedWord `json:"words"`
+}
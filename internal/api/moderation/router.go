@@ -0,0 +1,54 @@
+package moderation
+
+import (
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(moderationService *service.ModerationService) *Router {
+	return &Router{moderationService: moderationService}
+}
+
+type Router struct {
+	moderationService *service.ModerationService
+}
+
+func (r *Router) Routes() []api.Route {
+	reportLimit := api.WithBodyLimit(8192)
+	blockedWordsLimit := api.WithBodyLimit(131072)
+
+	return []api.Route{
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/moderation/report",
+			Handler: reportLimit(http.MethodPost, "/api/moderation/report", report(r.moderationService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/moderation/reports",
+			Handler: reports(r.moderationService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/moderation/action",
+			Handler: reportLimit(http.MethodPost, "/api/moderation/action", act(r.moderationService)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/moderation/log",
+			Handler: moderationLog(r.moderationService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/moderation/blockedwords",
+			Handler: blockedWords(r.moderationService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/moderation/blockedwords",
+			Handler: blockedWordsLimit(http.MethodPost, "/api/moderation/blockedwords", setBlockedWords(r.moderationService)),
+		},
+	}
+}
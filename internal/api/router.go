@@ -1,9 +1,14 @@
 package api
 
+// Route is served for requests matching both Method and Pattern. Patterns follow http.ServeMux and
+// may hold path values such as /api/fellowships/{id}; several routes may share a pattern with
+// different methods.
 type Route struct {
 	Method  string
 	Pattern string
 	Handler Handler
+	// Public routes are served without the server's route middleware (authentication).
+	Public bool
 }
 
 type Router interface {
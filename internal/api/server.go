@@ -99,12 +99,32 @@ func (s *Server) setupHandlers(mux *http.ServeMux) {
 		MetricsMiddleware,
 		WithHTTPErrStatus,
 	}
-	allMiddlewares = append(allMiddlewares, s.middleware...)
 
-	middlewareFunc := ChainMiddleware(allMiddlewares...)
+	publicMiddlewareFunc := ChainMiddleware(allMiddlewares...)
+	middlewareFunc := ChainMiddleware(append(allMiddlewares, s.middleware...)...)
+
+	preflightPatterns := make(map[string]struct{})
 
 	for _, rt := range s.router.Routes() {
-		handler := middlewareFunc(rt.Method, rt.Pattern, rt.Handler)
-		mux.Handle(rt.Pattern, DiscardError(rt.Method, handler))
+		var handler Handler
+		if rt.Public {
+			handler = publicMiddlewareFunc(rt.Method, rt.Pattern, rt.Handler)
+		} else {
+			handler = middlewareFunc(rt.Method, rt.Pattern, rt.Handler)
+		}
+
+		mux.Handle(rt.Method+" "+rt.Pattern, DiscardError(handler))
+
+		// CORS preflight requests carry no credentials, so answer them once per pattern outside authentication.
+		if _, ok := preflightPatterns[rt.Pattern]; !ok {
+			preflightPatterns[rt.Pattern] = struct{}{}
+			mux.Handle(http.MethodOptions+" "+rt.Pattern, DiscardError(publicMiddlewareFunc(http.MethodOptions, rt.Pattern, HandlerFunc(preflight))))
+		}
 	}
 }
+
+// preflight is reached only if no middleware answered the OPTIONS request.
+func preflight(w http.ResponseWriter, r *http.Request) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
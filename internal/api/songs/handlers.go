@@ -0,0 +1,142 @@
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api/contextkeys"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+type songService interface {
+	Create(ctx context.Context, user domain.User, input domain.SongInput) (*domain.Song, error)
+	Get(ctx context.Context, user domain.User, songId uuid.UUID) (*domain.Song, error)
+	Search(ctx context.Context, user domain.User, fellowshipId uuid.UUID, filter domain.SongFilter, limit *int, cursor string) ([]domain.Song, string, error)
+	Edit(ctx context.Context, user domain.User, songId uuid.UUID, input domain.SongInput) (*domain.Song, error)
+	Delete(ctx context.Context, user domain.User, songId uuid.UUID) error
+}
+
+func (s SongRequest) input() domain.SongInput {
+	return domain.SongInput{FellowshipId: s.FellowshipId, Title: s.Title, Authors: s.Authors, CCLINumber: s.CCLINumber, Copyright: s.Copyright,
+		DefaultKey: s.DefaultKey, Tempo: s.Tempo, TimeSignature: s.TimeSignature, Themes: s.Themes, Lyrics: s.Lyrics}
+}
+
+func create(s songService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var songRequest SongRequest
+		if err := json.NewDecoder(r.Body).Decode(&songRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		song, err := s.Create(r.Context(), *user, songRequest.input())
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, song, http.StatusCreated)
+		return nil
+	}
+}
+
+func get(s songService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		songId, err := api.PathUUID(r, "id")
+		if err != nil {
+			return err
+		}
+
+		song, err := s.Get(r.Context(), *user, songId)
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, song, http.StatusOK)
+		return nil
+	}
+}
+
+func search(s songService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		query := r.URL.Query()
+
+		fellowshipId, err := api.QueryUUID(query, "fellowshipId")
+		if err != nil {
+			return err
+		}
+
+		limit, err := api.QueryInt(query, "limit")
+		if err != nil {
+			return err
+		}
+
+		filter := domain.SongFilter{Query: query.Get("q"), Theme: query.Get("theme")}
+		songs, nextCursor, err := s.Search(r.Context(), *user, fellowshipId, filter, limit, query.Get("cursor"))
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, SearchResponse{Songs: songs, NextCursor: nextCursor}, http.StatusOK)
+		return nil
+	}
+}
+
+func edit(s songService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var editRequest EditSongRequest
+		if err := json.NewDecoder(r.Body).Decode(&editRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		song, err := s.Edit(r.Context(), *user, editRequest.SongId, editRequest.input())
+		if err != nil {
+			return api.MapDomainError(err)
+		}
+
+		api.RespondJSON(w, song, http.StatusOK)
+		return nil
+	}
+}
+
+func deleteSong(s songService) api.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := r.Context().Value(contextkeys.UserKey).(*domain.User)
+		if !ok {
+			return &api.Error{Code: http.StatusUnauthorized, Message: "user not authorised", Err: api.ErrorUnauthorized}
+		}
+
+		var deleteRequest SongIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&deleteRequest); err != nil {
+			return &api.Error{Code: http.StatusBadRequest, Message: "json unmarshal failed", Err: err}
+		}
+
+		if err := s.Delete(r.Context(), *user, deleteRequest.SongId); err != nil {
+			return api.MapDomainError(err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package songs
+
+import (
+	"net/http"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/api"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/service"
+)
+
+func NewRouter(songService *service.SongService) *Router {
+	return &Router{songService: songService}
+}
+
+type Router struct {
+	songService *service.SongService
+}
+
+func (r *Router) Routes() []api.Route {
+	songLimit := api.WithBodyLimit(32768)
+	songIdLimit := api.WithBodyLimit(512)
+
+	return []api.Route{
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/songs/search",
+			Handler: search(r.songService),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/api/songs/{id}",
+			Handler: get(r.songService),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/songs/create",
+			Handler: songLimit(http.MethodPost, "/api/songs/create", create(r.songService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/songs/edit",
+			Handler: songLimit(http.MethodPost, "/api/songs/edit", edit(r.songService)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: "/api/songs/delete",
+			Handler: songIdLimit(http.MethodPost, "/api/songs/delete", deleteSong(r.songService)),
+		},
+	}
+}
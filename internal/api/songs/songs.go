@@ -0,0 +1,35 @@
+package songs
+
+import (
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SongRequest describes a song. Everything but the title is optional. DefaultKey is written like G,
+// Bb or F#m, Tempo is in beats per minute and TimeSignature is written like 4/4 or 6/8.
+type SongRequest struct {
+	FellowshipId  uuid.UUID `json:"fellowshipId"`
+	Title         string    `json:"title"`
+	Authors       []string  `json:"authors"`
+	CCLINumber    string    `json:"ccliNumber"`
+	Copyright     string    `json:"copyright"`
+	DefaultKey    string    `json:"defaultKey"`
+	Tempo         int       `json:"tempo"`
+	TimeSignature string    `json:"timeSignature"`
+	Themes        []string  `json:"themes"`
+	Lyrics        string    `json:"lyrics"`
+}
+
+type EditSongRequest struct {
+	SongId uuid.UUID `json:"songId"`
+	SongRequest
+}
+
+type SongIdRequest struct {
+	SongId uuid.UUID `json:"songId"`
+}
+
+type SearchResponse struct {
+	Songs      []domain.Song `json:"songs"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
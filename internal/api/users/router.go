@@ -28,13 +28,15 @@ func (r *Router) Routes() []api.Route {
 			Pattern: "/api/user/login",
 			Handler: authRateLimit(http.MethodPost, "/api/user/login",
 				loginLimit(http.MethodPost, "/api/user/login", loginHandler(r.userService))),
+			Public: true,
 		},
 		{
 			Method:  http.MethodPost,
 			Pattern: "/api/user/register",
 			Handler: authRateLimit(http.MethodPost, "/api/user/register",
 				registerLimit(http.MethodPost, "/api/user/register", registerUserHandler(r.userService))),
+			Public: true,
 		},
-		{Method: http.MethodGet, Pattern: "/api/user/verifyemail", Handler: verifyEmailHandler(r.userService)},
+		{Method: http.MethodGet, Pattern: "/api/user/verifyemail", Handler: verifyEmailHandler(r.userService), Public: true},
 	}
 }
@@ -0,0 +1,38 @@
+// Package blob provides the stores that keep attachment content.
+package blob
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/config"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// keyPattern restricts keys to names that are safe as file names and URL path segments.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// New returns the blob store selected by config.
+func New(config config.StorageConfig) (domain.BlobStore, error) {
+	switch config.GetStorageBackend() {
+	case BackendLocal:
+		return NewLocalStore(config.GetStoragePath())
+	case BackendS3:
+		return NewS3Store(config.GetS3Endpoint(), config.GetS3Region(), config.GetS3Bucket(), config.GetS3AccessKey(), config.GetS3SecretKey())
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.GetStorageBackend())
+	}
+}
+
+func validateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{key: "3f2b9c1e-4d5a-4b8e-9f0a-1c2d3e4f5a6b", valid: true},
+		{key: "photo.web.jpg", valid: true},
+		{key: "a", valid: true},
+		{key: strings.Repeat("a", 128), valid: true},
+		{key: strings.Repeat("a", 129), valid: false},
+		{key: "", valid: false},
+		{key: ".", valid: false},
+		{key: "..", valid: false},
+		{key: ".hidden", valid: false},
+		{key: "-flag", valid: false},
+		{key: "../secret", valid: false},
+		{key: "a/../../secret", valid: false},
+		{key: "a/b", valid: false},
+		{key: `a\b`, valid: false},
+		{key: "/etc/passwd", valid: false},
+		{key: "a b", valid: false},
+		{key: "a%2f", valid: false},
+		{key: "photo.jpg\n", valid: false},
+		{key: "é", valid: false},
+	}
+
+	for _, test := range tests {
+		if err := validateKey(test.key); (err == nil) != test.valid {
+			t.Errorf("validateKey(%q) = %v, want valid %t", test.key, err, test.valid)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(root, "blobs"))
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	ctx := context.Background()
+	content := "image bytes"
+
+	if err := store.Put(ctx, "photo.jpg", strings.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "blobs", "pg", "photo.jpg")); err != nil {
+		t.Errorf("blob is not in its shard directory: %v", err)
+	}
+
+	body, err := store.Get(ctx, "photo.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+
+	if string(got) != content {
+		t.Errorf("Get = %q, want %q", got, content)
+	}
+
+	if err := store.Put(ctx, "short.jpg", strings.NewReader(content), int64(len(content))+1, "image/jpeg"); err == nil {
+		t.Error("Put of fewer bytes than the size succeeded")
+	}
+
+	if _, err := store.Get(ctx, "short.jpg"); !errors.Is(err, domain.ErrBlobNotFound) {
+		t.Errorf("Get after a failed Put = %v, want ErrBlobNotFound", err)
+	}
+
+	if err := store.Delete(ctx, "photo.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := store.Delete(ctx, "photo.jpg"); err != nil {
+		t.Errorf("Delete of a missing blob = %v, want nil", err)
+	}
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(root, "blobs"))
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	secret := filepath.Join(root, "secret")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	for _, key := range []string{"../secret", "../../secret", "..", "ab/../../secret", secret} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+
+		if body, err := store.Get(ctx, key); err == nil {
+			body.Close()
+			t.Errorf("Get(%q) succeeded", key)
+		}
+
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded", key)
+		}
+	}
+
+	if content, err := os.ReadFile(secret); err != nil || string(content) != "secret" {
+		t.Errorf("file outside the store changed: %q, %v", content, err)
+	}
+}
@@ -0,0 +1,192 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "eu-west-1"
+)
+
+// canonicalRequest is the SigV4 canonical request of a request as S3 receives it.
+func canonicalRequest(r *http.Request, host string) string {
+	return r.Method + "\n" +
+		r.URL.EscapedPath() + "\n" +
+		"\n" +
+		"host:" + host + "\n" +
+		"x-amz-content-sha256:" + r.Header.Get("X-Amz-Content-Sha256") + "\n" +
+		"x-amz-date:" + r.Header.Get("X-Amz-Date") + "\n" +
+		"\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		r.Header.Get("X-Amz-Content-Sha256")
+}
+
+// authorization is the SigV4 Authorization header S3 expects for a canonical request.
+func authorization(canonical, amzDate string) string {
+	date := amzDate[:8]
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date, testRegion, "s3", "aws4_request", "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+
+	return "AWS4-HMAC-SHA256 Credential=" + testAccessKey + "/" + scope + ", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=" + hex.EncodeToString(key)
+}
+
+func TestS3StoreSign(t *testing.T) {
+	store, err := NewS3Store("http://localhost:9000/minio/", testRegion, "attachments", testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+
+	req, err := store.newRequest(context.Background(), http.MethodPut, "abc-1.jpg", nil)
+	if err != nil {
+		t.Fatalf("newRequest: %v", err)
+	}
+
+	store.sign(req, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	if got, want := req.URL.String(), "http://localhost:9000/minio/attachments/abc-1.jpg"; got != want {
+		t.Errorf("URL = %s, want %s", got, want)
+	}
+
+	wantCanonical := "PUT\n/minio/attachments/abc-1.jpg\n\nhost:localhost:9000\nx-amz-content-sha256:UNSIGNED-PAYLOAD\nx-amz-date:20260301T120000Z\n\nhost;x-amz-content-sha256;x-amz-date\nUNSIGNED-PAYLOAD"
+	if got := canonicalRequest(req, req.URL.Host); got != wantCanonical {
+		t.Errorf("canonical request = %q, want %q", got, wantCanonical)
+	}
+
+	// Worked out independently of the store for the canonical request above.
+	const want = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260301/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=f2180b98fd3067a75491ad0e754f0d878301d0375522329aa14596bd5eec18fd"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %s, want %s", got, want)
+	}
+}
+
+// fakeS3 is an S3 stand-in for one bucket that checks each request's path style and signature.
+type fakeS3 struct {
+	t      *testing.T
+	bucket string
+	mu     sync.Mutex
+	blobs  map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if got, want := r.Header.Get("Authorization"), authorization(canonicalRequest(r, r.Host), r.Header.Get("X-Amz-Date")); got != want {
+		f.t.Errorf("%s %s: Authorization = %s, want %s", r.Method, r.URL.Path, got, want)
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok {
+		f.t.Errorf("%s %s: not a path-style request for bucket %s", r.Method, r.URL.Path, f.bucket)
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.blobs[key] = string(body)
+	case http.MethodGet:
+		body, ok := f.blobs[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, body)
+	case http.MethodDelete:
+		if _, ok := f.blobs[key]; !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		delete(f.blobs, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{t: t, bucket: "attachments", blobs: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(server.URL, testRegion, "attachments", testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+
+	ctx := context.Background()
+	content := "image bytes"
+
+	if err := store.Put(ctx, "photo.jpg", strings.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, err := store.Get(ctx, "photo.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+
+	if string(got) != content {
+		t.Errorf("Get = %q, want %q", got, content)
+	}
+
+	if err := store.Delete(ctx, "photo.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := store.Get(ctx, "photo.jpg"); !errors.Is(err, domain.ErrBlobNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrBlobNotFound", err)
+	}
+
+	if err := store.Delete(ctx, "photo.jpg"); err != nil {
+		t.Errorf("Delete of a missing blob = %v, want nil", err)
+	}
+
+	if err := store.Put(ctx, "../photo.jpg", strings.NewReader(content), int64(len(content)), "image/jpeg"); err == nil {
+		t.Error("Put with a traversal key succeeded")
+	}
+}
+
+func TestS3StoreReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store, err := NewS3Store(server.URL, testRegion, "attachments", testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+
+	err = store.Put(context.Background(), "photo.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	if err == nil || errors.Is(err, domain.ErrBlobNotFound) || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put = %v, want the AccessDenied response", err)
+	}
+}
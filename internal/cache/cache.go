@@ -47,3 +47,9 @@ func (c *Cache[K, V]) Delete(key K) {
 	delete(c.entries, key)
 	c.mu.Unlock()
 }
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+}
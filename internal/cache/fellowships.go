@@ -50,14 +50,50 @@ func (s *FellowshipStore) GetUserFellowshipIDs(ctx context.Context, userId uuid.
 	return ids, nil
 }
 
+func (s *FellowshipStore) GetFellowship(ctx context.Context, fellowshipId uuid.UUID) (*domain.Fellowship, error) {
+	return s.inner.GetFellowship(ctx, fellowshipId)
+}
+
+func (s *FellowshipStore) GetChildFellowships(ctx context.Context, parentId uuid.UUID) ([]domain.Fellowship, error) {
+	return s.inner.GetChildFellowships(ctx, parentId)
+}
+
+func (s *FellowshipStore) GetFellowshipAncestorIDs(ctx context.Context, fellowshipId uuid.UUID) ([]uuid.UUID, error) {
+	return s.inner.GetFellowshipAncestorIDs(ctx, fellowshipId)
+}
+
+func (s *FellowshipStore) GetFellowshipSubtreeHeight(ctx context.Context, fellowshipId uuid.UUID) (int, error) {
+	return s.inner.GetFellowshipSubtreeHeight(ctx, fellowshipId)
+}
+
 func (s *FellowshipStore) GetUserAccessLevel(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (domain.AccessLevel, error) {
 	return s.inner.GetUserAccessLevel(ctx, userId, fellowshipId)
 }
 
+func (s *FellowshipStore) GetModeratedFellowshipIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	return s.inner.GetModeratedFellowshipIDs(ctx, userId)
+}
+
 func (s *FellowshipStore) GetFellowshipMembers(ctx context.Context, fellowshipId uuid.UUID) ([]domain.FellowshipMember, error) {
 	return s.inner.GetFellowshipMembers(ctx, fellowshipId)
 }
 
+func (s *FellowshipStore) GetFellowshipMembersNamed(ctx context.Context, fellowshipId uuid.UUID, names []string) ([]domain.User, error) {
+	return s.inner.GetFellowshipMembersNamed(ctx, fellowshipId, names)
+}
+
+func (s *FellowshipStore) GetMemberJoined(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (time.Time, error) {
+	return s.inner.GetMemberJoined(ctx, userId, fellowshipId)
+}
+
+func (s *FellowshipStore) IsWorshipLeader(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (bool, error) {
+	return s.inner.IsWorshipLeader(ctx, userId, fellowshipId)
+}
+
+func (s *FellowshipStore) SearchPublicFellowships(ctx context.Context, query string, limit *int, cursor *domain.FellowshipSearchCursor) ([]domain.Fellowship, *domain.FellowshipSearchCursor, error) {
+	return s.inner.SearchPublicFellowships(ctx, query, limit, cursor)
+}
+
 func (s *FellowshipStore) CreateFellowship(ctx context.Context, fellowship domain.Fellowship) error {
 	return s.inner.CreateFellowship(ctx, fellowship)
 }
@@ -65,3 +101,62 @@ func (s *FellowshipStore) CreateFellowship(ctx context.Context, fellowship domai
 func (s *FellowshipStore) AddFellowshipMember(ctx context.Context, member domain.FellowshipMember) error {
 	return s.inner.AddFellowshipMember(ctx, member)
 }
+
+func (s *FellowshipStore) RemoveFellowshipMember(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID) error {
+	if err := s.inner.RemoveFellowshipMember(ctx, fellowshipId, userId); err != nil {
+		return err
+	}
+
+	s.fellowshipsCache.Delete(userId)
+	s.fellowshipIDsCache.Delete(userId)
+	return nil
+}
+
+func (s *FellowshipStore) SetFellowshipParent(ctx context.Context, fellowshipId uuid.UUID, parentId *uuid.UUID) error {
+	if err := s.inner.SetFellowshipParent(ctx, fellowshipId, parentId); err != nil {
+		return err
+	}
+
+	s.invalidateFellowship(ctx, fellowshipId)
+	return nil
+}
+
+func (s *FellowshipStore) SetFellowshipTimezone(ctx context.Context, fellowshipId uuid.UUID, timezone string) error {
+	if err := s.inner.SetFellowshipTimezone(ctx, fellowshipId, timezone); err != nil {
+		return err
+	}
+
+	s.invalidateFellowship(ctx, fellowshipId)
+	return nil
+}
+
+func (s *FellowshipStore) SetFellowshipDetails(ctx context.Context, fellowshipId uuid.UUID, description string, isPublic bool) error {
+	if err := s.inner.SetFellowshipDetails(ctx, fellowshipId, description, isPublic); err != nil {
+		return err
+	}
+
+	s.invalidateFellowship(ctx, fellowshipId)
+	return nil
+}
+
+func (s *FellowshipStore) SetFollowParentNotices(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID, follow bool) error {
+	return s.inner.SetFollowParentNotices(ctx, fellowshipId, userId, follow)
+}
+
+func (s *FellowshipStore) SetWorshipLeader(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID, leader bool) error {
+	return s.inner.SetWorshipLeader(ctx, fellowshipId, userId, leader)
+}
+
+// invalidateFellowship drops the cached fellowship lists of a fellowship's members, which hold a
+// copy of it. If the members cannot be read, every cached list is dropped.
+func (s *FellowshipStore) invalidateFellowship(ctx context.Context, fellowshipId uuid.UUID) {
+	members, err := s.inner.GetFellowshipMembers(ctx, fellowshipId)
+	if err != nil {
+		s.fellowshipsCache.Clear()
+		return
+	}
+
+	for _, member := range members {
+		s.fellowshipsCache.Delete(member.UserId)
+	}
+}
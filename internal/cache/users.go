@@ -34,6 +34,14 @@ func (s *UserStore) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, er
 	return user, nil
 }
 
+func (s *UserStore) GetUsers(ctx context.Context, ids []uuid.UUID) ([]domain.User, error) {
+	return s.inner.GetUsers(ctx, ids)
+}
+
+func (s *UserStore) GetUserContacts(ctx context.Context, ids []uuid.UUID) ([]domain.UserContact, error) {
+	return s.inner.GetUserContacts(ctx, ids)
+}
+
 func (s *UserStore) GetUserConnection(ctx context.Context, signInType domain.SignInType, accountId string) (*domain.UserConnection, error) {
 	return s.inner.GetUserConnection(ctx, signInType, accountId)
 }
@@ -0,0 +1,103 @@
+package circletypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+const maxSongsPerPost = 50
+
+var builtins = []Definition{
+	{
+		Type:     domain.Notices,
+		Name:     "Notices",
+		Kinds:    []Kind{{Kind: domain.PostKindNotice}},
+		Pinnable: true,
+	},
+	{
+		Type:      domain.Prayer,
+		Name:      "Prayer",
+		Kinds:     []Kind{{Kind: domain.PostKindPrayer}},
+		Reactions: []domain.ReactionKind{domain.ReactionPrayed, domain.ReactionAmen, domain.ReactionHeart},
+	},
+	{
+		Type: domain.Events,
+		Name: "Events",
+		Kinds: []Kind{
+			{Kind: domain.PostKindEvent, Validate: validateEventDetails},
+			{Kind: domain.PostKindGeneral},
+		},
+	},
+	{
+		Type: domain.Worship,
+		Name: "Worship",
+		Kinds: []Kind{
+			{Kind: domain.PostKindWorship, Validate: validateWorshipDetails},
+			{Kind: domain.PostKindGeneral},
+		},
+	},
+	{
+		Type:  domain.Discussion,
+		Name:  "Discussion",
+		Kinds: []Kind{{Kind: domain.PostKindDiscussion}},
+	},
+}
+
+// EventDetails are the extra fields of an event post.
+type EventDetails struct {
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"`
+	Location string     `json:"location,omitempty"`
+}
+
+// SongReference identifies a song used in a worship post.
+type SongReference struct {
+	Title string `json:"title"`
+	CCLI  string `json:"ccli,omitempty"`
+	Key   string `json:"key,omitempty"`
+}
+
+// WorshipDetails are the extra fields of a worship post, such as a Sunday set list.
+type WorshipDetails struct {
+	Songs []SongReference `json:"songs"`
+}
+
+func validateEventDetails(details json.RawMessage) error {
+	var event EventDetails
+	if err := decodeDetails(details, &event); err != nil {
+		return err
+	}
+
+	if event.Start.IsZero() {
+		return fmt.Errorf("%w: event start is required", domain.ErrInvalidPostDetails)
+	}
+
+	if event.End != nil && !event.End.After(event.Start) {
+		return fmt.Errorf("%w: event end must be after its start", domain.ErrInvalidPostDetails)
+	}
+
+	return nil
+}
+
+func validateWorshipDetails(details json.RawMessage) error {
+	var worship WorshipDetails
+	if err := decodeDetails(details, &worship); err != nil {
+		return err
+	}
+
+	if len(worship.Songs) == 0 || len(worship.Songs) > maxSongsPerPost {
+		return fmt.Errorf("%w: worship posts need between 1 and %d songs", domain.ErrInvalidPostDetails, maxSongsPerPost)
+	}
+
+	for _, song := range worship.Songs {
+		if strings.TrimSpace(song.Title) == "" {
+			return fmt.Errorf("%w: every song needs a title", domain.ErrInvalidPostDetails)
+		}
+	}
+
+	return nil
+}
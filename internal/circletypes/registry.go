@@ -0,0 +1,176 @@
+// Package circletypes defines the post kinds, reactions and pinning each domain.CircleType allows. New circle types
+// are added by registering a Definition; the feed service only consults the Registry.
+package circletypes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+// DetailsValidator checks the kind-specific details of a post. details is never empty.
+type DetailsValidator func(details json.RawMessage) error
+
+type Kind struct {
+	Kind domain.PostKind
+	// Validate checks the extra details of the kind. Kinds without a validator take no details.
+	Validate DetailsValidator
+}
+
+type Definition struct {
+	Type domain.CircleType
+	Name string
+	// Kinds lists the post kinds allowed in the circle type. The first is used when a post has no kind.
+	Kinds []Kind
+	// Reactions lists the reactions allowed on posts in the circle type. DefaultReactions are used when empty.
+	Reactions []domain.ReactionKind
+	// Pinnable allows moderators to pin posts in the circle type to the top of feeds.
+	Pinnable bool
+}
+
+// DefaultReactions are allowed on posts whose circle type does not list its own reactions.
+var DefaultReactions = []domain.ReactionKind{domain.ReactionAmen, domain.ReactionHeart, domain.ReactionPraise}
+
+// Fellowship describes posts made to a fellowship as a whole rather than to one of its circles.
+var Fellowship = Definition{
+	Name:  "Fellowship",
+	Kinds: []Kind{{Kind: domain.PostKindGeneral}},
+}
+
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[domain.CircleType]Definition
+}
+
+func NewRegistry() *Registry {
+	return &Registry{definitions: make(map[domain.CircleType]Definition)}
+}
+
+// Default returns a registry holding the built-in circle types.
+func Default() *Registry {
+	r := NewRegistry()
+	for _, definition := range builtins {
+		if err := r.Register(definition); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
+
+// Register adds a circle type. Each type can only be registered once and must allow at least one kind.
+func (r *Registry) Register(definition Definition) error {
+	if len(definition.Kinds) == 0 {
+		return fmt.Errorf("circle type %d (%s) has no post kinds", definition.Type, definition.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.definitions[definition.Type]; ok {
+		return fmt.Errorf("circle type %d (%s) is already registered", definition.Type, definition.Name)
+	}
+
+	r.definitions[definition.Type] = definition
+	return nil
+}
+
+func (r *Registry) Definition(circleType domain.CircleType) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definition, ok := r.definitions[circleType]
+	return definition, ok
+}
+
+// ValidatePost checks that kind is allowed in circles of circleType and that details suit it.
+// It returns the kind to store, which is the type's default kind when kind is empty.
+func (r *Registry) ValidatePost(circleType domain.CircleType, kind domain.PostKind, details json.RawMessage) (domain.PostKind, error) {
+	definition, ok := r.Definition(circleType)
+	if !ok {
+		return "", fmt.Errorf("circle type %d is not registered", circleType)
+	}
+
+	return definition.ValidatePost(kind, details)
+}
+
+// CanPin reports whether posts in circles of circleType can be pinned.
+func (r *Registry) CanPin(circleType domain.CircleType) bool {
+	definition, ok := r.Definition(circleType)
+	return ok && definition.Pinnable
+}
+
+// ValidateReaction checks that kind is allowed on posts in circles of circleType.
+func (r *Registry) ValidateReaction(circleType domain.CircleType, kind domain.ReactionKind) error {
+	definition, ok := r.Definition(circleType)
+	if !ok {
+		return fmt.Errorf("circle type %d is not registered", circleType)
+	}
+
+	return definition.ValidateReaction(kind)
+}
+
+func (d Definition) ValidateReaction(kind domain.ReactionKind) error {
+	reactions := d.Reactions
+	if len(reactions) == 0 {
+		reactions = DefaultReactions
+	}
+
+	if !slices.Contains(reactions, kind) {
+		return fmt.Errorf("%w: %s in %s", domain.ErrInvalidReaction, kind, d.Name)
+	}
+
+	return nil
+}
+
+func (d Definition) ValidatePost(kind domain.PostKind, details json.RawMessage) (domain.PostKind, error) {
+	if kind == "" {
+		kind = d.Kinds[0].Kind
+	}
+
+	for _, k := range d.Kinds {
+		if k.Kind != kind {
+			continue
+		}
+
+		if isEmpty(details) {
+			if k.Validate != nil {
+				return "", fmt.Errorf("%w: %s posts require details", domain.ErrInvalidPostDetails, kind)
+			}
+
+			return kind, nil
+		}
+
+		if k.Validate == nil {
+			return "", fmt.Errorf("%w: %s posts take no details", domain.ErrInvalidPostDetails, kind)
+		}
+
+		if err := k.Validate(details); err != nil {
+			return "", err
+		}
+
+		return kind, nil
+	}
+
+	return "", fmt.Errorf("%w: %s in %s", domain.ErrInvalidPostKind, kind, d.Name)
+}
+
+func isEmpty(details json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(details)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+// decodeDetails strictly decodes details into v, rejecting unknown fields.
+func decodeDetails(details json.RawMessage, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(details))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidPostDetails, err)
+	}
+
+	return nil
+}
@@ -33,3 +33,17 @@ type MailConfig interface {
 	GetMailDomain() string
 	GetMailEndpoint() string
 }
+
+// StorageConfig selects where attachment content is kept and how much each fellowship may use.
+type StorageConfig interface {
+	// GetStorageBackend is "local" or "s3".
+	GetStorageBackend() string
+	GetStoragePath() string
+	GetS3Endpoint() string
+	GetS3Region() string
+	GetS3Bucket() string
+	GetS3AccessKey() string
+	GetS3SecretKey() string
+	// GetFellowshipStorageQuota is the number of bytes of attachments each fellowship may store.
+	GetFellowshipStorageQuota() int64
+}
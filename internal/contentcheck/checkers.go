@@ -0,0 +1,144 @@
+package contentcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// BlockedWordStore provides each fellowship's blocked words.
+type BlockedWordStore interface {
+	GetBlockedWords(ctx context.Context, fellowshipId uuid.UUID) ([]domain.BlockedWord, error)
+}
+
+// MemberStore provides when users joined fellowships.
+type MemberStore interface {
+	GetMemberJoined(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (time.Time, error)
+}
+
+// PostHistory provides an author's recent posts, including scheduled ones, which were checked when
+// they were made. Drafts are left out, so saving them neither counts towards the rate limit nor makes
+// a later post a duplicate.
+type PostHistory interface {
+	GetPostsSince(ctx context.Context, authorId uuid.UUID, since time.Time, limit int) ([]domain.Post, error)
+}
+
+// duplicateLookback bounds how many recent posts are compared with a new one.
+const duplicateLookback = 50
+
+// linkRegex matches the start of a web link.
+var linkRegex = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S`)
+
+// BlockedWords applies the blocked words of the post's fellowship. Words match whole words regardless
+// of case and punctuation, and a post using several gets the strictest of their outcomes.
+func BlockedWords(store BlockedWordStore) Checker {
+	return CheckerFunc(func(ctx context.Context, submission Submission) (Verdict, error) {
+		words, err := store.GetBlockedWords(ctx, submission.FellowshipId)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("failed to get blocked words for fellowship %s: %w", submission.FellowshipId, err)
+		}
+
+		if len(words) == 0 {
+			return Allow, nil
+		}
+
+		text := " " + NormalizeText(postText(submission.Post)) + " "
+		verdict := Allow
+
+		for _, word := range words {
+			if !strings.Contains(text, " "+word.Word+" ") {
+				continue
+			}
+
+			if word.Outcome == domain.ContentReject {
+				return Verdict{Outcome: domain.ContentReject, Reason: fmt.Sprintf("uses the blocked word %q", word.Word)}, nil
+			}
+
+			if verdict.Outcome == domain.ContentAllow {
+				verdict = Verdict{Outcome: domain.ContentHold, Reason: fmt.Sprintf("uses the blocked word %q", word.Word)}
+			}
+		}
+
+		return verdict, nil
+	})
+}
+
+// NewMemberLinks holds posts with more than maxLinks links from authors who joined the post's
+// fellowship less than age ago. Admins of a parent fellowship post with inherited access and are
+// not new to it.
+func NewMemberLinks(members MemberStore, maxLinks int, age time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context, submission Submission) (Verdict, error) {
+		links := len(linkRegex.FindAllStringIndex(postText(submission.Post), -1))
+		if links <= maxLinks {
+			return Allow, nil
+		}
+
+		post := submission.Post
+		joined, err := members.GetMemberJoined(ctx, post.AuthorId, submission.FellowshipId)
+		if errors.Is(err, domain.ErrNotMember) {
+			return Allow, nil
+		}
+		if err != nil {
+			return Verdict{}, fmt.Errorf("failed to get when user %s joined fellowship %s: %w", post.AuthorId, submission.FellowshipId, err)
+		}
+
+		if post.Posted.Sub(joined) >= age {
+			return Allow, nil
+		}
+
+		return Verdict{Outcome: domain.ContentHold, Reason: fmt.Sprintf("a new member posted %d links", links)}, nil
+	})
+}
+
+// Duplicates rejects posts with the same heading and article as one the author made within window,
+// ignoring case, spacing and punctuation.
+func Duplicates(history PostHistory, window time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context, submission Submission) (Verdict, error) {
+		text := NormalizeText(postText(submission.Post))
+		if text == "" {
+			return Allow, nil
+		}
+
+		post := submission.Post
+		recent, err := history.GetPostsSince(ctx, post.AuthorId, post.Posted.Add(-window), duplicateLookback)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("failed to get recent posts of user %s: %w", post.AuthorId, err)
+		}
+
+		for _, other := range recent {
+			if other.Id != post.Id && NormalizeText(postText(other)) == text {
+				return Verdict{Outcome: domain.ContentReject, Reason: "duplicates a recent post"}, nil
+			}
+		}
+
+		return Allow, nil
+	})
+}
+
+// RateLimit rejects posts from authors who have already made limit posts within window. Edits are
+// not new posts and are not limited.
+func RateLimit(history PostHistory, limit int, window time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context, submission Submission) (Verdict, error) {
+		if submission.Edit {
+			return Allow, nil
+		}
+
+		post := submission.Post
+		recent, err := history.GetPostsSince(ctx, post.AuthorId, post.Posted.Add(-window), limit+1)
+		if err != nil {
+			return Verdict{}, fmt.Errorf("failed to get recent posts of user %s: %w", post.AuthorId, err)
+		}
+
+		if len(recent) < limit {
+			return Allow, nil
+		}
+
+		return Verdict{Outcome: domain.ContentReject, Reason: fmt.Sprintf("already posted %d times in the last %s", limit, window), Err: domain.ErrPostRateLimited}, nil
+	})
+}
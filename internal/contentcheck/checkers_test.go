@@ -0,0 +1,66 @@
+package contentcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memberStore is a MemberStore of the users who joined one fellowship.
+type memberStore struct {
+	fellowshipId uuid.UUID
+	joined       map[uuid.UUID]time.Time
+}
+
+func (m memberStore) GetMemberJoined(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (time.Time, error) {
+	joined, ok := m.joined[userId]
+	if !ok || fellowshipId != m.fellowshipId {
+		return time.Time{}, domain.ErrNotMember
+	}
+
+	return joined, nil
+}
+
+func TestNewMemberLinks(t *testing.T) {
+	posted := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	fellowshipId := uuid.New()
+	newMember, oldMember, inheritedAdmin := uuid.New(), uuid.New(), uuid.New()
+	members := memberStore{fellowshipId: fellowshipId, joined: map[uuid.UUID]time.Time{
+		newMember: posted.Add(-24 * time.Hour),
+		oldMember: posted.Add(-30 * 24 * time.Hour),
+	}}
+
+	// Every author's account is old; only their membership of the fellowship is new.
+	author := domain.User{Created: posted.AddDate(-1, 0, 0)}
+	check := NewMemberLinks(members, 1, 7*24*time.Hour)
+
+	tests := []struct {
+		name    string
+		author  uuid.UUID
+		article string
+		want    domain.ContentOutcome
+	}{
+		{name: "new member with one link", author: newMember, article: "See https://example.com", want: domain.ContentAllow},
+		{name: "new member with two links", author: newMember, article: "See https://example.com and www.example.org", want: domain.ContentHold},
+		{name: "old member with two links", author: oldMember, article: "See https://example.com and www.example.org", want: domain.ContentAllow},
+		{name: "inherited admin with two links", author: inheritedAdmin, article: "See https://example.com and www.example.org", want: domain.ContentAllow},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			post := domain.Post{Id: uuid.New(), AuthorId: test.author, FellowshipId: fellowshipId, Posted: posted, Article: test.article}
+
+			verdict, err := check.Check(context.Background(), Submission{Post: post, Author: author, FellowshipId: fellowshipId})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+
+			if verdict.Outcome != test.want {
+				t.Errorf("Check = %s (%s), want %s", verdict.Outcome, verdict.Reason, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package contentcheck screens posts before they are shown, as they are posted, edited or published
+// from drafts. A Chain runs Checkers in order and each allows the post, holds it for the moderators
+// to review or rejects it. New checks are added by putting another Checker in the chain; the feed
+// service only consults the Chain.
+package contentcheck
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Submission is a new or edited post to check.
+type Submission struct {
+	Post   domain.Post
+	Author domain.User
+	// FellowshipId is the fellowship the post is made in, or its circle's fellowship.
+	FellowshipId uuid.UUID
+	// Edit is set when Post is the new content of an existing post rather than a new one.
+	Edit bool
+}
+
+// Verdict is what a checker decided about a submission.
+type Verdict struct {
+	Outcome domain.ContentOutcome
+	// Reason explains a hold to the moderators or a rejection to the author.
+	Reason string
+	// Err is returned to the author of a rejected post, wrapping domain.ErrPostRejected when nil.
+	Err error
+}
+
+// Allow is the verdict of a checker that found nothing wrong.
+var Allow = Verdict{Outcome: domain.ContentAllow}
+
+type Checker interface {
+	// Check decides what happens to a submission. Errors are failures to check, not rejections.
+	Check(ctx context.Context, submission Submission) (Verdict, error)
+}
+
+// CheckerFunc adapts a function to a Checker.
+type CheckerFunc func(ctx context.Context, submission Submission) (Verdict, error)
+
+func (f CheckerFunc) Check(ctx context.Context, submission Submission) (Verdict, error) {
+	return f(ctx, submission)
+}
+
+type Chain struct {
+	checkers []Checker
+}
+
+func NewChain(checkers ...Checker) *Chain {
+	return &Chain{checkers: checkers}
+}
+
+// Check runs the checkers in order and returns the first rejection, or else the first hold. Checkers
+// after a rejection are not run.
+func (c *Chain) Check(ctx context.Context, submission Submission) (Verdict, error) {
+	verdict := Allow
+
+	for _, checker := range c.checkers {
+		v, err := checker.Check(ctx, submission)
+		if err != nil {
+			return Verdict{}, err
+		}
+
+		switch v.Outcome {
+		case domain.ContentReject:
+			return v, nil
+		case domain.ContentHold:
+			if verdict.Outcome == domain.ContentAllow {
+				verdict = v
+			}
+		}
+	}
+
+	return verdict, nil
+}
+
+// NormalizeText returns text as lower case words separated by single spaces, dropping punctuation,
+// so that text can be compared and searched for words regardless of case and spacing.
+func NormalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, " ")
+}
+
+// postText returns the text of a post that the checks read.
+func postText(post domain.Post) string {
+	text := post.Heading + "\n" + post.Article
+	if post.Poll != nil {
+		text += "\n" + strings.Join(post.Poll.Options, "\n")
+	}
+
+	return text
+}
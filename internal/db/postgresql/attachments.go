@@ -0,0 +1,255 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewAttachmentStore(db *sql.DB) *AttachmentStore {
+	return &AttachmentStore{db: db}
+}
+
+type AttachmentStore struct {
+	db *sql.DB
+}
+
+// attachmentColumns are the columns read by scanAttachment.
+const attachmentColumns = "id, fellowshipId, circleId, uploaderId, postId, name, contentType, size, uploaded, status, width, height"
+
+func (a *AttachmentStore) GetAttachment(ctx context.Context, attachmentId uuid.UUID) (*domain.Attachment, error) {
+	attachment, err := scanAttachment(a.db.QueryRowContext(ctx, "SELECT "+attachmentColumns+" FROM Attachments WHERE id=$1", attachmentId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (a *AttachmentStore) GetAttachments(ctx context.Context, attachmentIDs []uuid.UUID) ([]domain.Attachment, error) {
+	rows, err := a.db.QueryContext(ctx, "SELECT "+attachmentColumns+" FROM Attachments WHERE id = ANY($1) ORDER BY uploaded, id", pq.Array(attachmentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]domain.Attachment, 0, len(attachmentIDs))
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (a *AttachmentStore) GetPostAttachments(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]domain.Attachment, error) {
+	attachments := make(map[uuid.UUID][]domain.Attachment, len(postIDs))
+	if len(postIDs) == 0 {
+		return attachments, nil
+	}
+
+	rows, err := a.db.QueryContext(ctx, "SELECT "+attachmentColumns+" FROM Attachments WHERE postId = ANY($1) ORDER BY postId, uploaded, id", pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments[*attachment.PostId] = append(attachments[*attachment.PostId], attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (a *AttachmentStore) CreateAttachment(ctx context.Context, attachment domain.Attachment, quota int64) error {
+	if attachment.Id == uuid.Nil {
+		panic("invalid attachment id")
+	}
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO FellowshipStorage (fellowshipId) VALUES ($1) ON CONFLICT DO NOTHING", attachment.FellowshipId)
+	if err != nil {
+		return err
+	}
+
+	// The conditional update reserves the space atomically, so concurrent uploads cannot overshoot the quota.
+	result, err := tx.ExecContext(ctx, "UPDATE FellowshipStorage SET used = used + $2 WHERE fellowshipId=$1 AND used + $2 <= $3", attachment.FellowshipId, attachment.Size, quota)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrStorageQuotaExceeded
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO Attachments (id, fellowshipId, circleId, uploaderId, name, contentType, size, stored, uploaded, status) VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8, $9)",
+		attachment.Id, attachment.FellowshipId, uuid.NullUUID{UUID: attachment.CircleId, Valid: attachment.CircleId != uuid.Nil}, attachment.UploaderId,
+		attachment.Name, attachment.ContentType, attachment.Size, attachment.Uploaded, attachment.Status)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *AttachmentStore) DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var fellowshipId uuid.UUID
+	var stored int64
+	err = tx.QueryRowContext(ctx, "DELETE FROM Attachments WHERE id=$1 RETURNING fellowshipId, stored", attachmentId).Scan(&fellowshipId, &stored)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE FellowshipStorage SET used = GREATEST(used - $2, 0) WHERE fellowshipId=$1", fellowshipId, stored)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *AttachmentStore) DeleteUnattachedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	// Attachments being attached to a post are locked by the post's transaction and skipped.
+	const query = `
+		WITH removed AS (
+			DELETE FROM Attachments WHERE id IN (
+				SELECT id FROM Attachments WHERE postId IS NULL AND uploaded < $1 ORDER BY uploaded LIMIT $2 FOR UPDATE SKIP LOCKED)
+			RETURNING id, fellowshipId, stored),
+		released AS (
+			UPDATE FellowshipStorage s SET used = GREATEST(s.used - r.total, 0)
+			FROM (SELECT fellowshipId, sum(stored) AS total FROM removed GROUP BY fellowshipId) r
+			WHERE s.fellowshipId = r.fellowshipId)
+		SELECT id FROM removed`
+
+	rows, err := a.db.QueryContext(ctx, query, uploadedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachmentIDs := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		var attachmentId uuid.UUID
+		if err := rows.Scan(&attachmentId); err != nil {
+			return nil, err
+		}
+
+		attachmentIDs = append(attachmentIDs, attachmentId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachmentIDs, nil
+}
+
+func (a *AttachmentStore) GetProcessingAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]domain.Attachment, error) {
+	rows, err := a.db.QueryContext(ctx, "SELECT "+attachmentColumns+" FROM Attachments WHERE status='processing' AND uploaded < $1 ORDER BY uploaded, id LIMIT $2", uploadedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]domain.Attachment, 0)
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (a *AttachmentStore) FinishAttachment(ctx context.Context, attachmentId uuid.UUID, status domain.AttachmentStatus, size, stored int64, width, height int) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var fellowshipId uuid.UUID
+	var oldStored int64
+	err = tx.QueryRowContext(ctx, "SELECT fellowshipId, stored FROM Attachments WHERE id=$1 AND status='processing' FOR UPDATE", attachmentId).Scan(&fellowshipId, &oldStored)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE Attachments SET status=$2, size=$3, stored=$4, width=$5, height=$6 WHERE id=$1",
+		attachmentId, status, size, stored, sql.NullInt32{Int32: int32(width), Valid: width > 0}, sql.NullInt32{Int32: int32(height), Valid: height > 0})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE FellowshipStorage SET used = GREATEST(used + $2, 0) WHERE fellowshipId=$1", fellowshipId, stored-oldStored)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// scanAttachment reads attachmentColumns into an attachment.
+func scanAttachment(row scanner) (domain.Attachment, error) {
+	attachment := domain.Attachment{}
+	var circleId, postId uuid.NullUUID
+	var width, height sql.NullInt32
+
+	err := row.Scan(&attachment.Id, &attachment.FellowshipId, &circleId, &attachment.UploaderId, &postId,
+		&attachment.Name, &attachment.ContentType, &attachment.Size, &attachment.Uploaded, &attachment.Status, &width, &height)
+	if err != nil {
+		return attachment, err
+	}
+
+	attachment.CircleId = circleId.UUID
+	attachment.Width, attachment.Height = int(width.Int32), int(height.Int32)
+	if postId.Valid {
+		attachment.PostId = &postId.UUID
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestCreateAttachmentReservesQuota(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserved int64
+		wantErr  error
+		want     []string
+	}{
+		{
+			name:     "within quota",
+			reserved: 1,
+			want:     []string{"INSERT INTO FellowshipStorage", "UPDATE FellowshipStorage", "INSERT INTO Attachments", "COMMIT"},
+		},
+		{
+			name:     "over quota",
+			reserved: 0,
+			wantErr:  domain.ErrStorageQuotaExceeded,
+			want:     []string{"INSERT INTO FellowshipStorage", "UPDATE FellowshipStorage", "ROLLBACK"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attachment := domain.Attachment{Id: uuid.New(), FellowshipId: uuid.New(), UploaderId: uuid.New(), Name: "photo.jpg", ContentType: "image/jpeg", Size: 300, Uploaded: time.Now(), Status: domain.AttachmentReady}
+			var statements []string
+
+			db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+				statements = append(statements, query)
+
+				if strings.HasPrefix(query, "UPDATE FellowshipStorage") {
+					// The space is only taken if it is still free when the row is updated.
+					if !strings.Contains(query, "used + $2 <= $3") {
+						t.Errorf("reservation %q is not conditional on the quota", query)
+					}
+
+					if args[0].Value != attachment.FellowshipId.String() || args[1].Value != attachment.Size || args[2].Value != int64(1000) {
+						t.Errorf("reservation args = %v, want fellowship %s, size %d and quota 1000", args, attachment.FellowshipId, attachment.Size)
+					}
+
+					return fakeRows{affected: test.reserved}, nil
+				}
+
+				return fakeRows{affected: 1}, nil
+			})
+
+			err := NewAttachmentStore(db).CreateAttachment(context.Background(), attachment, 1000)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("CreateAttachment = %v, want %v", err, test.wantErr)
+			}
+
+			if len(statements) != len(test.want) {
+				t.Fatalf("statements = %q, want %q", statements, test.want)
+			}
+
+			for i, prefix := range test.want {
+				if !strings.HasPrefix(statements[i], prefix) {
+					t.Errorf("statement %d = %q, want %s", i, statements[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteUnattachedAttachments(t *testing.T) {
+	before := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	removed := []uuid.UUID{uuid.New(), uuid.New()}
+
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		// Only uploads that were never attached are removed, and their storage is given back.
+		for _, part := range []string{"postId IS NULL", "uploaded < $1", "LIMIT $2", "SKIP LOCKED", "UPDATE FellowshipStorage"} {
+			if !strings.Contains(query, part) {
+				t.Errorf("query does not contain %q", part)
+			}
+		}
+
+		if args[0].Value != before || args[1].Value != int64(2) {
+			t.Errorf("args = %v, want %s and 2", args, before)
+		}
+
+		return fakeRows{columns: []string{"id"}, rows: [][]driver.Value{{removed[0].String()}, {removed[1].String()}}}, nil
+	})
+
+	attachmentIDs, err := NewAttachmentStore(db).DeleteUnattachedAttachments(context.Background(), before, 2)
+	if err != nil {
+		t.Fatalf("DeleteUnattachedAttachments: %v", err)
+	}
+
+	if len(attachmentIDs) != 2 || attachmentIDs[0] != removed[0] || attachmentIDs[1] != removed[1] {
+		t.Errorf("DeleteUnattachedAttachments = %v, want %v", attachmentIDs, removed)
+	}
+}
@@ -0,0 +1,43 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func NewCalendarStore(db *sql.DB) *CalendarStore {
+	return &CalendarStore{db: db}
+}
+
+type CalendarStore struct {
+	db *sql.DB
+}
+
+func (c *CalendarStore) GetCalendarFeedUserId(ctx context.Context, tokenHash []byte) (uuid.UUID, error) {
+	var userId uuid.UUID
+	err := c.db.QueryRowContext(ctx, "SELECT userId FROM CalendarFeeds WHERE tokenHash=$1", tokenHash).Scan(&userId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return userId, nil
+}
+
+func (c *CalendarStore) SetCalendarFeedToken(ctx context.Context, userId uuid.UUID, tokenHash []byte, created time.Time) error {
+	_, err := c.db.ExecContext(ctx, `INSERT INTO CalendarFeeds (userId, tokenHash, created) VALUES ($1, $2, $3)
+		ON CONFLICT (userId) DO UPDATE SET tokenHash=EXCLUDED.tokenHash, created=EXCLUDED.created`,
+		userId, tokenHash, created)
+	return err
+}
+
+func (c *CalendarStore) DeleteCalendarFeedToken(ctx context.Context, userId uuid.UUID) error {
+	result, err := c.db.ExecContext(ctx, "DELETE FROM CalendarFeeds WHERE userId=$1", userId)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
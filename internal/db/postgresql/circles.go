@@ -7,6 +7,7 @@ import (
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func NewCircleStore(db *sql.DB) *CircleStore {
@@ -17,8 +18,40 @@ type CircleStore struct {
 	db *sql.DB
 }
 
+func (c *CircleStore) GetCircle(ctx context.Context, circleId uuid.UUID) (*domain.Circle, error) {
+	circle := &domain.Circle{Id: circleId}
+
+	err := c.db.QueryRowContext(ctx, "SELECT fellowshipId, name, type, accessMode, creator FROM FellowshipCircles WHERE id=$1", circleId).
+		Scan(&circle.FellowshipId, &circle.Name, &circle.Type, &circle.AccessMode, &circle.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	return circle, nil
+}
+
+// openCircleFellowships lists, as the CTE open_to, the fellowships whose open circles a user ($1) can
+// access: those they are a member of, and, for Owners and Admins ($3), those beneath theirs down to
+// the depth ($4) FellowshipStore.GetUserAccessLevel looks up.
+const openCircleFellowships = `
+	WITH RECURSIVE managed(id, depth) AS (
+		SELECT fellowshipId, 0 FROM FellowshipMembers WHERE userId=$1 AND access <= $3
+		UNION
+		SELECT f.id, m.depth + 1 FROM Fellowships f JOIN managed m ON f.parentId = m.id
+		WHERE m.depth < $4
+	), open_to(id) AS (
+		SELECT fellowshipId FROM FellowshipMembers WHERE userId=$1
+		UNION
+		SELECT id FROM managed
+	)`
+
 func (c *CircleStore) GetUserCircles(ctx context.Context, userId uuid.UUID) ([]domain.Circle, error) {
-	rows, err := c.db.QueryContext(ctx, "SELECT id, fellowshipId, name, type, creator FROM FellowshipCircles WHERE id in (SELECT circleId FROM CircleMembers WHERE userId=$1)", userId)
+	const query = openCircleFellowships + `
+		SELECT id, fellowshipId, name, type, accessMode, creator FROM FellowshipCircles
+		WHERE id IN (SELECT circleId FROM CircleMembers WHERE userId=$1)
+		OR (accessMode=$2 AND fellowshipId IN (SELECT id FROM open_to))`
+
+	rows, err := c.db.QueryContext(ctx, query, userId, domain.CircleOpen, domain.Admin, domain.FellowshipMaxDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +61,7 @@ func (c *CircleStore) GetUserCircles(ctx context.Context, userId uuid.UUID) ([]d
 
 	for rows.Next() {
 		circle := domain.Circle{}
-		err := rows.Scan(&circle.Id, &circle.FellowshipId, &circle.Name, &circle.Type, &circle.Creator)
+		err := rows.Scan(&circle.Id, &circle.FellowshipId, &circle.Name, &circle.Type, &circle.AccessMode, &circle.Creator)
 		if err != nil {
 			return nil, err
 		}
@@ -44,7 +77,13 @@ func (c *CircleStore) GetUserCircles(ctx context.Context, userId uuid.UUID) ([]d
 }
 
 func (c *CircleStore) GetUserCircleIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := c.db.QueryContext(ctx, "SELECT circleId FROM CircleMembers WHERE userId=$1", userId)
+	const query = openCircleFellowships + `
+		SELECT circleId FROM CircleMembers WHERE userId=$1
+		UNION
+		SELECT c.id FROM FellowshipCircles c JOIN open_to o ON o.id = c.fellowshipId
+		WHERE c.accessMode=$2`
+
+	rows, err := c.db.QueryContext(ctx, query, userId, domain.CircleOpen, domain.Admin, domain.FellowshipMaxDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -69,15 +108,65 @@ func (c *CircleStore) GetUserCircleIDs(ctx context.Context, userId uuid.UUID) ([
 	return circleIds, nil
 }
 
+func (c *CircleStore) GetInheritedNoticeCircleIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	const query = `
+		WITH RECURSIVE ancestors(id, depth) AS (
+			SELECT f.parentId, 1 FROM FellowshipMembers m JOIN Fellowships f ON f.id = m.fellowshipId
+			WHERE m.userId=$1 AND m.followParentNotices AND f.parentId IS NOT NULL
+			UNION
+			SELECT f.parentId, a.depth + 1 FROM Fellowships f JOIN ancestors a ON f.id = a.id
+			WHERE f.parentId IS NOT NULL AND a.depth < $3
+		)
+		SELECT DISTINCT c.id FROM FellowshipCircles c JOIN ancestors a ON c.fellowshipId = a.id
+		WHERE c.type=$2 AND c.accessMode=$4`
+
+	rows, err := c.db.QueryContext(ctx, query, userId, domain.Notices, domain.FellowshipMaxDepth, domain.CircleOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	circleIds := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		circleId := uuid.UUID{}
+		if err := rows.Scan(&circleId); err != nil {
+			return nil, err
+		}
+
+		circleIds = append(circleIds, circleId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return circleIds, nil
+}
+
 func (c *CircleStore) GetUserAccessLevel(ctx context.Context, userId uuid.UUID, circleId uuid.UUID) (domain.AccessLevel, error) {
-	accessLevel := domain.NoAccess
+	// Explicit circle membership and, for open circles, fellowship membership both grant access;
+	// the most privileged of the two wins.
+	const query = `
+		SELECT MIN(access) FROM (
+			SELECT access FROM CircleMembers WHERE userId=$1 AND circleId=$2
+			UNION ALL
+			SELECT m.access FROM FellowshipCircles c JOIN FellowshipMembers m ON m.fellowshipId = c.fellowshipId AND m.userId=$1
+			WHERE c.id=$2 AND c.accessMode=$3
+		) AS grants`
 
-	err := c.db.QueryRowContext(ctx, "SELECT access FROM CircleMembers WHERE userId=$1 AND circleId=$2", userId, circleId).Scan(&accessLevel)
+	var accessLevel sql.NullInt32
+
+	err := c.db.QueryRowContext(ctx, query, userId, circleId, domain.CircleOpen).Scan(&accessLevel)
 	if err != nil {
 		return domain.NoAccess, err
 	}
 
-	return accessLevel, nil
+	if !accessLevel.Valid {
+		return domain.NoAccess, domain.ErrNotMember
+	}
+
+	return domain.AccessLevel(accessLevel.Int32), nil
 }
 
 func (c *CircleStore) GetCircleMembers(ctx context.Context, circleId uuid.UUID) ([]domain.CircleMember, error) {
@@ -104,6 +193,92 @@ func (c *CircleStore) GetCircleMembers(ctx context.Context, circleId uuid.UUID)
 	return members, nil
 }
 
+func (c *CircleStore) GetCircleRecipientsNamed(ctx context.Context, circleId uuid.UUID, names []string) ([]domain.User, error) {
+	const query = `
+		SELECT u.id, u.displayName, u.created FROM Users u
+		WHERE lower(btrim(u.displayName)) = ANY($2) AND NOT u.isDeleted AND (
+			EXISTS (SELECT 1 FROM CircleMembers m WHERE m.circleId=$1 AND m.userId = u.id)
+			OR EXISTS (SELECT 1 FROM FellowshipCircles c JOIN FellowshipMembers m ON m.fellowshipId = c.fellowshipId
+				WHERE c.id=$1 AND c.accessMode=$3 AND m.userId = u.id))`
+
+	rows, err := c.db.QueryContext(ctx, query, circleId, pq.Array(names), domain.CircleOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+
+	for rows.Next() {
+		user := domain.User{}
+		if err := rows.Scan(&user.Id, &user.DisplayName, &user.Created); err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (c *CircleStore) GetModeratedCircleIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	const query = `
+		SELECT circleId FROM CircleMembers WHERE userId=$1 AND access <= $2
+		UNION
+		SELECT c.id FROM FellowshipCircles c JOIN FellowshipMembers m ON m.fellowshipId = c.fellowshipId AND m.userId=$1
+		WHERE c.accessMode=$3 AND m.access <= $2`
+
+	rows, err := c.db.QueryContext(ctx, query, userId, domain.Moderator, domain.CircleOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (c *CircleStore) GetJoinRequests(ctx context.Context, circleId uuid.UUID) ([]domain.CircleJoinRequest, error) {
+	rows, err := c.db.QueryContext(ctx, "SELECT userId, requested FROM CircleJoinRequests WHERE circleId=$1 ORDER BY requested", circleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]domain.CircleJoinRequest, 0)
+
+	for rows.Next() {
+		request := domain.CircleJoinRequest{CircleId: circleId}
+		if err := rows.Scan(&request.UserId, &request.Requested); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
 func (c *CircleStore) CreateCircle(ctx context.Context, circle domain.Circle) error {
 	return errors.New("not implemented")
 }
@@ -112,3 +287,51 @@ func (c *CircleStore) AddCircleMember(ctx context.Context, member domain.CircleM
 	_, err := c.db.ExecContext(ctx, "INSERT INTO CircleMembers (circleId, userId, access) VALUES ($1, $2, $3)", member.CircleId, member.UserId, member.Access)
 	return err
 }
+
+func (c *CircleStore) SetCircleAccessMode(ctx context.Context, circleId uuid.UUID, mode domain.CircleAccessMode) error {
+	result, err := c.db.ExecContext(ctx, "UPDATE FellowshipCircles SET accessMode=$2 WHERE id=$1", circleId, mode)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (c *CircleStore) CreateJoinRequest(ctx context.Context, request domain.CircleJoinRequest) error {
+	_, err := c.db.ExecContext(ctx, "INSERT INTO CircleJoinRequests (circleId, userId, requested) VALUES ($1, $2, $3) ON CONFLICT (circleId, userId) DO NOTHING",
+		request.CircleId, request.UserId, request.Requested)
+	return err
+}
+
+func (c *CircleStore) ApproveJoinRequest(ctx context.Context, circleId uuid.UUID, userId uuid.UUID, access domain.AccessLevel) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM CircleJoinRequests WHERE circleId=$1 AND userId=$2", circleId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO CircleMembers (circleId, userId, access) VALUES ($1, $2, $3)", circleId, userId, access)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *CircleStore) RemoveJoinRequest(ctx context.Context, circleId uuid.UUID, userId uuid.UUID) error {
+	result, err := c.db.ExecContext(ctx, "DELETE FROM CircleJoinRequests WHERE circleId=$1 AND userId=$2", circleId, userId)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
@@ -0,0 +1,61 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestGetInheritedNoticeCircleIDsSkipsClosedCircles(t *testing.T) {
+	open, inviteOnly := uuid.New(), uuid.New()
+	parentNotices := map[uuid.UUID]domain.CircleAccessMode{open: domain.CircleOpen, inviteOnly: domain.CircleInviteOnly}
+
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		if !strings.Contains(query, "c.accessMode=$4") || len(args) != 4 {
+			t.Fatalf("query %q with %d args does not filter on the access mode", query, len(args))
+		}
+
+		// Answer as the database would: only the parent Notices circles in the requested mode.
+		rows := [][]driver.Value{}
+		for id, mode := range parentNotices {
+			if args[3].Value == int64(mode) {
+				rows = append(rows, []driver.Value{id.String()})
+			}
+		}
+
+		return fakeRows{columns: []string{"id"}, rows: rows}, nil
+	})
+
+	circleIds, err := NewCircleStore(db).GetInheritedNoticeCircleIDs(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetInheritedNoticeCircleIDs: %v", err)
+	}
+
+	if len(circleIds) != 1 || circleIds[0] != open {
+		t.Errorf("GetInheritedNoticeCircleIDs = %v, want only the open circle %s", circleIds, open)
+	}
+}
+
+func TestGetUserCircleIDsIncludesInheritedOpenCircles(t *testing.T) {
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		// Open circles follow FellowshipStore.GetUserAccessLevel: Owners and Admins of a parent
+		// fellowship reach the open circles beneath it.
+		if !strings.Contains(query, "JOIN managed m ON f.parentId = m.id") || !strings.Contains(query, "JOIN open_to") {
+			t.Errorf("query %q leaves out the open circles of fellowships beneath the user's", query)
+		}
+
+		if len(args) != 4 || args[2].Value != int64(domain.Admin) || args[3].Value != int64(domain.FellowshipMaxDepth) {
+			t.Errorf("args = %v, want the Admin access level and the maximum fellowship depth", args)
+		}
+
+		return fakeRows{columns: []string{"circleId"}}, nil
+	})
+
+	if _, err := NewCircleStore(db).GetUserCircleIDs(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("GetUserCircleIDs: %v", err)
+	}
+}
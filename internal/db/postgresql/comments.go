@@ -0,0 +1,189 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func NewCommentStore(db *sql.DB) *CommentStore {
+	return &CommentStore{db: db}
+}
+
+type CommentStore struct {
+	db *sql.DB
+}
+
+func (c *CommentStore) GetComment(ctx context.Context, commentId uuid.UUID) (*domain.Comment, error) {
+	comment := &domain.Comment{Id: commentId}
+
+	err := c.db.QueryRowContext(ctx, "SELECT postId, parentId, authorId, created, edited, body, hidden FROM Comments WHERE id=$1 AND deleted IS NULL", commentId).
+		Scan(&comment.PostId, &comment.ParentId, &comment.AuthorId, &comment.Created, &comment.Edited, &comment.Body, &comment.Hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (c *CommentStore) GetComments(ctx context.Context, postId uuid.UUID, includeHidden bool, limit *int, cursor *domain.CommentCursor) ([]domain.Comment, *domain.CommentCursor, error) {
+	args := []any{postId}
+	shown := "%[1]s.deleted IS NULL"
+	if !includeHidden {
+		shown += " AND %[1]s.hidden IS NULL"
+	}
+
+	// Top level comments that are not shown are still listed while they have replies that are, so
+	// that the replies are not left without a parent.
+	query := fmt.Sprintf(`SELECT c.id, c.parentId, c.authorId, c.created, c.edited, c.body, c.hidden, NOT (%s) FROM Comments c
+		WHERE c.postId=$1 AND (%s OR (c.parentId IS NULL AND EXISTS (SELECT 1 FROM Comments r WHERE r.parentId = c.id AND %s)))`,
+		fmt.Sprintf(shown, "c"), fmt.Sprintf(shown, "c"), fmt.Sprintf(shown, "r"))
+
+	if cursor != nil {
+		query += fmt.Sprintf(" AND (c.created, c.id) > ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Created, cursor.Id)
+	}
+
+	actualLimit := 50 // default limit
+	if limit != nil {
+		actualLimit = max(min(*limit, 200), 1) // enforce a maximum limit and a minimum of 1
+	}
+
+	// Fetch one extra row to find out whether another page follows.
+	query += fmt.Sprintf(" ORDER BY c.created, c.id LIMIT %d", actualLimit+1)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]domain.Comment, 0, actualLimit)
+	var next *domain.CommentCursor
+
+	for rows.Next() {
+		if len(comments) == actualLimit {
+			last := comments[len(comments)-1]
+			next = &domain.CommentCursor{Created: last.Created, Id: last.Id}
+			break
+		}
+
+		comment := domain.Comment{PostId: postId}
+		err := rows.Scan(&comment.Id, &comment.ParentId, &comment.AuthorId, &comment.Created, &comment.Edited, &comment.Body, &comment.Hidden, &comment.Removed)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if comment.Removed {
+			comment.AuthorId, comment.Edited, comment.Body, comment.Hidden = uuid.Nil, nil, "", nil
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return comments, next, nil
+}
+
+func (c *CommentStore) CreateComment(ctx context.Context, comment domain.Comment) error {
+	if comment.Id == uuid.Nil {
+		panic("invalid comment id")
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO Comments (id, postId, parentId, authorId, created, body) VALUES ($1, $2, $3, $4, $5, $6)",
+		comment.Id, comment.PostId, comment.ParentId, comment.AuthorId, comment.Created, comment.Body)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE Posts SET commentCount = commentCount + 1 WHERE id=$1", comment.PostId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *CommentStore) UpdateComment(ctx context.Context, commentId uuid.UUID, body string, edited time.Time) error {
+	result, err := c.db.ExecContext(ctx, "UPDATE Comments SET body=$2, edited=$3 WHERE id=$1 AND deleted IS NULL", commentId, body, edited)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (c *CommentStore) HideComment(ctx context.Context, commentId uuid.UUID, hiddenBy uuid.UUID, hidden time.Time) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		postId        uuid.UUID
+		alreadyHidden bool
+	)
+	err = tx.QueryRowContext(ctx, "SELECT postId, hidden IS NOT NULL FROM Comments WHERE id=$1 AND deleted IS NULL FOR UPDATE", commentId).Scan(&postId, &alreadyHidden)
+	if err != nil {
+		return err
+	}
+
+	if alreadyHidden {
+		return tx.Commit()
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE Comments SET hidden=$3, hiddenBy=$2 WHERE id=$1", commentId, hiddenBy, hidden)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE Posts SET commentCount = GREATEST(commentCount - 1, 0) WHERE id=$1", postId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *CommentStore) DeleteComment(ctx context.Context, commentId uuid.UUID, deletedBy uuid.UUID, deleted time.Time) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		postId uuid.UUID
+		hidden bool
+	)
+	err = tx.QueryRowContext(ctx, "UPDATE Comments SET deleted=$3, deletedBy=$2 WHERE id=$1 AND deleted IS NULL RETURNING postId, hidden IS NOT NULL", commentId, deletedBy, deleted).Scan(&postId, &hidden)
+	if err != nil {
+		return err
+	}
+
+	// Hidden comments were already taken off the count when they were hidden.
+	if hidden {
+		return tx.Commit()
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE Posts SET commentCount = GREATEST(commentCount - 1, 0) WHERE id=$1", postId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
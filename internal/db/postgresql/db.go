@@ -1,8 +1,10 @@
 package postgresql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/config"
@@ -83,3 +85,32 @@ func NewDB(ctx context.Context, dbConfig config.DatabaseConfig, poolConfig confi
 func PrepareDB(ctx context.Context, db *sql.DB) error {
 	return RunMigrations(ctx, db)
 }
+
+// expectRowsAffected reports sql.ErrNoRows when an UPDATE or DELETE matched nothing.
+func expectRowsAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// nullableJSON converts empty or null JSON into a SQL NULL for JSONB columns.
+func nullableJSON(data json.RawMessage) any {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+
+	return string(trimmed)
+}
+
+// nullJSONDest is the scan destination for a nullable JSONB column. SQL NULL leaves data empty.
+func nullJSONDest(data *json.RawMessage) any {
+	return (*[]byte)(data)
+}
@@ -0,0 +1,131 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeRows is the canned answer to a query or statement run against a fakeDB.
+type fakeRows struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+}
+
+// fakeHandler answers the queries and statements run against a fakeDB. Transactions are ended
+// with the statements COMMIT and ROLLBACK.
+type fakeHandler func(query string, args []driver.NamedValue) (fakeRows, error)
+
+// openFakeDB returns a database whose queries are answered by handler, so stores can be tested
+// through database/sql's own scanning without a server.
+func openFakeDB(t *testing.T, handler fakeHandler) *sql.DB {
+	t.Helper()
+
+	db := sql.OpenDB(fakeConnector{handler: handler})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type fakeConnector struct {
+	handler fakeHandler
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{handler: c.handler}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fake driver is opened through its connector")
+}
+
+type fakeConn struct {
+	handler fakeHandler
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver does not prepare statements")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{conn: c}, nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.handler(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRowsIter{fakeRows: result}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.handler(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(result.affected), nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (t fakeTx) Commit() error {
+	_, err := t.conn.handler("COMMIT", nil)
+	return err
+}
+
+func (t fakeTx) Rollback() error {
+	_, err := t.conn.handler("ROLLBACK", nil)
+	return err
+}
+
+type fakeRowsIter struct {
+	fakeRows
+	next int
+}
+
+func (r *fakeRowsIter) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRowsIter) Close() error {
+	return nil
+}
+
+func (r *fakeRowsIter) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+// columnsOf splits a column list such as postColumns into column names.
+func columnsOf(list string) []string {
+	columns := strings.Split(list, ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	return columns
+}
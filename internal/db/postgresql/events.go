@@ -0,0 +1,236 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewEventStore(db *sql.DB) *EventStore {
+	return &EventStore{db: db}
+}
+
+type EventStore struct {
+	db *sql.DB
+}
+
+// eventColumns are the columns read by scanEvent.
+const eventColumns = `id, fellowshipId, circleId, creatorId, title, description, location, start, "end", timezone, rrule, seriesEnd, created, edited`
+
+func scanEvent(row scanner) (domain.Event, error) {
+	event := domain.Event{Exceptions: make([]domain.EventException, 0)}
+	err := row.Scan(&event.Id, &event.FellowshipId, &event.CircleId, &event.CreatorId, &event.Title, &event.Description, &event.Location,
+		&event.Start, &event.End, &event.Timezone, &event.RRule, &event.SeriesEnd, &event.Created, &event.Edited)
+	return event, err
+}
+
+func (e *EventStore) GetEvent(ctx context.Context, eventId uuid.UUID) (*domain.Event, error) {
+	row := e.db.QueryRowContext(ctx, "SELECT "+eventColumns+" FROM Events WHERE id=$1 AND deleted IS NULL", eventId)
+	event, err := scanEvent(row)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []domain.Event{event}
+	if err := e.attachExceptions(ctx, events); err != nil {
+		return nil, err
+	}
+
+	return &events[0], nil
+}
+
+func (e *EventStore) GetEvents(ctx context.Context, fellowshipIDs []uuid.UUID, circleIDs []uuid.UUID, from time.Time, to time.Time) ([]domain.Event, error) {
+	// Occurrences moved by an exception can fall outside the series' own bounds.
+	rows, err := e.db.QueryContext(ctx, "SELECT "+eventColumns+` FROM Events
+		WHERE (fellowshipId = ANY($1) OR circleId = ANY($2)) AND deleted IS NULL
+			AND ((start < $4 AND (seriesEnd IS NULL OR seriesEnd > $3))
+				OR EXISTS (SELECT 1 FROM EventExceptions x WHERE x.eventId = Events.id AND NOT x.cancelled AND x.start < $4 AND x."end" > $3))
+		ORDER BY start, id`,
+		pq.Array(fellowshipIDs), pq.Array(circleIDs), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.Event, 0)
+
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := e.attachExceptions(ctx, events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// attachExceptions reads the exceptions of events in one query.
+func (e *EventStore) attachExceptions(ctx context.Context, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID]int, len(events))
+	eventIDs := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		index[event.Id] = i
+		eventIDs[i] = event.Id
+	}
+
+	rows, err := e.db.QueryContext(ctx, `SELECT eventId, occurrence, cancelled, start, "end" FROM EventExceptions WHERE eventId = ANY($1) ORDER BY occurrence`, pq.Array(eventIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventId uuid.UUID
+		var exception domain.EventException
+		if err := rows.Scan(&eventId, &exception.Occurrence, &exception.Cancelled, &exception.Start, &exception.End); err != nil {
+			return err
+		}
+
+		i := index[eventId]
+		events[i].Exceptions = append(events[i].Exceptions, exception)
+	}
+
+	return rows.Err()
+}
+
+func (e *EventStore) GetRSVPTallies(ctx context.Context, userId uuid.UUID, eventIDs []uuid.UUID, occurrences []time.Time) ([]domain.RSVPTally, error) {
+	rows, err := e.db.QueryContext(ctx, `SELECT eventId, occurrence, response, COUNT(*), BOOL_OR(userId = $1) FROM EventRSVPs
+		WHERE eventId = ANY($2) AND occurrence = ANY($3) GROUP BY eventId, occurrence, response`,
+		userId, pq.Array(eventIDs), pq.Array(occurrences))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tallies := make([]domain.RSVPTally, 0)
+
+	for rows.Next() {
+		var tally domain.RSVPTally
+		if err := rows.Scan(&tally.EventId, &tally.Occurrence, &tally.Response, &tally.Count, &tally.Mine); err != nil {
+			return nil, err
+		}
+
+		tallies = append(tallies, tally)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tallies, nil
+}
+
+func (e *EventStore) GetRSVPs(ctx context.Context, eventId uuid.UUID, occurrence time.Time) ([]domain.EventRSVP, error) {
+	rows, err := e.db.QueryContext(ctx, "SELECT userId, response, responded FROM EventRSVPs WHERE eventId=$1 AND occurrence=$2 ORDER BY responded, userId", eventId, occurrence)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rsvps := make([]domain.EventRSVP, 0)
+
+	for rows.Next() {
+		rsvp := domain.EventRSVP{EventId: eventId, Occurrence: occurrence}
+		if err := rows.Scan(&rsvp.UserId, &rsvp.Response, &rsvp.Responded); err != nil {
+			return nil, err
+		}
+
+		rsvps = append(rsvps, rsvp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rsvps, nil
+}
+
+func (e *EventStore) CreateEvent(ctx context.Context, event domain.Event) error {
+	_, err := e.db.ExecContext(ctx, `INSERT INTO Events (id, fellowshipId, circleId, creatorId, title, description, location, start, "end", timezone, rrule, seriesEnd, created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		event.Id, event.FellowshipId, event.CircleId, event.CreatorId, event.Title, event.Description, event.Location,
+		event.Start, event.End, event.Timezone, event.RRule, event.SeriesEnd, event.Created)
+	return err
+}
+
+func (e *EventStore) UpdateEvent(ctx context.Context, event domain.Event) error {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE Events SET title=$2, description=$3, location=$4, start=$5, "end"=$6, timezone=$7, rrule=$8, seriesEnd=$9, edited=$10
+		WHERE id=$1 AND deleted IS NULL`,
+		event.Id, event.Title, event.Description, event.Location, event.Start, event.End, event.Timezone, event.RRule, event.SeriesEnd, event.Edited)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM EventExceptions WHERE eventId=$1", event.Id); err != nil {
+		return err
+	}
+
+	for _, exception := range event.Exceptions {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO EventExceptions (eventId, occurrence, cancelled, start, "end") VALUES ($1, $2, $3, $4, $5)`,
+			event.Id, exception.Occurrence, exception.Cancelled, exception.Start, exception.End); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (e *EventStore) DeleteEvent(ctx context.Context, eventId uuid.UUID, deleted time.Time) error {
+	result, err := e.db.ExecContext(ctx, "UPDATE Events SET deleted=$2 WHERE id=$1 AND deleted IS NULL", eventId, deleted)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (e *EventStore) SetEventException(ctx context.Context, eventId uuid.UUID, exception domain.EventException) error {
+	_, err := e.db.ExecContext(ctx, `INSERT INTO EventExceptions (eventId, occurrence, cancelled, start, "end") VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (eventId, occurrence) DO UPDATE SET cancelled=EXCLUDED.cancelled, start=EXCLUDED.start, "end"=EXCLUDED."end"`,
+		eventId, exception.Occurrence, exception.Cancelled, exception.Start, exception.End)
+	return err
+}
+
+func (e *EventStore) DeleteEventException(ctx context.Context, eventId uuid.UUID, occurrence time.Time) error {
+	result, err := e.db.ExecContext(ctx, "DELETE FROM EventExceptions WHERE eventId=$1 AND occurrence=$2", eventId, occurrence)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (e *EventStore) SetRSVP(ctx context.Context, rsvp domain.EventRSVP) error {
+	_, err := e.db.ExecContext(ctx, `INSERT INTO EventRSVPs (eventId, occurrence, userId, response, responded) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (eventId, occurrence, userId) DO UPDATE SET response=EXCLUDED.response, responded=EXCLUDED.responded`,
+		rsvp.EventId, rsvp.Occurrence, rsvp.UserId, rsvp.Response, rsvp.Responded)
+	return err
+}
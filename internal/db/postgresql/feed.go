@@ -3,7 +3,10 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"html"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,64 +23,173 @@ type FeedStore struct {
 	db *sql.DB
 }
 
-func (f *FeedStore) GetPosts(ctx context.Context, fellowshipIDs []uuid.UUID, circleIDs []uuid.UUID, limit *int, before *time.Time, after *time.Time) ([]domain.Post, error) {
-	var (
-		args       []any
-		conditions []string
-	)
-
-	if len(fellowshipIDs) == 0 && len(circleIDs) == 0 {
-		return nil, fmt.Errorf("at least one fellowshipID or circleID must be provided")
+func (f *FeedStore) GetPosts(ctx context.Context, filter domain.PostFilter, limit *int, cursor *domain.FeedCursor) ([]domain.Post, bool, error) {
+	if len(filter.FellowshipIDs) == 0 && len(filter.CircleIDs) == 0 {
+		return nil, false, fmt.Errorf("at least one fellowshipID or circleID must be provided")
 	}
 
-	query := "SELECT id, authorId, fellowshipId, circleId, posted, heading, article FROM Posts WHERE fellowshipId = ANY($1) OR circleId = ANY($2)"
-	args = append(args, pq.Array(fellowshipIDs))
-	args = append(args, pq.Array(circleIDs))
+	query := "SELECT " + postColumns + " FROM Posts"
+	conditions, args := postFilterConditions(filter)
 
-	if before != nil {
-		conditions = append(conditions, fmt.Sprintf("posted < $%d", len(args)+1))
-		args = append(args, *before)
-	}
+	order := "DESC"
+	if cursor != nil {
+		comparison := "<"
+		if cursor.Newer {
+			comparison, order = ">", "ASC"
+		}
 
-	if after != nil {
-		conditions = append(conditions, fmt.Sprintf("posted > $%d", len(args)+1))
-		args = append(args, *after)
+		conditions = append(conditions, fmt.Sprintf("(posted, id) %s ($%d, $%d)", comparison, len(args)+1, len(args)+2))
+		args = append(args, cursor.Posted, cursor.Id)
 	}
 
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
+	query += " WHERE " + strings.Join(conditions, " AND ")
 
 	actualLimit := 10 // default limit
 	if limit != nil {
 		actualLimit = max(min(*limit, 1000), 1) // enforce a maximum limit and a minimum of 1
 	}
 
-	query += fmt.Sprintf(" ORDER BY posted DESC LIMIT %d", actualLimit)
+	// Fetch one extra row to find out whether another page follows.
+	query += fmt.Sprintf(" ORDER BY posted %s, id %s LIMIT %d", order, order, actualLimit+1)
 
 	rows, err := f.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer rows.Close()
 
 	posts := make([]domain.Post, 0, actualLimit)
+	hasMore := false
 
 	for rows.Next() {
-		post := domain.Post{}
-		err := rows.Scan(&post.Id, &post.AuthorId, &post.FellowshipId, &post.CircleId, &post.Posted, &post.Heading, &post.Article)
+		if len(posts) == actualLimit {
+			hasMore = true
+			break
+		}
+
+		post, err := scanPost(rows)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		posts = append(posts, post)
 	}
 
 	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	// Pages of newer posts are read oldest first so the limit applies next to the cursor.
+	if order == "ASC" {
+		slices.Reverse(posts)
+	}
+
+	return posts, hasMore, nil
+}
+
+func (f *FeedStore) GetUnpublishedPosts(ctx context.Context, authorId uuid.UUID) ([]domain.Post, error) {
+	rows, err := f.db.QueryContext(ctx, "SELECT "+postColumns+" FROM Posts WHERE authorId=$1 AND state <> 'published' AND deleted IS NULL ORDER BY posted DESC, id DESC", authorId)
+	if err != nil {
 		return nil, err
 	}
 
-	return posts, nil
+	return scanPosts(rows)
+}
+
+func (f *FeedStore) GetPostsSince(ctx context.Context, authorId uuid.UUID, since time.Time, limit int) ([]domain.Post, error) {
+	rows, err := f.db.QueryContext(ctx, "SELECT "+postColumns+" FROM Posts WHERE authorId=$1 AND posted >= $2 AND state <> 'draft' AND deleted IS NULL ORDER BY posted DESC, id DESC LIMIT $3", authorId, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPosts(rows)
+}
+
+func (f *FeedStore) GetPinnedPosts(ctx context.Context, filter domain.PostFilter, now time.Time) ([]domain.Post, error) {
+	if len(filter.FellowshipIDs) == 0 && len(filter.CircleIDs) == 0 {
+		return nil, fmt.Errorf("at least one fellowshipID or circleID must be provided")
+	}
+
+	conditions, args := postFilterConditions(filter)
+	conditions = append(conditions, fmt.Sprintf("pinnedUntil > $%d", len(args)+1))
+	args = append(args, now)
+
+	query := fmt.Sprintf("SELECT %s FROM Posts WHERE %s ORDER BY posted DESC, id DESC LIMIT %d", postColumns, strings.Join(conditions, " AND "), maxPinnedPosts)
+
+	rows, err := f.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPosts(rows)
+}
+
+func (f *FeedStore) SearchPosts(ctx context.Context, filter domain.PostFilter, query string, limit *int, cursor *domain.PostSearchCursor) ([]domain.PostSearchResult, *domain.PostSearchCursor, error) {
+	if len(filter.FellowshipIDs) == 0 && len(filter.CircleIDs) == 0 {
+		return nil, nil, fmt.Errorf("at least one fellowshipID or circleID must be provided")
+	}
+
+	conditions, args := postFilterConditions(filter)
+	args = append(args, query, headlineOptions)
+	tsQuery := fmt.Sprintf("websearch_to_tsquery('english', $%d)", len(args)-1)
+	optionsArg := len(args)
+	conditions = append(conditions, "searchVector @@ "+tsQuery)
+
+	// Heading matches weigh more than article matches through the weights of searchVector.
+	inner := fmt.Sprintf("SELECT %s, ts_rank(searchVector, %s)::real AS rank FROM Posts WHERE %s",
+		postColumns, tsQuery, strings.Join(conditions, " AND "))
+	page := "SELECT " + postColumns + ", rank FROM (" + inner + ") AS matches"
+
+	if cursor != nil {
+		page += fmt.Sprintf(" WHERE (rank, id) < ($%d::real, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Rank, cursor.Id)
+	}
+
+	actualLimit := 10 // default limit
+	if limit != nil {
+		actualLimit = max(min(*limit, 100), 1) // enforce a maximum limit and a minimum of 1
+	}
+
+	// Fetch one extra row to find out whether another page follows.
+	page += fmt.Sprintf(" ORDER BY rank DESC, id DESC LIMIT %d", actualLimit+1)
+
+	// Snippets are only made for the rows on the page, as ts_headline is expensive.
+	sqlQuery := fmt.Sprintf("SELECT %s, rank, ts_headline('english', coalesce(heading, '') || ' ' || coalesce(article, ''), %s, $%d) AS snippet FROM (%s) AS page ORDER BY rank DESC, id DESC",
+		postColumns, tsQuery, optionsArg, page)
+
+	rows, err := f.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	results := make([]domain.PostSearchResult, 0, actualLimit)
+	var (
+		lastRank float32
+		next     *domain.PostSearchCursor
+	)
+
+	for rows.Next() {
+		if len(results) == actualLimit {
+			last := results[len(results)-1]
+			next = &domain.PostSearchCursor{Rank: lastRank, Id: last.Post.Id}
+			break
+		}
+
+		var snippet string
+		post, err := scanPost(rows, &lastRank, &snippet)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		results = append(results, domain.PostSearchResult{Post: post, Snippet: highlight(snippet)})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return results, next, nil
 }
 
 func (f *FeedStore) CreatePost(ctx context.Context, post domain.Post) error {
@@ -85,8 +197,437 @@ func (f *FeedStore) CreatePost(ctx context.Context, post domain.Post) error {
 		panic("invalid post id")
 	}
 
-	_, err := f.db.ExecContext(ctx, "INSERT INTO Posts (id, authorId, fellowshipId, circleId, posted, heading, article) VALUES ($1, $2, $3, $4, $5, $6, $7)",
-		post.Id, post.AuthorId, post.FellowshipId, post.CircleId, post.Posted, post.Heading, post.Article)
+	var prayer prayerColumns
+	if post.Prayer != nil {
+		prayer = prayerColumns{
+			status:    sql.NullString{String: string(post.Prayer.Status), Valid: true},
+			testimony: sql.NullString{String: post.Prayer.Testimony, Valid: post.Prayer.Testimony != ""},
+			followUp:  post.Prayer.FollowUp,
+		}
+	}
+
+	entities, err := entitiesJSON(post.Entities)
+	if err != nil {
+		return err
+	}
+
+	var poll []byte
+	if post.Poll != nil {
+		if poll, err = json.Marshal(post.Poll); err != nil {
+			return fmt.Errorf("failed to marshal post poll: %w", err)
+		}
+	}
+
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO Posts (id, authorId, fellowshipId, circleId, posted, kind, heading, article, details, prayerStatus, testimony, followUp, state, scheduledFor, format, articleHtml, entities, tags, hidden, poll) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)",
+		post.Id, post.AuthorId, post.FellowshipId, post.CircleId, post.Posted, post.Kind, post.Heading, post.Article, nullableJSON(post.Details), prayer.status, prayer.testimony, prayer.followUp,
+		post.State, post.ScheduledFor, post.Format, sql.NullString{String: post.ArticleHTML, Valid: post.ArticleHTML != ""}, entities, pq.Array(domain.EntityTags(post.Entities)), post.Hidden, nullableJSON(poll))
+	if err != nil {
+		return err
+	}
+
+	if err := savePostMentions(ctx, tx, post.Id, post.Entities); err != nil {
+		return err
+	}
+
+	if len(post.Attachments) > 0 {
+		attachmentIDs := make([]uuid.UUID, len(post.Attachments))
+		for i, attachment := range post.Attachments {
+			attachmentIDs[i] = attachment.Id
+		}
+
+		result, err := tx.ExecContext(ctx, "UPDATE Attachments SET postId=$1 WHERE id = ANY($2) AND postId IS NULL", post.Id, pq.Array(attachmentIDs))
+		if err != nil {
+			return err
+		}
+
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n != int64(len(attachmentIDs)) {
+			return fmt.Errorf("%w: already attached to another post", domain.ErrInvalidAttachment)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (f *FeedStore) GetPost(ctx context.Context, postId uuid.UUID) (*domain.Post, error) {
+	post, err := scanPost(f.db.QueryRowContext(ctx, "SELECT "+postColumns+" FROM Posts WHERE id=$1 AND deleted IS NULL", postId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (f *FeedStore) GetPostRevisions(ctx context.Context, postId uuid.UUID) ([]domain.PostRevision, error) {
+	rows, err := f.db.QueryContext(ctx, "SELECT revision, editorId, edited, heading, article, details FROM PostRevisions WHERE postId=$1 ORDER BY revision", postId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]domain.PostRevision, 0)
+
+	for rows.Next() {
+		revision := domain.PostRevision{PostId: postId}
+		err := rows.Scan(&revision.Revision, &revision.EditorId, &revision.Edited, &revision.Heading, &revision.Article, nullJSONDest(&revision.Details))
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (f *FeedStore) UpdatePost(ctx context.Context, postId uuid.UUID, editorId uuid.UUID, edit domain.PostEdit, edited time.Time) error {
+	entities, err := entitiesJSON(edit.Entities)
+	if err != nil {
+		return err
+	}
+
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the post so concurrent edits are numbered one after the other.
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT TRUE FROM Posts WHERE id=$1 AND deleted IS NULL FOR UPDATE", postId).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO PostRevisions (postId, revision, editorId, edited, heading, article, details)
+		SELECT id, COALESCE((SELECT MAX(revision) FROM PostRevisions WHERE postId=$1), 0) + 1, $2, $3, heading, article, details
+		FROM Posts WHERE id=$1`, postId, editorId, edited)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE Posts SET heading=$2, article=$3, details=$4, edited=$5, articleHtml=$6, entities=$7, tags=$8 WHERE id=$1",
+		postId, edit.Heading, edit.Article, nullableJSON(edit.Details), edited, sql.NullString{String: edit.ArticleHTML, Valid: edit.ArticleHTML != ""},
+		entities, pq.Array(domain.EntityTags(edit.Entities)))
+	if err != nil {
+		return err
+	}
+
+	if err := savePostMentions(ctx, tx, postId, edit.Entities); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (f *FeedStore) DeletePost(ctx context.Context, postId uuid.UUID, deletedBy uuid.UUID, deleted time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET deleted=$3, deletedBy=$2 WHERE id=$1 AND deleted IS NULL", postId, deletedBy, deleted)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) PublishPost(ctx context.Context, postId uuid.UUID, posted time.Time, hidden *time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET state='published', posted=$2, scheduledFor=NULL, hidden=COALESCE(hidden, $3) WHERE id=$1 AND state <> 'published' AND deleted IS NULL", postId, posted, hidden)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) SchedulePost(ctx context.Context, postId uuid.UUID, publishAt time.Time, hidden *time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET state='scheduled', scheduledFor=$2, hidden=COALESCE(hidden, $3) WHERE id=$1 AND state <> 'published' AND deleted IS NULL", postId, publishAt, hidden)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]domain.Post, error) {
+	// SKIP LOCKED lets several servers run the publisher without publishing a post twice.
+	rows, err := f.db.QueryContext(ctx, `
+		UPDATE Posts SET state='published', posted=$1, scheduledFor=NULL
+		WHERE id IN (
+			SELECT id FROM Posts WHERE state='scheduled' AND scheduledFor <= $1 AND deleted IS NULL
+			ORDER BY scheduledFor LIMIT $2 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+postColumns, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPosts(rows)
+}
+
+func (f *FeedStore) HidePost(ctx context.Context, postId uuid.UUID, hiddenBy uuid.UUID, hidden time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET hidden=COALESCE(hidden, $3), hiddenBy=COALESCE(hiddenBy, $2) WHERE id=$1 AND deleted IS NULL", postId, hiddenBy, hidden)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) HoldPost(ctx context.Context, postId uuid.UUID, hidden time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET hidden=COALESCE(hidden, $2) WHERE id=$1 AND deleted IS NULL", postId, hidden)
+	if err != nil {
+		return err
+	}
 
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) ShowPost(ctx context.Context, postId uuid.UUID) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET hidden=NULL, hiddenBy=NULL WHERE id=$1 AND deleted IS NULL", postId)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) PinPost(ctx context.Context, postId uuid.UUID, pinnedBy uuid.UUID, until time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET pinnedUntil=$3, pinnedBy=$2 WHERE id=$1 AND deleted IS NULL", postId, pinnedBy, until)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) UnpinPost(ctx context.Context, postId uuid.UUID) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET pinnedUntil=NULL, pinnedBy=NULL WHERE id=$1 AND deleted IS NULL", postId)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) SetPrayerState(ctx context.Context, postId uuid.UUID, state domain.PrayerState) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET prayerStatus=$2, testimony=$3, followUp=$4, followUpSent=FALSE, followUpFailed=NULL WHERE id=$1 AND prayerStatus IS NOT NULL AND deleted IS NULL",
+		postId, state.Status, sql.NullString{String: state.Testimony, Valid: state.Testimony != ""}, state.FollowUp)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) GetDuePrayerFollowUps(ctx context.Context, due time.Time, retryFailedBefore time.Time, limit int) ([]domain.PrayerFollowUp, error) {
+	rows, err := f.db.QueryContext(ctx, `
+		SELECT p.id, p.heading, u.displayName, c.accountId
+		FROM Posts p
+		JOIN Users u ON u.id = p.authorId
+		JOIN UserConnections c ON c.userId = p.authorId AND c.signInType = $2
+		WHERE p.prayerStatus = 'active' AND NOT p.followUpSent AND p.followUp <= $1 AND p.deleted IS NULL AND p.state = 'published'
+			AND (p.followUpFailed IS NULL OR p.followUpFailed < $3)
+		ORDER BY p.followUpFailed NULLS FIRST, p.followUp
+		LIMIT $4`, due, domain.SignInTypeLocal, retryFailedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	followUps := make([]domain.PrayerFollowUp, 0)
+
+	for rows.Next() {
+		followUp := domain.PrayerFollowUp{}
+		if err := rows.Scan(&followUp.PostId, &followUp.Heading, &followUp.AuthorName, &followUp.AuthorEmail); err != nil {
+			return nil, err
+		}
+
+		followUps = append(followUps, followUp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return followUps, nil
+}
+
+func (f *FeedStore) MarkPrayerFollowUpSent(ctx context.Context, postId uuid.UUID) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET followUpSent=TRUE WHERE id=$1", postId)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FeedStore) MarkPrayerFollowUpFailed(ctx context.Context, postId uuid.UUID, failed time.Time) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Posts SET followUpFailed=$2 WHERE id=$1", postId, failed)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+// savePostMentions indexes the users mentioned by a post's entities, replacing those it mentioned before.
+func savePostMentions(ctx context.Context, tx *sql.Tx, postId uuid.UUID, entities []domain.PostEntity) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM PostMentions WHERE postId=$1", postId); err != nil {
+		return err
+	}
+
+	userIds := domain.MentionedUsers(entities)
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "INSERT INTO PostMentions (postId, userId) SELECT $1, unnest($2::uuid[])", postId, pq.Array(userIds))
 	return err
 }
+
+// entitiesJSON returns the entities column of a post.
+func entitiesJSON(entities []domain.PostEntity) (string, error) {
+	if len(entities) == 0 {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(entities)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal post entities: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// maxPinnedPosts bounds the pinned section of a feed.
+const maxPinnedPosts = 20
+
+// postColumns are the columns read by scanPost.
+const postColumns = "id, authorId, fellowshipId, circleId, posted, edited, kind, heading, article, details, commentCount, prayerStatus, testimony, followUp, pinnedUntil, state, scheduledFor, format, articleHtml, entities, hidden, poll"
+
+// headlineOptions mark matches in ts_headline with control characters that highlight replaces once
+// the rest of the snippet has been escaped.
+const headlineOptions = "StartSel=\x02, StopSel=\x03, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPost reads postColumns followed by extra columns into a post.
+func scanPost(row scanner, extra ...any) (domain.Post, error) {
+	post := domain.Post{}
+	var prayer prayerColumns
+	var articleHTML sql.NullString
+	var entities, poll []byte
+	dest := []any{&post.Id, &post.AuthorId, &post.FellowshipId, &post.CircleId, &post.Posted, &post.Edited, &post.Kind, &post.Heading, &post.Article, nullJSONDest(&post.Details), &post.CommentCount,
+		&prayer.status, &prayer.testimony, &prayer.followUp, &post.PinnedUntil, &post.State, &post.ScheduledFor,
+		&post.Format, &articleHTML, &entities, &post.Hidden, &poll}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return post, err
+	}
+
+	if err := json.Unmarshal(entities, &post.Entities); err != nil {
+		return post, fmt.Errorf("failed to unmarshal entities of post %s: %w", post.Id, err)
+	}
+
+	if poll != nil {
+		post.Poll = &domain.Poll{}
+		if err := json.Unmarshal(poll, post.Poll); err != nil {
+			return post, fmt.Errorf("failed to unmarshal poll of post %s: %w", post.Id, err)
+		}
+	}
+
+	post.Prayer = prayer.state()
+	post.ArticleHTML = articleHTML.String
+	return post, nil
+}
+
+// scanPosts reads and closes rows of postColumns.
+func scanPosts(rows *sql.Rows) ([]domain.Post, error) {
+	defer rows.Close()
+
+	posts := make([]domain.Post, 0)
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// postFilterConditions returns the WHERE conditions and arguments selecting the visible posts that match filter.
+func postFilterConditions(filter domain.PostFilter) ([]string, []any) {
+	conditions := []string{"(fellowshipId = ANY($1) OR circleId = ANY($2))", "deleted IS NULL", "state = 'published'"}
+	args := []any{pq.Array(filter.FellowshipIDs), pq.Array(filter.CircleIDs)}
+
+	if filter.AuthorId != uuid.Nil {
+		conditions = append(conditions, fmt.Sprintf("authorId = $%d", len(args)+1))
+		args = append(args, filter.AuthorId)
+	}
+
+	if filter.Kind != "" {
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)+1))
+		args = append(args, filter.Kind)
+	}
+
+	if filter.PrayerStatus != nil {
+		conditions = append(conditions, fmt.Sprintf("prayerStatus = $%d", len(args)+1))
+		args = append(args, *filter.PrayerStatus)
+	}
+
+	// Hidden posts are only seen where the user moderates, which includes the circles of the fellowships they moderate.
+	conditions = append(conditions, fmt.Sprintf("(hidden IS NULL OR fellowshipId = ANY($%[1]d) OR circleId = ANY($%[2]d) OR circleId IN (SELECT id FROM FellowshipCircles WHERE fellowshipId = ANY($%[1]d)))",
+		len(args)+1, len(args)+2))
+	args = append(args, pq.Array(filter.ModeratedFellowshipIDs), pq.Array(filter.ModeratedCircleIDs))
+
+	if filter.Tag != "" {
+		// Containment rather than ANY so the GIN index on tags is used.
+		conditions = append(conditions, fmt.Sprintf("tags @> ARRAY[$%d]::text[]", len(args)+1))
+		args = append(args, filter.Tag)
+	}
+
+	return conditions, args
+}
+
+// highlight escapes a ts_headline snippet and turns its match markers into <mark> elements.
+func highlight(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	return strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>").Replace(escaped)
+}
+
+// prayerColumns scans the nullable prayer lifecycle columns of a post.
+type prayerColumns struct {
+	status    sql.NullString
+	testimony sql.NullString
+	followUp  *time.Time
+}
+
+func (p prayerColumns) state() *domain.PrayerState {
+	if !p.status.Valid {
+		return nil
+	}
+
+	return &domain.PrayerState{Status: domain.PrayerStatus(p.status.String), Testimony: p.testimony.String, FollowUp: p.followUp}
+}
@@ -0,0 +1,127 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// postRow is a published plain post in postColumns order with the given details.
+func postRow(id uuid.UUID, details driver.Value) []driver.Value {
+	return []driver.Value{
+		id.String(), uuid.NewString(), uuid.NewString(), uuid.Nil.String(), time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), nil,
+		"general", "Heading", "Article", details, int64(0),
+		nil, nil, nil, nil, "published", nil,
+		"plain", nil, []byte("[]"), nil, nil,
+	}
+}
+
+func TestGetPostReadsDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		details driver.Value
+		want    string
+	}{
+		{name: "null", details: nil, want: ""},
+		{name: "object", details: []byte(`{"location":"Hall"}`), want: `{"location":"Hall"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id := uuid.New()
+			db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+				return fakeRows{columns: columnsOf(postColumns), rows: [][]driver.Value{postRow(id, test.details)}}, nil
+			})
+
+			post, err := NewFeedStore(db).GetPost(context.Background(), id)
+			if err != nil {
+				t.Fatalf("GetPost: %v", err)
+			}
+
+			if post.Id != id || string(post.Details) != test.want {
+				t.Errorf("GetPost = id %s details %q, want id %s details %q", post.Id, post.Details, id, test.want)
+			}
+		})
+	}
+}
+
+func TestGetPostsSinceReadsPostsWithoutDetails(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		// Drafts must not count towards the rate limit or duplicate checks, but scheduled posts must.
+		if !strings.Contains(query, "state <> 'draft'") {
+			t.Errorf("query %q does not leave out only drafts", query)
+		}
+
+		return fakeRows{columns: columnsOf(postColumns), rows: [][]driver.Value{postRow(first, nil), postRow(second, []byte(`{}`))}}, nil
+	})
+
+	posts, err := NewFeedStore(db).GetPostsSince(context.Background(), uuid.New(), time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetPostsSince: %v", err)
+	}
+
+	if len(posts) != 2 || posts[0].Id != first || posts[0].Details != nil || string(posts[1].Details) != "{}" {
+		t.Errorf("GetPostsSince = %+v, want posts %s without details and %s with {}", posts, first, second)
+	}
+}
+
+func TestGetPostRevisionsReadsRevisionsWithoutDetails(t *testing.T) {
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		if !strings.Contains(query, "FROM PostRevisions") {
+			t.Errorf("unexpected query %q", query)
+		}
+
+		edited := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		return fakeRows{
+			columns: []string{"revision", "editorId", "edited", "heading", "article", "details"},
+			rows: [][]driver.Value{
+				{int64(1), uuid.NewString(), edited, "Heading", "Article", nil},
+				{int64(2), uuid.NewString(), edited.Add(time.Hour), "Heading", "Edited", []byte(`{"a":1}`)},
+			},
+		}, nil
+	})
+
+	revisions, err := NewFeedStore(db).GetPostRevisions(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetPostRevisions: %v", err)
+	}
+
+	if len(revisions) != 2 || revisions[0].Details != nil || string(revisions[1].Details) != `{"a":1}` {
+		t.Errorf("GetPostRevisions = %+v, want revision 1 without details and revision 2 with {\"a\":1}", revisions)
+	}
+}
+
+func TestSearchPostsMakesSnippetsForThePageOnly(t *testing.T) {
+	id := uuid.New()
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		// The snippet is made by the outermost SELECT, after the page has been limited, and copes
+		// with posts without a heading or article.
+		page := strings.Index(query, "FROM (")
+		if headline := strings.Index(query, "ts_headline"); headline < 0 || headline > page || strings.Count(query, "ts_headline") != 1 {
+			t.Errorf("query %q does not make snippets after the limit", query)
+		}
+
+		if !strings.Contains(query, "coalesce(heading, '') || ' ' || coalesce(article, '')") {
+			t.Errorf("query %q makes no snippet for posts without a heading or article", query)
+		}
+
+		row := append(postRow(id, nil), float32(0.5), "a \x02match\x03 <b>")
+		return fakeRows{columns: append(columnsOf(postColumns), "rank", "snippet"), rows: [][]driver.Value{row}}, nil
+	})
+
+	filter := domain.PostFilter{FellowshipIDs: []uuid.UUID{uuid.New()}}
+	results, next, err := NewFeedStore(db).SearchPosts(context.Background(), filter, "match", nil, nil)
+	if err != nil {
+		t.Fatalf("SearchPosts: %v", err)
+	}
+
+	if len(results) != 1 || next != nil || results[0].Post.Id != id || results[0].Snippet != "a <mark>match</mark> &lt;b&gt;" {
+		t.Errorf("SearchPosts = %+v, next %v, want post %s with an escaped, highlighted snippet", results, next, id)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func NewFellowshipStore(db *sql.DB) *FellowshipStore {
@@ -18,7 +22,7 @@ type FellowshipStore struct {
 }
 
 func (f *FellowshipStore) GetUserFellowships(ctx context.Context, userId uuid.UUID) ([]domain.Fellowship, error) {
-	rows, err := f.db.QueryContext(ctx, "SELECT id, name, creator FROM Fellowships WHERE id in (SELECT fellowshipId FROM FellowshipMembers WHERE userId=$1)", userId)
+	rows, err := f.db.QueryContext(ctx, "SELECT id, parentId, name, description, isPublic, creator, timezone FROM Fellowships WHERE id in (SELECT fellowshipId FROM FellowshipMembers WHERE userId=$1)", userId)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +32,7 @@ func (f *FellowshipStore) GetUserFellowships(ctx context.Context, userId uuid.UU
 
 	for rows.Next() {
 		fellowship := domain.Fellowship{}
-		err := rows.Scan(&fellowship.Id, &fellowship.Name, &fellowship.CreatorId)
+		err := rows.Scan(&fellowship.Id, &fellowship.ParentId, &fellowship.Name, &fellowship.Description, &fellowship.IsPublic, &fellowship.CreatorId, &fellowship.Timezone)
 		if err != nil {
 			return nil, err
 		}
@@ -69,19 +73,166 @@ func (f *FellowshipStore) GetUserFellowshipIDs(ctx context.Context, userId uuid.
 	return fellowshipIds, nil
 }
 
+func (f *FellowshipStore) GetFellowship(ctx context.Context, fellowshipId uuid.UUID) (*domain.Fellowship, error) {
+	fellowship := &domain.Fellowship{Id: fellowshipId}
+
+	err := f.db.QueryRowContext(ctx, "SELECT parentId, name, description, isPublic, creator, timezone FROM Fellowships WHERE id=$1", fellowshipId).
+		Scan(&fellowship.ParentId, &fellowship.Name, &fellowship.Description, &fellowship.IsPublic, &fellowship.CreatorId, &fellowship.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return fellowship, nil
+}
+
+func (f *FellowshipStore) GetChildFellowships(ctx context.Context, parentId uuid.UUID) ([]domain.Fellowship, error) {
+	rows, err := f.db.QueryContext(ctx, "SELECT id, parentId, name, description, isPublic, creator, timezone FROM Fellowships WHERE parentId=$1 ORDER BY name", parentId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fellowships := make([]domain.Fellowship, 0)
+
+	for rows.Next() {
+		fellowship := domain.Fellowship{}
+		err := rows.Scan(&fellowship.Id, &fellowship.ParentId, &fellowship.Name, &fellowship.Description, &fellowship.IsPublic, &fellowship.CreatorId, &fellowship.Timezone)
+		if err != nil {
+			return nil, err
+		}
+
+		fellowships = append(fellowships, fellowship)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fellowships, nil
+}
+
+func (f *FellowshipStore) GetFellowshipAncestorIDs(ctx context.Context, fellowshipId uuid.UUID) ([]uuid.UUID, error) {
+	const query = `
+		WITH RECURSIVE ancestors(id, depth) AS (
+			SELECT parentId, 1 FROM Fellowships WHERE id=$1 AND parentId IS NOT NULL
+			UNION ALL
+			SELECT f.parentId, a.depth + 1 FROM Fellowships f JOIN ancestors a ON f.id = a.id
+			WHERE f.parentId IS NOT NULL AND a.depth < $2
+		)
+		SELECT id FROM ancestors ORDER BY depth`
+
+	rows, err := f.db.QueryContext(ctx, query, fellowshipId, domain.FellowshipMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ancestorIds := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		ancestorId := uuid.UUID{}
+		if err := rows.Scan(&ancestorId); err != nil {
+			return nil, err
+		}
+
+		ancestorIds = append(ancestorIds, ancestorId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ancestorIds, nil
+}
+
+func (f *FellowshipStore) GetFellowshipSubtreeHeight(ctx context.Context, fellowshipId uuid.UUID) (int, error) {
+	const query = `
+		WITH RECURSIVE descendants(id, depth) AS (
+			SELECT id, 0 FROM Fellowships WHERE id=$1
+			UNION ALL
+			SELECT f.id, d.depth + 1 FROM Fellowships f JOIN descendants d ON f.parentId = d.id
+			WHERE d.depth < $2
+		)
+		SELECT MAX(depth) FROM descendants`
+
+	var height sql.NullInt32
+
+	err := f.db.QueryRowContext(ctx, query, fellowshipId, domain.FellowshipMaxDepth).Scan(&height)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(height.Int32), nil
+}
+
 func (f *FellowshipStore) GetUserAccessLevel(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (domain.AccessLevel, error) {
-	accessLevel := domain.NoAccess
+	// Membership of the fellowship itself counts as-is. Membership of an ancestor only counts
+	// for its Owners and Admins, who are capped at Admin on the fellowships beneath them.
+	const query = `
+		WITH RECURSIVE lineage(id, depth) AS (
+			SELECT id, 0 FROM Fellowships WHERE id=$2
+			UNION ALL
+			SELECT f.parentId, l.depth + 1 FROM Fellowships f JOIN lineage l ON f.id = l.id
+			WHERE f.parentId IS NOT NULL AND l.depth < $4
+		)
+		SELECT MIN(CASE WHEN l.depth = 0 THEN m.access ELSE GREATEST(m.access, $3) END)
+		FROM lineage l JOIN FellowshipMembers m ON m.fellowshipId = l.id AND m.userId = $1
+		WHERE l.depth = 0 OR m.access <= $3`
+
+	var accessLevel sql.NullInt32
 
-	err := f.db.QueryRowContext(ctx, "SELECT access FROM FellowshipMembers WHERE userId=$1 AND fellowshipId=$2", userId, fellowshipId).Scan(&accessLevel)
+	err := f.db.QueryRowContext(ctx, query, userId, fellowshipId, domain.Admin, domain.FellowshipMaxDepth).Scan(&accessLevel)
 	if err != nil {
 		return domain.NoAccess, err
 	}
 
-	return accessLevel, nil
+	if !accessLevel.Valid {
+		return domain.NoAccess, domain.ErrNotMember
+	}
+
+	return domain.AccessLevel(accessLevel.Int32), nil
+}
+
+func (f *FellowshipStore) GetModeratedFellowshipIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	// Moderators moderate their own fellowships. Owners and Admins also moderate every fellowship
+	// beneath theirs, down to the depth GetUserAccessLevel looks up.
+	const query = `
+		WITH RECURSIVE managed(id, depth) AS (
+			SELECT fellowshipId, 0 FROM FellowshipMembers WHERE userId=$1 AND access <= $3
+			UNION
+			SELECT f.id, m.depth + 1 FROM Fellowships f JOIN managed m ON f.parentId = m.id
+			WHERE m.depth < $4
+		)
+		SELECT id FROM managed
+		UNION
+		SELECT fellowshipId FROM FellowshipMembers WHERE userId=$1 AND access <= $2`
+
+	rows, err := f.db.QueryContext(ctx, query, userId, domain.Moderator, domain.Admin, domain.FellowshipMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fellowshipIds := make([]uuid.UUID, 0)
+
+	for rows.Next() {
+		var fellowshipId uuid.UUID
+		if err := rows.Scan(&fellowshipId); err != nil {
+			return nil, err
+		}
+
+		fellowshipIds = append(fellowshipIds, fellowshipId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fellowshipIds, nil
 }
 
 func (f *FellowshipStore) GetFellowshipMembers(ctx context.Context, fellowshipId uuid.UUID) ([]domain.FellowshipMember, error) {
-	rows, err := f.db.QueryContext(ctx, "SELECT userId, access FROM FellowshipMembers WHERE fellowshipId=$1", fellowshipId)
+	rows, err := f.db.QueryContext(ctx, "SELECT userId, access, followParentNotices, worshipLeader, joined FROM FellowshipMembers WHERE fellowshipId=$1", fellowshipId)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +242,7 @@ func (f *FellowshipStore) GetFellowshipMembers(ctx context.Context, fellowshipId
 
 	for rows.Next() {
 		member := domain.FellowshipMember{FellowshipId: fellowshipId}
-		if err := rows.Scan(&member.UserId, &member.Access); err != nil {
+		if err := rows.Scan(&member.UserId, &member.Access, &member.FollowParentNotices, &member.WorshipLeader, &member.Joined); err != nil {
 			return nil, err
 		}
 		members = append(members, member)
@@ -104,11 +255,197 @@ func (f *FellowshipStore) GetFellowshipMembers(ctx context.Context, fellowshipId
 	return members, nil
 }
 
+func (f *FellowshipStore) GetFellowshipMembersNamed(ctx context.Context, fellowshipId uuid.UUID, names []string) ([]domain.User, error) {
+	rows, err := f.db.QueryContext(ctx, `SELECT u.id, u.displayName, u.created FROM Users u JOIN FellowshipMembers m ON m.userId = u.id
+		WHERE m.fellowshipId=$1 AND lower(btrim(u.displayName)) = ANY($2) AND NOT u.isDeleted`, fellowshipId, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+
+	for rows.Next() {
+		user := domain.User{}
+		if err := rows.Scan(&user.Id, &user.DisplayName, &user.Created); err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (f *FellowshipStore) GetMemberJoined(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (time.Time, error) {
+	var joined time.Time
+	err := f.db.QueryRowContext(ctx, "SELECT joined FROM FellowshipMembers WHERE fellowshipId=$1 AND userId=$2", fellowshipId, userId).Scan(&joined)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, domain.ErrNotMember
+	}
+
+	return joined, err
+}
+
+func (f *FellowshipStore) IsWorshipLeader(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (bool, error) {
+	var leader bool
+	err := f.db.QueryRowContext(ctx, "SELECT worshipLeader FROM FellowshipMembers WHERE fellowshipId=$1 AND userId=$2", fellowshipId, userId).Scan(&leader)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	return leader, err
+}
+
+func (f *FellowshipStore) SearchPublicFellowships(ctx context.Context, query string, limit *int, cursor *domain.FellowshipSearchCursor) ([]domain.Fellowship, *domain.FellowshipSearchCursor, error) {
+	var (
+		args       []any
+		conditions []string
+	)
+
+	rank := "0::real"
+	conditions = append(conditions, "isPublic")
+
+	if query = strings.TrimSpace(query); query != "" {
+		args = append(args, query)
+		rank = "ts_rank(searchVector, websearch_to_tsquery('english', $1))::real"
+		conditions = append(conditions, "searchVector @@ websearch_to_tsquery('english', $1)")
+	}
+
+	inner := fmt.Sprintf("SELECT id, parentId, name, description, isPublic, creator, timezone, %s AS rank FROM Fellowships WHERE %s", rank, strings.Join(conditions, " AND "))
+	sqlQuery := "SELECT id, parentId, name, description, isPublic, creator, timezone, rank FROM (" + inner + ") AS matches"
+
+	// Ordering by (rank, id) descending gives a stable order even when every rank is equal.
+	if cursor != nil {
+		sqlQuery += fmt.Sprintf(" WHERE (rank, id) < ($%d::real, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Rank, cursor.Id)
+	}
+
+	actualLimit := 10 // default limit
+	if limit != nil {
+		actualLimit = max(min(*limit, 100), 1) // enforce a maximum limit and a minimum of 1
+	}
+
+	// Fetch one extra row to find out whether another page follows.
+	sqlQuery += fmt.Sprintf(" ORDER BY rank DESC, id DESC LIMIT %d", actualLimit+1)
+
+	rows, err := f.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	fellowships := make([]domain.Fellowship, 0, actualLimit)
+	var (
+		lastRank float32
+		next     *domain.FellowshipSearchCursor
+	)
+
+	for rows.Next() {
+		if len(fellowships) == actualLimit {
+			last := fellowships[len(fellowships)-1]
+			next = &domain.FellowshipSearchCursor{Rank: lastRank, Id: last.Id}
+			break
+		}
+
+		fellowship := domain.Fellowship{}
+		err := rows.Scan(&fellowship.Id, &fellowship.ParentId, &fellowship.Name, &fellowship.Description, &fellowship.IsPublic, &fellowship.CreatorId, &fellowship.Timezone, &lastRank)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fellowships = append(fellowships, fellowship)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return fellowships, next, nil
+}
+
 func (f *FellowshipStore) CreateFellowship(ctx context.Context, fellowship domain.Fellowship) error {
 	return errors.New("not implemented")
 }
 
 func (f *FellowshipStore) AddFellowshipMember(ctx context.Context, member domain.FellowshipMember) error {
-	_, err := f.db.ExecContext(ctx, "INSERT INTO FellowshipMembers (fellowshipId, userId, access) VALUES ($1, $2, $3)", member.FellowshipId, member.UserId, member.Access)
+	_, err := f.db.ExecContext(ctx, "INSERT INTO FellowshipMembers (fellowshipId, userId, access, followParentNotices) VALUES ($1, $2, $3, $4)", member.FellowshipId, member.UserId, member.Access, member.FollowParentNotices)
 	return err
 }
+
+func (f *FellowshipStore) RemoveFellowshipMember(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID) error {
+	tx, err := f.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	circles := "SELECT id FROM FellowshipCircles WHERE fellowshipId=$1"
+	if _, err := tx.ExecContext(ctx, "DELETE FROM CircleMembers WHERE userId=$2 AND circleId IN ("+circles+")", fellowshipId, userId); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM CircleJoinRequests WHERE userId=$2 AND circleId IN ("+circles+")", fellowshipId, userId); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM FellowshipMembers WHERE fellowshipId=$1 AND userId=$2", fellowshipId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (f *FellowshipStore) SetFellowshipParent(ctx context.Context, fellowshipId uuid.UUID, parentId *uuid.UUID) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Fellowships SET parentId=$2 WHERE id=$1", fellowshipId, parentId)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FellowshipStore) SetFellowshipTimezone(ctx context.Context, fellowshipId uuid.UUID, timezone string) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Fellowships SET timezone=$2 WHERE id=$1", fellowshipId, timezone)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FellowshipStore) SetFellowshipDetails(ctx context.Context, fellowshipId uuid.UUID, description string, isPublic bool) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE Fellowships SET description=$2, isPublic=$3 WHERE id=$1", fellowshipId, description, isPublic)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FellowshipStore) SetFollowParentNotices(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID, follow bool) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE FellowshipMembers SET followParentNotices=$3 WHERE fellowshipId=$1 AND userId=$2", fellowshipId, userId, follow)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
+
+func (f *FellowshipStore) SetWorshipLeader(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID, leader bool) error {
+	result, err := f.db.ExecContext(ctx, "UPDATE FellowshipMembers SET worshipLeader=$3 WHERE fellowshipId=$1 AND userId=$2", fellowshipId, userId, leader)
+	if err != nil {
+		return err
+	}
+
+	return expectRowsAffected(result)
+}
@@ -0,0 +1,10 @@
+ALTER TABLE Fellowships ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE Fellowships ADD COLUMN IF NOT EXISTS isPublic BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE Fellowships ADD COLUMN IF NOT EXISTS searchVector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_fellowships_searchvector ON Fellowships USING GIN (searchVector);
+CREATE INDEX IF NOT EXISTS idx_fellowships_public ON Fellowships(id) WHERE isPublic;
@@ -0,0 +1,6 @@
+ALTER TABLE Fellowships ADD COLUMN IF NOT EXISTS parentId UUID REFERENCES Fellowships(id);
+ALTER TABLE Fellowships ADD CONSTRAINT chk_fellowships_parent_not_self CHECK (parentId IS NULL OR parentId <> id);
+ALTER TABLE FellowshipMembers ADD COLUMN IF NOT EXISTS followParentNotices BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_fellowships_parentid ON Fellowships(parentId);
+CREATE INDEX IF NOT EXISTS idx_fellowshipcircles_fellowshipid ON FellowshipCircles(fellowshipId);
@@ -0,0 +1,10 @@
+ALTER TABLE FellowshipCircles ADD COLUMN IF NOT EXISTS accessMode INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS CircleJoinRequests (
+    circleId UUID REFERENCES FellowshipCircles(id),
+    userId UUID REFERENCES Users(id),
+    requested TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (circleId, userId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fellowshipcircles_open ON FellowshipCircles(fellowshipId) WHERE accessMode = 2;
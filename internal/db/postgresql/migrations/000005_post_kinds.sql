@@ -0,0 +1,5 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'general';
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS details JSONB;
+
+UPDATE Posts SET kind = CASE c.type WHEN 0 THEN 'notice' WHEN 1 THEN 'prayer' ELSE Posts.kind END
+FROM FellowshipCircles c WHERE Posts.circleId = c.id;
@@ -0,0 +1,24 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS edited TIMESTAMPTZ;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS deleted TIMESTAMPTZ;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS deletedBy UUID REFERENCES Users(id);
+
+CREATE TABLE IF NOT EXISTS PostRevisions (
+    postId UUID NOT NULL REFERENCES Posts(id),
+    revision INTEGER NOT NULL,
+    editorId UUID NOT NULL REFERENCES Users(id),
+    edited TIMESTAMPTZ NOT NULL,
+    heading VARCHAR(80),
+    article TEXT,
+    details JSONB,
+    PRIMARY KEY (postId, revision)
+);
+
+CREATE OR REPLACE FUNCTION reject_post_revision_changes() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'PostRevisions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_postrevisions_immutable ON PostRevisions;
+CREATE TRIGGER trg_postrevisions_immutable BEFORE UPDATE OR DELETE ON PostRevisions
+    FOR EACH ROW EXECUTE FUNCTION reject_post_revision_changes();
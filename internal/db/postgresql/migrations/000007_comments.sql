@@ -0,0 +1,15 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS commentCount INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS Comments (
+    id UUID PRIMARY KEY,
+    postId UUID NOT NULL REFERENCES Posts(id),
+    parentId UUID REFERENCES Comments(id),
+    authorId UUID NOT NULL REFERENCES Users(id),
+    created TIMESTAMPTZ NOT NULL,
+    edited TIMESTAMPTZ,
+    deleted TIMESTAMPTZ,
+    deletedBy UUID REFERENCES Users(id),
+    body TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_postid_created ON Comments(postId, created, id) WHERE deleted IS NULL;
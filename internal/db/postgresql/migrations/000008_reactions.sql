@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS PostReactions (
+    postId UUID NOT NULL REFERENCES Posts(id),
+    kind TEXT NOT NULL,
+    userId UUID NOT NULL REFERENCES Users(id),
+    reacted TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (postId, kind, userId)
+);
+
+-- Running totals of PostReactions, kept in step by the reaction store so feeds never count rows.
+CREATE TABLE IF NOT EXISTS PostReactionCounts (
+    postId UUID NOT NULL REFERENCES Posts(id),
+    kind TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0 CHECK (count >= 0),
+    PRIMARY KEY (postId, kind)
+);
+
+CREATE INDEX IF NOT EXISTS idx_postreactions_userid ON PostReactions(userId, postId);
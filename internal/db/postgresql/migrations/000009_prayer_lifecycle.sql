@@ -0,0 +1,9 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS prayerStatus TEXT;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS testimony TEXT;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS followUp TIMESTAMPTZ;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS followUpSent BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE Posts SET prayerStatus = 'active' WHERE kind = 'prayer' AND prayerStatus IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_prayerstatus ON Posts(prayerStatus, posted) WHERE prayerStatus IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_followup ON Posts(followUp) WHERE prayerStatus = 'active' AND NOT followUpSent;
@@ -0,0 +1,8 @@
+-- Feed pages are ordered by (posted, id) so posts sharing a timestamp are neither skipped nor repeated.
+CREATE INDEX IF NOT EXISTS idx_posts_posted_id ON Posts(posted, id) WHERE deleted IS NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_fellowshipid_posted_id ON Posts(fellowshipId, posted, id) WHERE deleted IS NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_circleid_posted_id ON Posts(circleId, posted, id) WHERE deleted IS NULL;
+
+DROP INDEX IF EXISTS idx_posts_posted;
+DROP INDEX IF EXISTS idx_posts_fellowshipid;
+DROP INDEX IF EXISTS idx_posts_circleid;
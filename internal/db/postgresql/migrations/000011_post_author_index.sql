@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_posts_authorid_posted_id ON Posts(authorId, posted, id) WHERE deleted IS NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS searchVector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(heading, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(article, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_posts_searchvector ON Posts USING GIN (searchVector);
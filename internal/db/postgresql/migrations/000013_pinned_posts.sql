@@ -0,0 +1,4 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS pinnedUntil TIMESTAMPTZ;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS pinnedBy UUID REFERENCES Users(id);
+
+CREATE INDEX IF NOT EXISTS idx_posts_pinneduntil ON Posts(pinnedUntil) WHERE pinnedUntil IS NOT NULL AND deleted IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS state TEXT NOT NULL DEFAULT 'published';
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS scheduledFor TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_posts_scheduledfor ON Posts(scheduledFor) WHERE state = 'scheduled' AND deleted IS NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_unpublished_authorid ON Posts(authorId) WHERE state <> 'published' AND deleted IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS format TEXT NOT NULL DEFAULT 'plain';
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS articleHtml TEXT;
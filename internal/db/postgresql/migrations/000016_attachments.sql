@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS Attachments (
+    id UUID PRIMARY KEY,
+    fellowshipId UUID NOT NULL REFERENCES Fellowships(id),
+    circleId UUID REFERENCES FellowshipCircles(id),
+    uploaderId UUID NOT NULL REFERENCES Users(id),
+    postId UUID REFERENCES Posts(id),
+    name TEXT NOT NULL,
+    contentType TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    uploaded TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_postid ON Attachments(postId) WHERE postId IS NOT NULL;
+
+-- Storage used by each fellowship's attachments, kept in step with Attachments so quotas can be
+-- checked without summing sizes.
+CREATE TABLE IF NOT EXISTS FellowshipStorage (
+    fellowshipId UUID PRIMARY KEY REFERENCES Fellowships(id),
+    used BIGINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,5 @@
+ALTER TABLE Attachments ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'ready';
+ALTER TABLE Attachments ADD COLUMN IF NOT EXISTS width INTEGER;
+ALTER TABLE Attachments ADD COLUMN IF NOT EXISTS height INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_processing ON Attachments(uploaded) WHERE status = 'processing';
@@ -0,0 +1,13 @@
+-- Mentions and tags found in each post's article, with their offsets for clients to link.
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS entities JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_posts_tags ON Posts USING GIN (tags);
+
+CREATE TABLE IF NOT EXISTS PostMentions (
+    postId UUID NOT NULL REFERENCES Posts(id),
+    userId UUID NOT NULL REFERENCES Users(id),
+    PRIMARY KEY (postId, userId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_postmentions_userid ON PostMentions(userId);
@@ -0,0 +1,9 @@
+-- The newest post each user has read in each fellowship or circle. containerId is a fellowship ID
+-- or a circle ID, which never collide.
+CREATE TABLE IF NOT EXISTS ReadMarkers (
+    userId UUID NOT NULL REFERENCES Users(id),
+    containerId UUID NOT NULL,
+    posted TIMESTAMPTZ NOT NULL,
+    postId UUID NOT NULL REFERENCES Posts(id),
+    PRIMARY KEY (userId, containerId)
+);
@@ -0,0 +1,39 @@
+-- Hidden content stays in place for moderators but disappears for everyone else.
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS hidden TIMESTAMPTZ;
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS hiddenBy UUID REFERENCES Users(id);
+ALTER TABLE Comments ADD COLUMN IF NOT EXISTS hidden TIMESTAMPTZ;
+ALTER TABLE Comments ADD COLUMN IF NOT EXISTS hiddenBy UUID REFERENCES Users(id);
+
+CREATE TABLE IF NOT EXISTS Reports (
+    id UUID PRIMARY KEY,
+    fellowshipId UUID NOT NULL REFERENCES Fellowships(id),
+    reporterId UUID NOT NULL REFERENCES Users(id),
+    postId UUID NOT NULL REFERENCES Posts(id),
+    commentId UUID REFERENCES Comments(id),
+    authorId UUID NOT NULL REFERENCES Users(id),
+    reason TEXT NOT NULL,
+    created TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open',
+    resolvedBy UUID REFERENCES Users(id),
+    resolved TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_open ON Reports(fellowshipId, created, id) WHERE status = 'open';
+-- A member can only have one open report on each post or comment.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_reporter ON Reports(reporterId, COALESCE(commentId, postId)) WHERE status = 'open';
+
+-- Append-only record of every moderator action.
+CREATE TABLE IF NOT EXISTS ModerationLog (
+    id UUID PRIMARY KEY,
+    fellowshipId UUID NOT NULL REFERENCES Fellowships(id),
+    moderatorId UUID NOT NULL REFERENCES Users(id),
+    action TEXT NOT NULL,
+    reportId UUID REFERENCES Reports(id),
+    targetUserId UUID NOT NULL REFERENCES Users(id),
+    postId UUID REFERENCES Posts(id),
+    commentId UUID REFERENCES Comments(id),
+    note TEXT NOT NULL DEFAULT '',
+    created TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderationlog_fellowshipid ON ModerationLog(fellowshipId, created, id);
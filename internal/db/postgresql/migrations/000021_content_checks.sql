@@ -0,0 +1,9 @@
+-- Posts held for review by the content checks are queued as reports without a reporter.
+ALTER TABLE Reports ALTER COLUMN reporterId DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS BlockedWords (
+    fellowshipId UUID NOT NULL REFERENCES Fellowships(id),
+    word TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    PRIMARY KEY (fellowshipId, word)
+);
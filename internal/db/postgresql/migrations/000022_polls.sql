@@ -0,0 +1,11 @@
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS poll JSONB;
+
+-- One vote per member on each poll. A multiple choice vote lists every option chosen. Voters are
+-- kept for anonymous polls too, to enforce this, but are never shown.
+CREATE TABLE IF NOT EXISTS PollVotes (
+    postId UUID NOT NULL REFERENCES Posts(id),
+    userId UUID NOT NULL REFERENCES Users(id),
+    options INTEGER[] NOT NULL,
+    voted TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (postId, userId)
+);
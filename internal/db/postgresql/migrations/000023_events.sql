@@ -0,0 +1,43 @@
+ALTER TABLE Fellowships ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Events belong to a fellowship or a circle, like posts. start and "end" are the times of the first
+-- occurrence, and seriesEnd when the last one ends, NULL for rules that repeat forever.
+CREATE TABLE IF NOT EXISTS Events (
+    id UUID PRIMARY KEY,
+    fellowshipId UUID,
+    circleId UUID,
+    creatorId UUID NOT NULL REFERENCES Users(id),
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    start TIMESTAMPTZ NOT NULL,
+    "end" TIMESTAMPTZ NOT NULL,
+    timezone TEXT NOT NULL,
+    rrule TEXT NOT NULL DEFAULT '',
+    seriesEnd TIMESTAMPTZ,
+    created TIMESTAMPTZ NOT NULL,
+    edited TIMESTAMPTZ,
+    deleted TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_fellowshipid ON Events(fellowshipId, start) WHERE deleted IS NULL;
+CREATE INDEX IF NOT EXISTS idx_events_circleid ON Events(circleId, start) WHERE deleted IS NULL;
+
+-- Occurrences are identified by the time the event's rule has them start.
+CREATE TABLE IF NOT EXISTS EventExceptions (
+    eventId UUID NOT NULL REFERENCES Events(id),
+    occurrence TIMESTAMPTZ NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    start TIMESTAMPTZ,
+    "end" TIMESTAMPTZ,
+    PRIMARY KEY (eventId, occurrence)
+);
+
+CREATE TABLE IF NOT EXISTS EventRSVPs (
+    eventId UUID NOT NULL REFERENCES Events(id),
+    occurrence TIMESTAMPTZ NOT NULL,
+    userId UUID NOT NULL REFERENCES Users(id),
+    response TEXT NOT NULL,
+    responded TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (eventId, occurrence, userId)
+);
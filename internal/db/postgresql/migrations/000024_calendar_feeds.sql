@@ -0,0 +1,7 @@
+-- Calendar feed tokens are kept as SHA-256 hashes. Each user has at most one, and replacing or
+-- deleting it revokes the old link.
+CREATE TABLE IF NOT EXISTS CalendarFeeds (
+    userId UUID PRIMARY KEY REFERENCES Users(id),
+    tokenHash BYTEA NOT NULL UNIQUE,
+    created TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,38 @@
+-- Worship leaders can edit their fellowship's song library without being its Admins.
+ALTER TABLE FellowshipMembers ADD COLUMN IF NOT EXISTS worshipLeader BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- array_to_string is only STABLE, so generated columns cannot call it directly. Joining text is
+-- immutable in practice.
+CREATE OR REPLACE FUNCTION song_authors_text(authors TEXT[]) RETURNS TEXT AS $$
+    SELECT array_to_string(authors, ' ')
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE TABLE IF NOT EXISTS Songs (
+    id UUID PRIMARY KEY,
+    fellowshipId UUID NOT NULL REFERENCES Fellowships(id),
+    title TEXT NOT NULL,
+    authors TEXT[] NOT NULL DEFAULT '{}',
+    ccliNumber TEXT NOT NULL DEFAULT '',
+    copyright TEXT NOT NULL DEFAULT '',
+    defaultKey TEXT NOT NULL DEFAULT '',
+    tempo INTEGER NOT NULL DEFAULT 0,
+    timeSignature TEXT NOT NULL DEFAULT '',
+    themes TEXT[] NOT NULL DEFAULT '{}',
+    lyrics TEXT NOT NULL DEFAULT '',
+    creatorId UUID NOT NULL REFERENCES Users(id),
+    created TIMESTAMPTZ NOT NULL,
+    edited TIMESTAMPTZ,
+    deleted TIMESTAMPTZ,
+    searchVector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', song_authors_text(authors)), 'B') ||
+        setweight(to_tsvector('english', lyrics), 'C')
+    ) STORED
+);
+
+CREATE INDEX IF NOT EXISTS idx_songs_fellowshipid ON Songs(fellowshipId, title) WHERE deleted IS NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_searchvector ON Songs USING GIN (searchVector);
+CREATE INDEX IF NOT EXISTS idx_songs_themes ON Songs USING GIN (themes);
+
+-- A fellowship's library holds each CCLI song once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_ccli ON Songs(fellowshipId, ccliNumber) WHERE ccliNumber <> '' AND deleted IS NULL;
@@ -0,0 +1,3 @@
+-- When a reminder last failed to send, so that failing reminders are retried later rather than
+-- holding up the rest of the queue.
+ALTER TABLE Posts ADD COLUMN IF NOT EXISTS followUpFailed TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+-- Comment counts only include comments everyone can see, so recount the posts that have hidden comments.
+UPDATE Posts SET commentCount = (SELECT count(*) FROM Comments c WHERE c.postId = Posts.id AND c.deleted IS NULL AND c.hidden IS NULL)
+WHERE id IN (SELECT postId FROM Comments WHERE hidden IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_comments_parentid ON Comments(parentId) WHERE parentId IS NOT NULL;
@@ -0,0 +1,8 @@
+-- The bytes an attachment takes in blob storage, counting the web version and thumbnail of images
+-- as well as the original. This is what counts against the fellowship's quota. Variants already
+-- made before this column existed are not counted.
+ALTER TABLE Attachments ADD COLUMN IF NOT EXISTS stored BIGINT;
+UPDATE Attachments SET stored = size WHERE stored IS NULL;
+ALTER TABLE Attachments ALTER COLUMN stored SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_unattached ON Attachments(uploaded) WHERE postId IS NULL;
@@ -0,0 +1,6 @@
+-- When a member joined the fellowship. Members from before this column existed are dated from
+-- when their account was made.
+ALTER TABLE FellowshipMembers ADD COLUMN IF NOT EXISTS joined TIMESTAMPTZ;
+UPDATE FellowshipMembers m SET joined = u.created FROM Users u WHERE u.id = m.userId AND m.joined IS NULL;
+ALTER TABLE FellowshipMembers ALTER COLUMN joined SET DEFAULT NOW();
+ALTER TABLE FellowshipMembers ALTER COLUMN joined SET NOT NULL;
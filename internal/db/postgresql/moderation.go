@@ -0,0 +1,281 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func NewModerationStore(db *sql.DB) *ModerationStore {
+	return &ModerationStore{db: db}
+}
+
+type ModerationStore struct {
+	db *sql.DB
+}
+
+// reportColumns are the columns read by scanReport.
+const reportColumns = "id, fellowshipId, reporterId, postId, commentId, authorId, reason, created, status, resolvedBy, resolved"
+
+func scanReport(row scanner) (domain.Report, error) {
+	report := domain.Report{}
+	err := row.Scan(&report.Id, &report.FellowshipId, &report.ReporterId, &report.PostId, &report.CommentId, &report.AuthorId, &report.Reason, &report.Created,
+		&report.Status, &report.ResolvedBy, &report.Resolved)
+	return report, err
+}
+
+func (m *ModerationStore) GetReport(ctx context.Context, reportId uuid.UUID) (*domain.Report, error) {
+	report, err := scanReport(m.db.QueryRowContext(ctx, "SELECT "+reportColumns+" FROM Reports WHERE id=$1", reportId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (m *ModerationStore) GetOpenReports(ctx context.Context, fellowshipId uuid.UUID, limit *int, cursor *domain.ModerationCursor) ([]domain.Report, *domain.ModerationCursor, error) {
+	args := []any{fellowshipId}
+	query := "SELECT " + reportColumns + " FROM Reports WHERE fellowshipId=$1 AND status='open'"
+
+	if cursor != nil {
+		query += fmt.Sprintf(" AND (created, id) > ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Created, cursor.Id)
+	}
+
+	actualLimit := moderationPageLimit(limit)
+
+	// Fetch one extra row to find out whether another page follows.
+	query += fmt.Sprintf(" ORDER BY created, id LIMIT %d", actualLimit+1)
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]domain.Report, 0, actualLimit)
+	var next *domain.ModerationCursor
+
+	for rows.Next() {
+		if len(reports) == actualLimit {
+			last := reports[len(reports)-1]
+			next = &domain.ModerationCursor{Created: last.Created, Id: last.Id}
+			break
+		}
+
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return reports, next, nil
+}
+
+func (m *ModerationStore) GetModerationLog(ctx context.Context, fellowshipId uuid.UUID, limit *int, cursor *domain.ModerationCursor) ([]domain.ModerationLogEntry, *domain.ModerationCursor, error) {
+	args := []any{fellowshipId}
+	query := "SELECT id, moderatorId, action, reportId, targetUserId, postId, commentId, note, created FROM ModerationLog WHERE fellowshipId=$1"
+
+	if cursor != nil {
+		query += fmt.Sprintf(" AND (created, id) < ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Created, cursor.Id)
+	}
+
+	actualLimit := moderationPageLimit(limit)
+
+	// Fetch one extra row to find out whether another page follows.
+	query += fmt.Sprintf(" ORDER BY created DESC, id DESC LIMIT %d", actualLimit+1)
+
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.ModerationLogEntry, 0, actualLimit)
+	var next *domain.ModerationCursor
+
+	for rows.Next() {
+		if len(entries) == actualLimit {
+			last := entries[len(entries)-1]
+			next = &domain.ModerationCursor{Created: last.Created, Id: last.Id}
+			break
+		}
+
+		entry := domain.ModerationLogEntry{FellowshipId: fellowshipId}
+		err := rows.Scan(&entry.Id, &entry.ModeratorId, &entry.Action, &entry.ReportId, &entry.TargetUserId, &entry.PostId, &entry.CommentId, &entry.Note, &entry.Created)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return entries, next, nil
+}
+
+func (m *ModerationStore) CreateReport(ctx context.Context, report domain.Report) error {
+	// The only conflict is with the reporter's open report on the same content. Posts held for review
+	// have no reporter and so never conflict.
+	result, err := m.db.ExecContext(ctx, "INSERT INTO Reports (id, fellowshipId, reporterId, postId, commentId, authorId, reason, created, status) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING",
+		report.Id, report.FellowshipId, report.ReporterId, report.PostId, report.CommentId, report.AuthorId, report.Reason, report.Created, domain.ReportOpen)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAlreadyReported
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ModerationStore) ResolveReport(ctx context.Context, entry domain.ModerationLogEntry) error {
+	if entry.ReportId == nil {
+		return fmt.Errorf("moderation log entry %s is not for a report", entry.Id)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status := reportStatus(entry.Action)
+
+	result, err := tx.ExecContext(ctx, "UPDATE Reports SET status=$2, resolvedBy=$3, resolved=$4 WHERE id=$1 AND status='open'", *entry.ReportId, status, entry.ModeratorId, entry.Created)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO ModerationLog (id, fellowshipId, moderatorId, action, reportId, targetUserId, postId, commentId, note, created) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		entry.Id, entry.FellowshipId, entry.ModeratorId, entry.Action, entry.ReportId, entry.TargetUserId, entry.PostId, entry.CommentId, entry.Note, entry.Created)
+	if err != nil {
+		return err
+	}
+
+	// Resolve every open report on the same content, not just the one acted on.
+	if entry.PostId != nil {
+		_, err = tx.ExecContext(ctx, "UPDATE Reports SET status=$3, resolvedBy=$4, resolved=$5 WHERE status='open' AND postId=$1 AND commentId IS NOT DISTINCT FROM $2",
+			*entry.PostId, entry.CommentId, status, entry.ModeratorId, entry.Created)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *ModerationStore) ReopenReport(ctx context.Context, entry domain.ModerationLogEntry) error {
+	if entry.ReportId == nil {
+		return fmt.Errorf("moderation log entry %s is not for a report", entry.Id)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ModerationLog WHERE id=$1", entry.Id); err != nil {
+		return err
+	}
+
+	// The reports resolved with the action are the ones it resolved at the moment it was taken.
+	result, err := tx.ExecContext(ctx, `UPDATE Reports SET status='open', resolvedBy=NULL, resolved=NULL
+		WHERE resolvedBy=$2 AND resolved=$3 AND (id=$1 OR (postId=$4 AND commentId IS NOT DISTINCT FROM $5))`,
+		*entry.ReportId, entry.ModeratorId, entry.Created, entry.PostId, entry.CommentId)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *ModerationStore) GetBlockedWords(ctx context.Context, fellowshipId uuid.UUID) ([]domain.BlockedWord, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT word, outcome FROM BlockedWords WHERE fellowshipId=$1 ORDER BY word", fellowshipId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := make([]domain.BlockedWord, 0)
+
+	for rows.Next() {
+		var word domain.BlockedWord
+		if err := rows.Scan(&word.Word, &word.Outcome); err != nil {
+			return nil, err
+		}
+
+		words = append(words, word)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+func (m *ModerationStore) SetBlockedWords(ctx context.Context, fellowshipId uuid.UUID, words []domain.BlockedWord) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM BlockedWords WHERE fellowshipId=$1", fellowshipId); err != nil {
+		return err
+	}
+
+	for _, word := range words {
+		_, err := tx.ExecContext(ctx, "INSERT INTO BlockedWords (fellowshipId, word, outcome) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", fellowshipId, word.Word, word.Outcome)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// moderationPageLimit returns the page size for a requested limit.
+func moderationPageLimit(limit *int) int {
+	if limit == nil {
+		return 50 // default limit
+	}
+
+	return max(min(*limit, 200), 1) // enforce a maximum limit and a minimum of 1
+}
+
+// reportStatus is the status of a report resolved by a moderation action.
+func reportStatus(action domain.ModerationAction) domain.ReportStatus {
+	if action == domain.ModerationDismiss {
+		return domain.ReportDismissed
+	}
+
+	return domain.ReportActioned
+}
@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestResolveReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     domain.ModerationAction
+		affected   int64
+		wantStatus domain.ReportStatus
+		wantErr    error
+		want       []string
+	}{
+		{
+			name:       "open report actioned",
+			action:     domain.ModerationHide,
+			affected:   1,
+			wantStatus: domain.ReportActioned,
+			want:       []string{"UPDATE Reports", "INSERT INTO ModerationLog", "UPDATE Reports", "COMMIT"},
+		},
+		{
+			name:       "open report dismissed",
+			action:     domain.ModerationDismiss,
+			affected:   1,
+			wantStatus: domain.ReportDismissed,
+			want:       []string{"UPDATE Reports", "INSERT INTO ModerationLog", "UPDATE Reports", "COMMIT"},
+		},
+		{
+			name:       "resolved report",
+			action:     domain.ModerationHide,
+			affected:   0,
+			wantStatus: domain.ReportActioned,
+			wantErr:    sql.ErrNoRows,
+			want:       []string{"UPDATE Reports", "ROLLBACK"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				statements []string
+				status     driver.Value
+			)
+
+			db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+				statements = append(statements, query)
+
+				// The report is claimed first, and only while it is open.
+				if len(statements) == 1 {
+					if !strings.Contains(query, "status='open'") {
+						t.Errorf("query %q resolves the report whether or not it is open", query)
+					}
+
+					status = args[1].Value
+					return fakeRows{affected: test.affected}, nil
+				}
+
+				return fakeRows{affected: 1}, nil
+			})
+
+			reportId, postId := uuid.New(), uuid.New()
+			entry := domain.ModerationLogEntry{Id: uuid.New(), FellowshipId: uuid.New(), ModeratorId: uuid.New(), Action: test.action, ReportId: &reportId, PostId: &postId, Created: time.Now()}
+
+			err := NewModerationStore(db).ResolveReport(context.Background(), entry)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("ResolveReport = %v, want %v", err, test.wantErr)
+			}
+
+			if status != string(test.wantStatus) {
+				t.Errorf("status = %v, want %s", status, test.wantStatus)
+			}
+
+			if len(statements) != len(test.want) {
+				t.Fatalf("statements = %q, want %q", statements, test.want)
+			}
+
+			for i, prefix := range test.want {
+				if !strings.HasPrefix(statements[i], prefix) {
+					t.Errorf("statement %d = %q, want %s", i, statements[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestReopenReportRemovesLogEntry(t *testing.T) {
+	var statements []string
+	db := openFakeDB(t, func(query string, args []driver.NamedValue) (fakeRows, error) {
+		statements = append(statements, query)
+		return fakeRows{affected: 1}, nil
+	})
+
+	reportId, postId := uuid.New(), uuid.New()
+	entry := domain.ModerationLogEntry{Id: uuid.New(), ModeratorId: uuid.New(), Action: domain.ModerationHide, ReportId: &reportId, PostId: &postId, Created: time.Now()}
+
+	if err := NewModerationStore(db).ReopenReport(context.Background(), entry); err != nil {
+		t.Fatalf("ReopenReport: %v", err)
+	}
+
+	want := []string{"DELETE FROM ModerationLog", "UPDATE Reports", "COMMIT"}
+	if len(statements) != len(want) {
+		t.Fatalf("statements = %q, want %q", statements, want)
+	}
+
+	for i, prefix := range want {
+		if !strings.HasPrefix(statements[i], prefix) {
+			t.Errorf("statement %d = %q, want %s", i, statements[i], prefix)
+		}
+	}
+}
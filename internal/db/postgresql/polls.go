@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewPollStore(db *sql.DB) *PollStore {
+	return &PollStore{db: db}
+}
+
+type PollStore struct {
+	db *sql.DB
+}
+
+func (p *PollStore) GetPollVotes(ctx context.Context, postId uuid.UUID) ([]domain.PollVote, error) {
+	rows, err := p.db.QueryContext(ctx, "SELECT userId, options, voted FROM PollVotes WHERE postId=$1 ORDER BY voted, userId", postId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := make([]domain.PollVote, 0)
+
+	for rows.Next() {
+		vote := domain.PollVote{PostId: postId}
+		var options pq.Int64Array
+		if err := rows.Scan(&vote.UserId, &options, &vote.Voted); err != nil {
+			return nil, err
+		}
+
+		vote.Options = make([]int, len(options))
+		for i, option := range options {
+			vote.Options[i] = int(option)
+		}
+
+		votes = append(votes, vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+func (p *PollStore) AddPollVote(ctx context.Context, vote domain.PollVote) error {
+	options := make(pq.Int64Array, len(vote.Options))
+	for i, option := range vote.Options {
+		options[i] = int64(option)
+	}
+
+	// The only conflict is with the member's earlier vote.
+	result, err := p.db.ExecContext(ctx, "INSERT INTO PollVotes (postId, userId, options, voted) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING",
+		vote.PostId, vote.UserId, options, vote.Voted)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAlreadyVoted
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
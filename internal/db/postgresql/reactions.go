@@ -0,0 +1,99 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewReactionStore(db *sql.DB) *ReactionStore {
+	return &ReactionStore{db: db}
+}
+
+type ReactionStore struct {
+	db *sql.DB
+}
+
+func (r *ReactionStore) GetReactionCounts(ctx context.Context, postIDs []uuid.UUID, userId uuid.UUID) (map[uuid.UUID][]domain.ReactionCount, error) {
+	counts := make(map[uuid.UUID][]domain.ReactionCount, len(postIDs))
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.postId, c.kind, c.count, EXISTS (
+			SELECT 1 FROM PostReactions WHERE postId = c.postId AND kind = c.kind AND userId = $2
+		)
+		FROM PostReactionCounts c
+		WHERE c.postId = ANY($1) AND c.count > 0
+		ORDER BY c.postId, c.kind`, pq.Array(postIDs), userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postId uuid.UUID
+		count := domain.ReactionCount{}
+		if err := rows.Scan(&postId, &count.Kind, &count.Count, &count.ReactedByMe); err != nil {
+			return nil, err
+		}
+
+		counts[postId] = append(counts[postId], count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *ReactionStore) ToggleReaction(ctx context.Context, reaction domain.Reaction) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM PostReactions WHERE postId=$1 AND kind=$2 AND userId=$3", reaction.PostId, reaction.Kind, reaction.UserId)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	delta := -1
+	if removed == 0 {
+		result, err = tx.ExecContext(ctx, "INSERT INTO PostReactions (postId, kind, userId, reacted) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING",
+			reaction.PostId, reaction.Kind, reaction.UserId, reaction.Reacted)
+		if err != nil {
+			return false, err
+		}
+
+		// A concurrent toggle may have added the reaction first, in which case there is nothing to count.
+		if added, err := result.RowsAffected(); err != nil {
+			return false, err
+		} else if added == 0 {
+			return true, tx.Commit()
+		}
+
+		delta = 1
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO PostReactionCounts (postId, kind, count) VALUES ($1, $2, GREATEST($3, 0))
+		ON CONFLICT (postId, kind) DO UPDATE SET count = GREATEST(PostReactionCounts.count + $3, 0)`,
+		reaction.PostId, reaction.Kind, delta)
+	if err != nil {
+		return false, err
+	}
+
+	return delta > 0, tx.Commit()
+}
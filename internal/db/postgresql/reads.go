@@ -0,0 +1,94 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewReadStore(db *sql.DB) *ReadStore {
+	return &ReadStore{db: db}
+}
+
+type ReadStore struct {
+	db *sql.DB
+}
+
+func (r *ReadStore) GetUnreadCounts(ctx context.Context, userId uuid.UUID, fellowshipIDs []uuid.UUID, circleIDs []uuid.UUID, limit int) ([]domain.UnreadCount, error) {
+	counts := make([]domain.UnreadCount, 0, len(fellowshipIDs)+len(circleIDs))
+
+	fellowshipCounts, err := r.countUnread(ctx, "fellowshipId", userId, fellowshipIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range fellowshipIDs {
+		counts = append(counts, domain.UnreadCount{FellowshipId: id, Count: fellowshipCounts[id]})
+	}
+
+	circleCounts, err := r.countUnread(ctx, "circleId", userId, circleIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range circleIDs {
+		counts = append(counts, domain.UnreadCount{CircleId: id, Count: circleCounts[id]})
+	}
+
+	return counts, nil
+}
+
+// countUnread counts the unread posts whose column is each of containerIDs. Each count is a range
+// scan of the (column, posted, id) feed index after the user's marker, stopping after limit rows.
+func (r *ReadStore) countUnread(ctx context.Context, column string, userId uuid.UUID, containerIDs []uuid.UUID, limit int) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(containerIDs))
+	if len(containerIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT c.id, (
+			SELECT COUNT(*) FROM (
+				SELECT 1 FROM Posts p
+				WHERE p.%s = c.id AND p.deleted IS NULL AND p.hidden IS NULL
+					AND (p.posted, p.id) > (COALESCE(m.posted, '-infinity'), COALESCE(m.postId, '00000000-0000-0000-0000-000000000000'))
+					AND p.state = 'published' AND p.authorId <> $1
+				LIMIT $3
+			) AS unread
+		)
+		FROM unnest($2::uuid[]) AS c(id)
+		LEFT JOIN ReadMarkers m ON m.userId = $1 AND m.containerId = c.id`, column), userId, pq.Array(containerIDs), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+
+		counts[id] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *ReadStore) MarkRead(ctx context.Context, marker domain.ReadMarker) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO ReadMarkers (userId, containerId, posted, postId) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (userId, containerId) DO UPDATE SET posted = EXCLUDED.posted, postId = EXCLUDED.postId
+		WHERE (ReadMarkers.posted, ReadMarkers.postId) < (EXCLUDED.posted, EXCLUDED.postId)`,
+		marker.UserId, marker.ContainerId, marker.Posted, marker.PostId)
+	return err
+}
@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewSongStore(db *sql.DB) *SongStore {
+	return &SongStore{db: db}
+}
+
+type SongStore struct {
+	db *sql.DB
+}
+
+// songColumns are the columns read by scanSong, less the lyrics, which only GetSong reads.
+const songColumns = "id, fellowshipId, title, authors, ccliNumber, copyright, defaultKey, tempo, timeSignature, themes, creatorId, created, edited"
+
+func scanSong(row scanner, extra ...any) (domain.Song, error) {
+	song := domain.Song{Authors: make([]string, 0), Themes: make([]string, 0)}
+	dest := []any{&song.Id, &song.FellowshipId, &song.Title, pq.Array(&song.Authors), &song.CCLINumber, &song.Copyright, &song.DefaultKey,
+		&song.Tempo, &song.TimeSignature, pq.Array(&song.Themes), &song.CreatorId, &song.Created, &song.Edited}
+	err := row.Scan(append(dest, extra...)...)
+	return song, err
+}
+
+func (s *SongStore) GetSong(ctx context.Context, songId uuid.UUID) (*domain.Song, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+songColumns+", lyrics FROM Songs WHERE id=$1 AND deleted IS NULL", songId)
+
+	var lyrics string
+	song, err := scanSong(row, &lyrics)
+	if err != nil {
+		return nil, err
+	}
+
+	song.Lyrics = lyrics
+	return &song, nil
+}
+
+func (s *SongStore) SearchSongs(ctx context.Context, fellowshipId uuid.UUID, filter domain.SongFilter, limit *int, cursor *domain.SongSearchCursor) ([]domain.Song, *domain.SongSearchCursor, error) {
+	args := []any{fellowshipId}
+	conditions := []string{"fellowshipId=$1", "deleted IS NULL"}
+	rank := "0::real"
+
+	if query := strings.TrimSpace(filter.Query); query != "" {
+		args = append(args, query)
+		tsQuery := fmt.Sprintf("websearch_to_tsquery('english', $%d)", len(args))
+		rank = "ts_rank(searchVector, " + tsQuery + ")::real"
+		conditions = append(conditions, "searchVector @@ "+tsQuery)
+	}
+
+	if filter.Theme != "" {
+		args = append(args, filter.Theme)
+		conditions = append(conditions, fmt.Sprintf("themes @> ARRAY[$%d::text]", len(args)))
+	}
+
+	inner := fmt.Sprintf("SELECT %s, %s AS rank FROM Songs WHERE %s", songColumns, rank, strings.Join(conditions, " AND "))
+	sqlQuery := "SELECT " + songColumns + ", rank FROM (" + inner + ") AS matches"
+
+	// Best matches come first, and songs that match equally well are listed by title.
+	if cursor != nil {
+		sqlQuery += fmt.Sprintf(" WHERE rank < $%[1]d::real OR (rank = $%[1]d::real AND (title, id) > ($%[2]d, $%[3]d))", len(args)+1, len(args)+2, len(args)+3)
+		args = append(args, cursor.Rank, cursor.Title, cursor.Id)
+	}
+
+	actualLimit := 10 // default limit
+	if limit != nil {
+		actualLimit = max(min(*limit, 100), 1) // enforce a maximum limit and a minimum of 1
+	}
+
+	// Fetch one extra row to find out whether another page follows.
+	sqlQuery += fmt.Sprintf(" ORDER BY rank DESC, title, id LIMIT %d", actualLimit+1)
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]domain.Song, 0, actualLimit)
+	var (
+		lastRank float32
+		next     *domain.SongSearchCursor
+	)
+
+	for rows.Next() {
+		if len(songs) == actualLimit {
+			last := songs[len(songs)-1]
+			next = &domain.SongSearchCursor{Rank: lastRank, Title: last.Title, Id: last.Id}
+			break
+		}
+
+		song, err := scanSong(rows, &lastRank)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return songs, next, nil
+}
+
+func (s *SongStore) CreateSong(ctx context.Context, song domain.Song) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO Songs (id, fellowshipId, title, authors, ccliNumber, copyright, defaultKey, tempo, timeSignature, themes, lyrics, creatorId, created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		song.Id, song.FellowshipId, song.Title, pq.Array(song.Authors), song.CCLINumber, song.Copyright, song.DefaultKey,
+		song.Tempo, song.TimeSignature, pq.Array(song.Themes), song.Lyrics, song.CreatorId, song.Created)
+	if isUniqueViolation(err) {
+		return domain.ErrDuplicateSong
+	}
+
+	return err
+}
+
+func (s *SongStore) UpdateSong(ctx context.Context, song domain.Song) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE Songs SET title=$2, authors=$3, ccliNumber=$4, copyright=$5, defaultKey=$6, tempo=$7, timeSignature=$8, themes=$9, lyrics=$10, edited=$11
+		WHERE id=$1 AND deleted IS NULL`,
+		song.Id, song.Title, pq.Array(song.Authors), song.CCLINumber, song.Copyright, song.DefaultKey,
+		song.Tempo, song.TimeSignature, pq.Array(song.Themes), song.Lyrics, song.Edited)
+	if isUniqueViolation(err) {
+		return domain.ErrDuplicateSong
+	} else if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SongStore) DeleteSong(ctx context.Context, songId uuid.UUID, deleted time.Time) error {
+	result, err := s.db.ExecContext(ctx, "UPDATE Songs SET deleted=$2 WHERE id=$1 AND deleted IS NULL", songId, deleted)
+	if err != nil {
+		return err
+	}
+
+	if err := expectRowsAffected(result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether err is Postgres refusing a row that breaks a unique index.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation"
+}
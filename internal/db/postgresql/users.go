@@ -6,6 +6,7 @@ import (
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func NewUserStore(db *sql.DB) *UserStore {
@@ -27,6 +28,57 @@ func (u *UserStore) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, er
 	return user, nil
 }
 
+func (u *UserStore) GetUsers(ctx context.Context, ids []uuid.UUID) ([]domain.User, error) {
+	rows, err := u.db.QueryContext(ctx, "SELECT id, displayName, created FROM Users WHERE id = ANY($1) AND NOT isDeleted", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0, len(ids))
+
+	for rows.Next() {
+		user := domain.User{}
+		if err := rows.Scan(&user.Id, &user.DisplayName, &user.Created); err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (u *UserStore) GetUserContacts(ctx context.Context, ids []uuid.UUID) ([]domain.UserContact, error) {
+	rows, err := u.db.QueryContext(ctx, "SELECT u.id, u.displayName, c.accountId FROM Users u JOIN UserConnections c ON c.userId = u.id AND c.signInType = $2 WHERE u.id = ANY($1)",
+		pq.Array(ids), domain.SignInTypeLocal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := make([]domain.UserContact, 0, len(ids))
+
+	for rows.Next() {
+		contact := domain.UserContact{}
+		if err := rows.Scan(&contact.UserId, &contact.DisplayName, &contact.Email); err != nil {
+			return nil, err
+		}
+
+		contacts = append(contacts, contact)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
 func (u *UserStore) GetUserConnection(ctx context.Context, signInType domain.SignInType, accountId string) (*domain.UserConnection, error) {
 	conn := &domain.UserConnection{SignInType: signInType, AccountId: accountId}
 
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentStatus tracks the processing of uploaded images. Other files are ready straight away.
+type AttachmentStatus string
+
+const (
+	AttachmentProcessing AttachmentStatus = "processing"
+	AttachmentReady      AttachmentStatus = "ready"
+	AttachmentFailed     AttachmentStatus = "failed"
+)
+
+// AttachmentVariant selects a version of an image attachment to download.
+type AttachmentVariant string
+
+const (
+	AttachmentOriginal  AttachmentVariant = ""
+	AttachmentWeb       AttachmentVariant = "web"
+	AttachmentThumbnail AttachmentVariant = "thumbnail"
+)
+
+// Attachment is a file uploaded to a fellowship or one of its circles, such as a bulletin or a song
+// sheet. It counts against its fellowship's storage quota and is attached to at most one post.
+type Attachment struct {
+	Id           uuid.UUID        `json:"id"`
+	FellowshipId uuid.UUID        `json:"fellowshipId"`
+	CircleId     uuid.UUID        `json:"circleId"`
+	UploaderId   uuid.UUID        `json:"uploaderId"`
+	PostId       *uuid.UUID       `json:"postId,omitempty"`
+	Name         string           `json:"name"`
+	ContentType  string           `json:"contentType"`
+	Size         int64            `json:"size"`
+	Uploaded     time.Time        `json:"uploaded"`
+	Status       AttachmentStatus `json:"status"`
+	// Width and Height are set on processed images.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// AttachmentURL is a signed download link for an attachment.
+type AttachmentURL struct {
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"`
+}
+
+// BlobStore keeps the content of attachments under opaque keys.
+type BlobStore interface {
+	// Put stores size bytes read from content under key, replacing any blob already there.
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key. It returns ErrBlobNotFound if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+type AttachmentStoreReader interface {
+	GetAttachment(ctx context.Context, attachmentId uuid.UUID) (*Attachment, error)
+	GetAttachments(ctx context.Context, attachmentIDs []uuid.UUID) ([]Attachment, error)
+	// GetPostAttachments returns the attachments of each post in upload order.
+	GetPostAttachments(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]Attachment, error)
+	// GetProcessingAttachments returns up to limit attachments uploaded before uploadedBefore that are
+	// still waiting to be processed, oldest first.
+	GetProcessingAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]Attachment, error)
+}
+
+type AttachmentStoreWriter interface {
+	// CreateAttachment records a new attachment and adds its size to its fellowship's storage used.
+	// It returns ErrStorageQuotaExceeded if that would take the fellowship over quota bytes.
+	CreateAttachment(ctx context.Context, attachment Attachment, quota int64) error
+	// DeleteAttachment removes an attachment and releases the storage it used.
+	DeleteAttachment(ctx context.Context, attachmentId uuid.UUID) error
+	// DeleteUnattachedAttachments removes up to limit attachments uploaded before uploadedBefore that
+	// are on no post, releases the storage they used and returns their IDs.
+	DeleteUnattachedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]uuid.UUID, error)
+	// FinishAttachment records the outcome of processing an attachment: size is the size of its
+	// original, and stored the bytes of all its blobs. The fellowship's storage used follows stored,
+	// which is zero for failed attachments.
+	FinishAttachment(ctx context.Context, attachmentId uuid.UUID, status AttachmentStatus, size, stored int64, width, height int) error
+}
+
+type AttachmentStore interface {
+	AttachmentStoreReader
+	AttachmentStoreWriter
+}
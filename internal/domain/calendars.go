@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed is the link calendar apps subscribe to for a user's events. Calendar apps cannot sign
+// in, so the link carries a token that stands in for the user. It is only shown when made.
+type CalendarFeed struct {
+	URL string `json:"url"`
+}
+
+type CalendarStoreReader interface {
+	// GetCalendarFeedUserId returns the user a feed token belongs to, by the token's hash.
+	GetCalendarFeedUserId(ctx context.Context, tokenHash []byte) (uuid.UUID, error)
+}
+
+type CalendarStoreWriter interface {
+	// SetCalendarFeedToken gives the user a new feed token, revoking any earlier one.
+	SetCalendarFeedToken(ctx context.Context, userId uuid.UUID, tokenHash []byte, created time.Time) error
+	// DeleteCalendarFeedToken revokes the user's feed token.
+	DeleteCalendarFeedToken(ctx context.Context, userId uuid.UUID) error
+}
+
+type CalendarStore interface {
+	CalendarStoreReader
+	CalendarStoreWriter
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,6 +12,21 @@ type CircleType int32
 const (
 	Notices CircleType = iota
 	Prayer
+	Events
+	Worship
+	Discussion
+)
+
+// CircleAccessMode controls how members of a fellowship gain access to one of its circles.
+type CircleAccessMode int32
+
+const (
+	// CircleInviteOnly circles are only accessible to members added by a moderator.
+	CircleInviteOnly CircleAccessMode = iota
+	// CircleRequestToJoin circles accept join requests that a moderator approves.
+	CircleRequestToJoin
+	// CircleOpen circles are accessible to every fellowship member at their fellowship access level.
+	CircleOpen
 )
 
 type CircleMember struct {
@@ -19,24 +35,52 @@ type CircleMember struct {
 	Access   AccessLevel
 }
 
+type CircleJoinRequest struct {
+	CircleId  uuid.UUID `json:"circleId"`
+	UserId    uuid.UUID `json:"userId"`
+	Requested time.Time `json:"requested"`
+}
+
 type Circle struct {
-	Id           uuid.UUID  `json:"id"`
-	Creator      uuid.UUID  `json:"creatorId"`
-	FellowshipId uuid.UUID  `json:"fellowshipId"`
-	Name         string     `json:"name"`
-	Type         CircleType `json:"type"`
+	Id           uuid.UUID        `json:"id"`
+	Creator      uuid.UUID        `json:"creatorId"`
+	FellowshipId uuid.UUID        `json:"fellowshipId"`
+	Name         string           `json:"name"`
+	Type         CircleType       `json:"type"`
+	AccessMode   CircleAccessMode `json:"accessMode"`
 }
 
 type CircleStoreReader interface {
+	GetCircle(ctx context.Context, circleId uuid.UUID) (*Circle, error)
+	// GetUserCircles returns the circles the user is a member of, plus the open circles of their
+	// fellowships and, for Owners and Admins, of the fellowships beneath theirs.
 	GetUserCircles(ctx context.Context, userId uuid.UUID) ([]Circle, error)
+	// GetUserCircleIDs returns the IDs of the circles GetUserCircles returns.
 	GetUserCircleIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	// GetInheritedNoticeCircleIDs returns the open Notices circles of ancestor fellowships for every
+	// fellowship in which the user has opted in to parent notices.
+	GetInheritedNoticeCircleIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	// GetUserAccessLevel returns the user's access to a circle, derived from their fellowship access
+	// for open circles. It returns ErrNotMember when the user has no access.
 	GetUserAccessLevel(ctx context.Context, userId uuid.UUID, circleId uuid.UUID) (AccessLevel, error)
 	GetCircleMembers(ctx context.Context, circleId uuid.UUID) ([]CircleMember, error)
+	// GetCircleRecipientsNamed returns the users who can see a circle's posts, its members and for
+	// open circles its fellowship's members, whose display names, in lower case, are among names.
+	GetCircleRecipientsNamed(ctx context.Context, circleId uuid.UUID, names []string) ([]User, error)
+	// GetModeratedCircleIDs returns the circles in which the user's own access, or for open circles
+	// their fellowship access, is Moderator or above.
+	GetModeratedCircleIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	GetJoinRequests(ctx context.Context, circleId uuid.UUID) ([]CircleJoinRequest, error)
 }
 
 type CircleStoreWriter interface {
 	CreateCircle(ctx context.Context, circle Circle) error
 	AddCircleMember(ctx context.Context, member CircleMember) error
+	SetCircleAccessMode(ctx context.Context, circleId uuid.UUID, mode CircleAccessMode) error
+	CreateJoinRequest(ctx context.Context, request CircleJoinRequest) error
+	// ApproveJoinRequest adds the requesting user as a member and removes the request.
+	ApproveJoinRequest(ctx context.Context, circleId uuid.UUID, userId uuid.UUID, access AccessLevel) error
+	RemoveJoinRequest(ctx context.Context, circleId uuid.UUID, userId uuid.UUID) error
 }
 
 type CircleStore interface {
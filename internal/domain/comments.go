@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a reply to a post. Replies to a comment set ParentId, allowing one level of threading.
+type Comment struct {
+	Id       uuid.UUID  `json:"id"`
+	PostId   uuid.UUID  `json:"postId"`
+	ParentId *uuid.UUID `json:"parentId,omitempty"`
+	AuthorId uuid.UUID  `json:"authorId"`
+	Created  time.Time  `json:"created"`
+	Edited   *time.Time `json:"edited,omitempty"`
+	Body     string     `json:"body"`
+	// Hidden is when a moderator hid the comment. Hidden comments are only shown to moderators.
+	Hidden *time.Time `json:"hidden,omitempty"`
+	// Removed marks a deleted or hidden comment listed only so that its replies keep their place in
+	// the thread. Its author and body are left empty.
+	Removed bool `json:"removed,omitempty"`
+}
+
+// CommentCursor is the keyset position of the last comment returned in a page.
+type CommentCursor struct {
+	Created time.Time `json:"c"`
+	Id      uuid.UUID `json:"i"`
+}
+
+type CommentStoreReader interface {
+	// GetComment returns a comment that has not been deleted, even if it is hidden.
+	GetComment(ctx context.Context, commentId uuid.UUID) (*Comment, error)
+	// GetComments returns a post's comments oldest first, leaving out hidden ones unless includeHidden
+	// is set. Deleted and left out comments that still have replies to show are listed as Removed.
+	// The returned cursor is nil on the last page.
+	GetComments(ctx context.Context, postId uuid.UUID, includeHidden bool, limit *int, cursor *CommentCursor) ([]Comment, *CommentCursor, error)
+}
+
+type CommentStoreWriter interface {
+	// CreateComment stores a comment and increments the post's comment count.
+	CreateComment(ctx context.Context, comment Comment) error
+	UpdateComment(ctx context.Context, commentId uuid.UUID, body string, edited time.Time) error
+	// HideComment hides a comment from everyone but moderators and decrements the post's comment count.
+	// Hiding a hidden comment changes nothing.
+	HideComment(ctx context.Context, commentId uuid.UUID, hiddenBy uuid.UUID, hidden time.Time) error
+	// DeleteComment soft deletes a comment, decrementing the post's comment count unless it was hidden.
+	DeleteComment(ctx context.Context, commentId uuid.UUID, deletedBy uuid.UUID, deleted time.Time) error
+}
+
+type CommentStore interface {
+	CommentStoreReader
+	CommentStoreWriter
+}
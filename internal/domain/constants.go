@@ -6,11 +6,79 @@ import (
 )
 
 const (
-	EmailRegexPattern       = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	DisplayNameRegexPattern = `^[a-zA-Z0-9 ]{3,30}$`
-	DisplayNameMinLength    = 3
-	DisplayNameMaxLength    = 30
-	PasswordMinLength       = 8
+	EmailRegexPattern         = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
+	DisplayNameRegexPattern   = `^[a-zA-Z0-9 ]{3,30}$`
+	DisplayNameMinLength      = 3
+	DisplayNameMaxLength      = 30
+	PasswordMinLength         = 8
+	SearchQueryMaxLength      = 200
+	CommentMaxLength          = 4000
+	TestimonyMaxLength        = 4000
+	AttachmentNameMaxLength   = 255
+	TagMaxLength              = 50
+	ReportReasonMaxLength     = 1000
+	ModerationNoteMaxLength   = 1000
+	BlockedWordMaxLength      = 100
+	PollOptionMaxLength       = 200
+	EventTitleMaxLength       = 200
+	EventLocationMaxLength    = 300
+	EventDescriptionMaxLength = 4000
+	SongTitleMaxLength        = 200
+	SongAuthorMaxLength       = 100
+	SongCCLINumberMaxLength   = 10
+	SongCopyrightMaxLength    = 300
+	SongLyricsMaxLength       = 20000
+	SongKeyRegexPattern       = `^[A-G][#b]?m?$`
+	SongTimeSignaturePattern  = `^[1-9][0-9]?/(1|2|4|8|16|32)$`
+
+	// SongMaxAuthors and SongMaxThemes bound how many authors and themes a song lists.
+	SongMaxAuthors = 10
+	SongMaxThemes  = 20
+
+	// SongMinTempo and SongMaxTempo bound a song's tempo in beats per minute.
+	SongMinTempo = 20
+	SongMaxTempo = 300
+
+	// PollMinOptions and PollMaxOptions bound how many options a poll offers.
+	PollMinOptions = 2
+	PollMaxOptions = 20
+
+	// EventMaxDuration bounds how long one occurrence of an event can last.
+	EventMaxDuration = 14 * 24 * time.Hour
+
+	// EventRangeMax bounds the date range occurrences are listed for, and EventOccurrencesMax how many
+	// are listed.
+	EventRangeMax       = 366 * 24 * time.Hour
+	EventOccurrencesMax = 500
+
+	// CalendarFeedPast and CalendarFeedFuture bound which events calendar feeds carry: those with
+	// occurrences from CalendarFeedPast ago that start within CalendarFeedFuture.
+	CalendarFeedPast   = 365 * 24 * time.Hour
+	CalendarFeedFuture = 2 * 365 * 24 * time.Hour
+
+	// BlockedWordsMax bounds how many words a fellowship can block.
+	BlockedWordsMax = 500
+
+	// PostMaxMentions bounds how many members a post can mention, and so notify.
+	PostMaxMentions = 20
+
+	// UnreadCountMax is where unread counts stop, so counting never reads more posts than a badge shows.
+	UnreadCountMax = 100
+
+	// AttachmentMaxSize is the largest file that can be uploaded as an attachment.
+	AttachmentMaxSize = 25 << 20
+
+	// PostMaxAttachments bounds how many attachments a post can carry.
+	PostMaxAttachments = 10
+
+	// AttachmentURLExpiry is how long a signed attachment download link remains valid.
+	AttachmentURLExpiry = 15 * time.Minute
+
+	// FellowshipMaxDepth bounds how many levels of parent fellowships are followed.
+	FellowshipMaxDepth = 8
+
+	// FellowshipDescriptionMaxLength bounds the description shown in public fellowship search.
+	FellowshipDescriptionMaxLength = 1000
 
 	// TokenExpiryDuration is how long a user auth/verification token remains valid.
 	TokenExpiryDuration = 15 * time.Minute
@@ -24,3 +92,9 @@ var EmailRegex = regexp.MustCompile(EmailRegexPattern)
 
 // DisplayNameRegex is the compiled form of DisplayNameRegexPattern.
 var DisplayNameRegex = regexp.MustCompile(DisplayNameRegexPattern)
+
+// SongKeyRegex is the compiled form of SongKeyRegexPattern.
+var SongKeyRegex = regexp.MustCompile(SongKeyRegexPattern)
+
+// SongTimeSignatureRegex is the compiled form of SongTimeSignaturePattern.
+var SongTimeSignatureRegex = regexp.MustCompile(SongTimeSignaturePattern)
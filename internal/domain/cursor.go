@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor serialises a store-specific position into an opaque string that
+// clients pass back unchanged to fetch the next page.
+func EncodeCursor(position any) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor reverses EncodeCursor. Any malformed input is reported as ErrInvalidCursor.
+func DecodeCursor(cursor string, position any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, position); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
@@ -19,4 +19,86 @@ var (
 	// User errors
 	ErrUserNotFound    = errors.New("user not found")
 	ErrUserFetchFailed = errors.New("unable to fetch user")
+
+	// Access errors
+	ErrNotMember          = errors.New("not a member")
+	ErrInsufficientAccess = errors.New("insufficient access")
+
+	// Fellowship errors
+	ErrFellowshipNotFound           = errors.New("fellowship not found")
+	ErrInvalidTimezone              = errors.New("invalid timezone")
+	ErrInvalidFellowshipParent      = errors.New("invalid parent fellowship")
+	ErrInvalidFellowshipDescription = errors.New("invalid fellowship description")
+
+	// Circle errors
+	ErrCircleNotFound      = errors.New("circle not found")
+	ErrAlreadyMember       = errors.New("already a member")
+	ErrJoinRequestNotFound = errors.New("join request not found")
+	ErrInvalidCircleMember = errors.New("user is not a member of the circle's fellowship")
+	ErrInvalidAccessMode   = errors.New("invalid circle access mode")
+
+	// Post errors
+	ErrPostNotFound       = errors.New("post not found")
+	ErrInvalidPostTarget  = errors.New("post must target either a fellowship or a circle")
+	ErrInvalidPostKind    = errors.New("post kind not allowed in this circle")
+	ErrInvalidPostDetails = errors.New("invalid post details")
+	ErrInvalidPostFormat  = errors.New("invalid post format")
+	ErrInvalidReaction    = errors.New("reaction not allowed on this post")
+	ErrInvalidPin         = errors.New("post cannot be pinned")
+	ErrInvalidTag         = errors.New("invalid tag")
+
+	// Prayer errors
+	ErrNotPrayerRequest    = errors.New("post is not a prayer request")
+	ErrInvalidPrayerStatus = errors.New("invalid prayer status")
+
+	// Poll errors
+	ErrPollNotFound = errors.New("poll not found")
+	ErrInvalidPoll  = errors.New("invalid poll")
+	ErrInvalidVote  = errors.New("invalid vote")
+	ErrAlreadyVoted = errors.New("already voted")
+	ErrPollClosed   = errors.New("poll closed")
+
+	// Event errors
+	ErrEventNotFound      = errors.New("event not found")
+	ErrInvalidEvent       = errors.New("invalid event")
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrOccurrenceNotFound = errors.New("event occurrence not found")
+	ErrInvalidRSVP        = errors.New("invalid rsvp")
+	ErrInvalidEventRange  = errors.New("invalid event range")
+
+	// Calendar errors
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	// Song errors
+	ErrSongNotFound  = errors.New("song not found")
+	ErrInvalidSong   = errors.New("invalid song")
+	ErrDuplicateSong = errors.New("song already in library")
+
+	// Comment errors
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrInvalidComment       = errors.New("invalid comment")
+	ErrInvalidCommentParent = errors.New("invalid parent comment")
+
+	// Moderation errors
+	ErrReportNotFound          = errors.New("report not found")
+	ErrInvalidReport           = errors.New("invalid report")
+	ErrAlreadyReported         = errors.New("already reported")
+	ErrReportResolved          = errors.New("report already resolved")
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
+	ErrInvalidBlockedWord      = errors.New("invalid blocked word")
+	ErrPostRejected            = errors.New("post rejected")
+	ErrPostRateLimited         = errors.New("posting too often")
+
+	// Attachment errors
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrInvalidAttachment     = errors.New("invalid attachment")
+	ErrAttachmentTooLarge    = errors.New("attachment too large")
+	ErrUnsupportedAttachment = errors.New("unsupported attachment type")
+	ErrStorageQuotaExceeded  = errors.New("storage quota exceeded")
+	ErrAttachmentNotReady    = errors.New("attachment not ready")
+	ErrBlobNotFound          = errors.New("blob not found")
+
+	// Pagination and search errors
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrInvalidSearchQuery = errors.New("invalid search query")
 )
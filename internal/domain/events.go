@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a gathering in a fellowship or one of its circles. Only one of FellowshipId and CircleId is
+// set. Recurring events repeat by RRule, and each repeat is an occurrence, identified by the time the
+// rule has it start.
+type Event struct {
+	Id           uuid.UUID `json:"id"`
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	CircleId     uuid.UUID `json:"circleId"`
+	CreatorId    uuid.UUID `json:"creatorId"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Location     string    `json:"location"`
+	// Start and End are the times of the first occurrence.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Timezone is the IANA zone of the fellowship when the event was saved. Occurrences keep the
+	// first occurrence's wall clock time in it.
+	Timezone string `json:"timezone"`
+	// RRule is an RFC 5545 recurrence rule, and is empty for events that happen once.
+	RRule string `json:"rrule,omitempty"`
+	// SeriesEnd is when the last occurrence ends, and is nil for series that repeat forever or whose
+	// last occurrence is too far off to find.
+	SeriesEnd  *time.Time       `json:"seriesEnd,omitempty"`
+	Created    time.Time        `json:"created"`
+	Edited     *time.Time       `json:"edited,omitempty"`
+	Exceptions []EventException `json:"exceptions"`
+}
+
+// Duration is how long each occurrence lasts, unless an exception moves it.
+func (e Event) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Exception returns the exception for an occurrence, or nil if it happens as the rule has it.
+func (e Event) Exception(occurrence time.Time) *EventException {
+	for i := range e.Exceptions {
+		if e.Exceptions[i].Occurrence.Equal(occurrence) {
+			return &e.Exceptions[i]
+		}
+	}
+
+	return nil
+}
+
+// EventInput describes a new event, or the new details of an event being edited.
+type EventInput struct {
+	FellowshipId uuid.UUID
+	CircleId     uuid.UUID
+	Title        string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	RRule        string
+}
+
+// EventException changes one occurrence of a recurring event, either cancelling it or moving it to
+// Start and End.
+type EventException struct {
+	// Occurrence is when the rule has the occurrence start.
+	Occurrence time.Time  `json:"occurrence"`
+	Cancelled  bool       `json:"cancelled"`
+	Start      *time.Time `json:"start,omitempty"`
+	End        *time.Time `json:"end,omitempty"`
+}
+
+// RSVPResponse is whether a member will come to an occurrence of an event.
+type RSVPResponse string
+
+const (
+	RSVPYes   RSVPResponse = "yes"
+	RSVPNo    RSVPResponse = "no"
+	RSVPMaybe RSVPResponse = "maybe"
+)
+
+func (r RSVPResponse) IsValid() bool {
+	switch r {
+	case RSVPYes, RSVPNo, RSVPMaybe:
+		return true
+	}
+
+	return false
+}
+
+// EventRSVP is a member's response for one occurrence of an event.
+type EventRSVP struct {
+	EventId    uuid.UUID    `json:"eventId"`
+	Occurrence time.Time    `json:"occurrence"`
+	UserId     uuid.UUID    `json:"userId"`
+	Response   RSVPResponse `json:"response"`
+	Responded  time.Time    `json:"responded"`
+}
+
+// RSVPTally is how many members gave a response for one occurrence, and whether the user asking is one
+// of them.
+type RSVPTally struct {
+	EventId    uuid.UUID
+	Occurrence time.Time
+	Response   RSVPResponse
+	Count      int
+	Mine       bool
+}
+
+// RSVPCounts are the responses for one occurrence.
+type RSVPCounts struct {
+	Yes   int `json:"yes"`
+	No    int `json:"no"`
+	Maybe int `json:"maybe"`
+	// Mine is the requesting member's response, and is empty if they have not responded.
+	Mine RSVPResponse `json:"mine,omitempty"`
+}
+
+// EventOccurrence is one time an event happens.
+type EventOccurrence struct {
+	EventId uuid.UUID `json:"eventId"`
+	// Occurrence is when the rule has the occurrence start. RSVPs and exceptions refer to it.
+	Occurrence time.Time `json:"occurrence"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	// Rescheduled is set when an exception moved the occurrence.
+	Rescheduled bool       `json:"rescheduled"`
+	RSVPs       RSVPCounts `json:"rsvps"`
+}
+
+// EventOccurrences are the occurrences within a date range, in order of start, and the events they
+// belong to.
+type EventOccurrences struct {
+	Events      []Event           `json:"events"`
+	Occurrences []EventOccurrence `json:"occurrences"`
+}
+
+type EventStoreReader interface {
+	// GetEvent returns an event that has not been deleted, with its exceptions.
+	GetEvent(ctx context.Context, eventId uuid.UUID) (*Event, error)
+	// GetEvents returns the events in fellowshipIDs and circleIDs that start before to and whose
+	// series have not ended by from, or that have an occurrence moved into that range, with their
+	// exceptions.
+	GetEvents(ctx context.Context, fellowshipIDs []uuid.UUID, circleIDs []uuid.UUID, from time.Time, to time.Time) ([]Event, error)
+	// GetRSVPTallies counts the responses for the given occurrences of eventIDs.
+	GetRSVPTallies(ctx context.Context, userId uuid.UUID, eventIDs []uuid.UUID, occurrences []time.Time) ([]RSVPTally, error)
+	// GetRSVPs returns the responses for one occurrence, oldest first.
+	GetRSVPs(ctx context.Context, eventId uuid.UUID, occurrence time.Time) ([]EventRSVP, error)
+}
+
+type EventStoreWriter interface {
+	CreateEvent(ctx context.Context, event Event) error
+	// UpdateEvent saves an event's details and replaces its exceptions.
+	UpdateEvent(ctx context.Context, event Event) error
+	DeleteEvent(ctx context.Context, eventId uuid.UUID, deleted time.Time) error
+	// SetEventException adds or replaces the exception for an occurrence.
+	SetEventException(ctx context.Context, eventId uuid.UUID, exception EventException) error
+	// DeleteEventException restores an occurrence to the time the rule has it.
+	DeleteEventException(ctx context.Context, eventId uuid.UUID, occurrence time.Time) error
+	// SetRSVP adds or replaces a member's response for an occurrence.
+	SetRSVP(ctx context.Context, rsvp EventRSVP) error
+}
+
+type EventStore interface {
+	EventStoreReader
+	EventStoreWriter
+}
@@ -2,27 +2,273 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// PostKind identifies what a post is, which in turn decides the extra details it carries.
+// The kinds allowed in a circle are defined by its CircleType.
+type PostKind string
+
+const (
+	PostKindGeneral    PostKind = "general"
+	PostKindNotice     PostKind = "notice"
+	PostKindPrayer     PostKind = "prayer"
+	PostKindEvent      PostKind = "event"
+	PostKindWorship    PostKind = "worship"
+	PostKindDiscussion PostKind = "discussion"
+)
+
+// PostFormat is how a post's article is written. Plain articles are shown as text; Markdown
+// articles are also rendered to sanitized HTML.
+type PostFormat string
+
+const (
+	PostFormatPlain    PostFormat = "plain"
+	PostFormatMarkdown PostFormat = "markdown"
+)
+
+// PostState is whether a post has been published. Drafts and scheduled posts are only seen by their author.
+type PostState string
+
+const (
+	PostDraft     PostState = "draft"
+	PostScheduled PostState = "scheduled"
+	PostPublished PostState = "published"
+)
+
+// PostEntityType is what a span of a post's article refers to.
+type PostEntityType string
+
+const (
+	PostEntityMention PostEntityType = "mention"
+	PostEntityTag     PostEntityType = "tag"
+)
+
+// PostEntity is a mention or tag found in a post's article. Offset and Length are counted in UTF-16
+// code units, as JavaScript and Dart index strings, and include the leading @ or #.
+type PostEntity struct {
+	Type   PostEntityType `json:"type"`
+	Offset int            `json:"offset"`
+	Length int            `json:"length"`
+	// UserId is the member a mention refers to.
+	UserId *uuid.UUID `json:"userId,omitempty"`
+	// Tag is the lower case tag, without its #.
+	Tag string `json:"tag,omitempty"`
+}
+
 type Post struct {
-	Id           uuid.UUID `json:"id"`
-	AuthorId     uuid.UUID `json:"authorId"`
-	FellowshipId uuid.UUID `json:"fellowshipId"`
-	CircleId     uuid.UUID `json:"circleId"`
-	Posted       time.Time `json:"posted"`
-	Heading      string    `json:"heading"`
-	Article      string    `json:"article"`
+	Id           uuid.UUID  `json:"id"`
+	AuthorId     uuid.UUID  `json:"authorId"`
+	FellowshipId uuid.UUID  `json:"fellowshipId"`
+	CircleId     uuid.UUID  `json:"circleId"`
+	Posted       time.Time  `json:"posted"`
+	Edited       *time.Time `json:"edited,omitempty"`
+	Kind         PostKind   `json:"kind"`
+	Heading      string     `json:"heading"`
+	Article      string     `json:"article"`
+	Format       PostFormat `json:"format"`
+	// ArticleHTML is the rendered article of Markdown posts.
+	ArticleHTML string `json:"articleHtml,omitempty"`
+	// Entities are the mentions and tags in Article, in order.
+	Entities     []PostEntity    `json:"entities"`
+	Details      json.RawMessage `json:"details,omitempty"`
+	CommentCount int             `json:"commentCount"`
+	Reactions    []ReactionCount `json:"reactions"`
+	Attachments  []Attachment    `json:"attachments"`
+	// Prayer is set on prayer posts only.
+	Prayer *PrayerState `json:"prayer,omitempty"`
+	Poll   *Poll        `json:"poll,omitempty"`
+	// PinnedUntil keeps the post in the pinned section of feeds until the time passes.
+	PinnedUntil *time.Time `json:"pinnedUntil,omitempty"`
+	State       PostState  `json:"state"`
+	// ScheduledFor is when a scheduled post will be published. Posted becomes the actual publish time.
+	ScheduledFor *time.Time `json:"scheduledFor,omitempty"`
+	// Hidden is when a moderator hid the post. Hidden posts are only shown to moderators.
+	Hidden *time.Time `json:"hidden,omitempty"`
+}
+
+// PostInput is the content of a new post as submitted by its author.
+type PostInput struct {
+	FellowshipId uuid.UUID
+	CircleId     uuid.UUID
+	Kind         PostKind
+	Heading      string
+	Article      string
+	// Format defaults to plain.
+	Format  PostFormat
+	Details json.RawMessage
+	// FollowUp optionally schedules a reminder to the author of a prayer post.
+	FollowUp *time.Time
+	// Draft saves the post without publishing it.
+	Draft bool
+	// PublishAt schedules publication for a future time. Posts without it are published straight away.
+	PublishAt *time.Time
+	// AttachmentIDs are the author's unattached uploads to carry on the post.
+	AttachmentIDs []uuid.UUID
+	// Poll optionally asks members to vote on the heading.
+	Poll *Poll
+}
+
+// PostEdit is the replacement content of an edited post. A post's target, kind and format never change.
+type PostEdit struct {
+	Heading string
+	Article string
+	// ArticleHTML and Entities are found in Article by the feed service.
+	ArticleHTML string
+	Entities    []PostEntity
+	Details     json.RawMessage
+}
+
+// FeedPage is a page of a user's feed. Pinned holds the pinned posts and is only filled on the
+// first page; pinned posts also appear in Posts in their usual place.
+type FeedPage struct {
+	Posts      []Post
+	Pinned     []Post
+	NextCursor string
+	PrevCursor string
+}
+
+// FeedFilter narrows a user's feed. Zero fields do not filter. Fellowship and circle filters must
+// name ones the user can see.
+type FeedFilter struct {
+	FellowshipId uuid.UUID
+	CircleId     uuid.UUID
+	AuthorId     uuid.UUID
+	Kind         PostKind
+	PrayerStatus *PrayerStatus
+	// Tag is matched without its # and regardless of case.
+	Tag string
+}
+
+// PostFilter selects the published posts a store returns: those in any of FellowshipIDs or CircleIDs
+// that match the remaining fields. Zero fields other than the IDs do not filter.
+type PostFilter struct {
+	FellowshipIDs []uuid.UUID
+	CircleIDs     []uuid.UUID
+	AuthorId      uuid.UUID
+	Kind          PostKind
+	PrayerStatus  *PrayerStatus
+	// Tag is a lower case tag without its #.
+	Tag string
+	// Hidden posts are only returned from ModeratedFellowshipIDs, their circles and ModeratedCircleIDs.
+	ModeratedFellowshipIDs []uuid.UUID
+	ModeratedCircleIDs     []uuid.UUID
+}
+
+// FeedCursor is the keyset position of a post in the feed, which is ordered newest first.
+type FeedCursor struct {
+	Posted time.Time `json:"p"`
+	Id     uuid.UUID `json:"i"`
+	// Newer pages towards posts newer than the position rather than older ones.
+	Newer bool `json:"n,omitempty"`
+}
+
+// PostSearchResult is a post matching a search. Snippet is HTML-escaped text from the post with the
+// matching words wrapped in <mark> elements.
+type PostSearchResult struct {
+	Post    Post   `json:"post"`
+	Snippet string `json:"snippet"`
+}
+
+// PostSearchCursor is the keyset position of the last post returned by a search.
+type PostSearchCursor struct {
+	Rank float32   `json:"r"`
+	Id   uuid.UUID `json:"i"`
+}
+
+// PostRevision is an immutable copy of a post's content as it was before an edit.
+type PostRevision struct {
+	PostId   uuid.UUID       `json:"postId"`
+	Revision int             `json:"revision"`
+	EditorId uuid.UUID       `json:"editorId"`
+	Edited   time.Time       `json:"edited"`
+	Heading  string          `json:"heading"`
+	Article  string          `json:"article"`
+	Details  json.RawMessage `json:"details,omitempty"`
+}
+
+// MentionedUsers returns the distinct users mentioned by entities.
+func MentionedUsers(entities []PostEntity) []uuid.UUID {
+	userIds := make([]uuid.UUID, 0)
+	for _, entity := range entities {
+		if entity.Type == PostEntityMention && entity.UserId != nil && !slices.Contains(userIds, *entity.UserId) {
+			userIds = append(userIds, *entity.UserId)
+		}
+	}
+
+	return userIds
+}
+
+// EntityTags returns the distinct tags in entities.
+func EntityTags(entities []PostEntity) []string {
+	tags := make([]string, 0)
+	for _, entity := range entities {
+		if entity.Type == PostEntityTag && !slices.Contains(tags, entity.Tag) {
+			tags = append(tags, entity.Tag)
+		}
+	}
+
+	return tags
 }
 
 type FeedStoreReader interface {
-	GetPosts(ctx context.Context, fellowshipIDs []uuid.UUID, circleIDs []uuid.UUID, limit *int, before *time.Time, after *time.Time) ([]Post, error)
+	// GetPosts returns a page of the posts matching filter, newest first. Without a cursor the newest
+	// posts are returned. It also reports whether more posts follow the page in the cursor's direction.
+	GetPosts(ctx context.Context, filter PostFilter, limit *int, cursor *FeedCursor) ([]Post, bool, error)
+	// GetPost returns a post that has not been deleted, even if it is hidden.
+	GetPost(ctx context.Context, postId uuid.UUID) (*Post, error)
+	// GetUnpublishedPosts returns the author's drafts and scheduled posts, newest first.
+	GetUnpublishedPosts(ctx context.Context, authorId uuid.UUID) ([]Post, error)
+	// GetPinnedPosts returns the posts matching filter that are still pinned at now, newest first.
+	GetPinnedPosts(ctx context.Context, filter PostFilter, now time.Time) ([]Post, error)
+	GetPostRevisions(ctx context.Context, postId uuid.UUID) ([]PostRevision, error)
+	// SearchPosts returns the posts matching filter and query, best match first. The returned cursor is nil on the last page.
+	SearchPosts(ctx context.Context, filter PostFilter, query string, limit *int, cursor *PostSearchCursor) ([]PostSearchResult, *PostSearchCursor, error)
+	// GetDuePrayerFollowUps returns reminders for active prayers whose follow-up date has passed and
+	// whose authors have an email address. Reminders that last failed to send at or after
+	// retryFailedBefore are skipped, and the others that failed come after those never tried.
+	GetDuePrayerFollowUps(ctx context.Context, due time.Time, retryFailedBefore time.Time, limit int) ([]PrayerFollowUp, error)
+	// GetPostsSince returns up to limit of the author's published and scheduled posts made since the
+	// given time that have not been deleted, newest first. Drafts are left out.
+	GetPostsSince(ctx context.Context, authorId uuid.UUID, since time.Time, limit int) ([]Post, error)
 }
 
 type FeedStoreWriter interface {
+	// CreatePost stores a new post, indexes its tags and mentions and attaches post.Attachments to it. It returns ErrInvalidAttachment
+	// if any of them has been attached to another post in the meantime. Posts held for review are stored
+	// with Hidden set.
 	CreatePost(ctx context.Context, post Post) error
+	// UpdatePost records the current content of the post as a revision and replaces it with edit,
+	// indexing its tags and mentions again.
+	UpdatePost(ctx context.Context, postId uuid.UUID, editorId uuid.UUID, edit PostEdit, edited time.Time) error
+	// DeletePost soft deletes a post, keeping it and its revisions for moderation audits.
+	DeletePost(ctx context.Context, postId uuid.UUID, deletedBy uuid.UUID, deleted time.Time) error
+	// PublishPost publishes a draft or scheduled post, making posted its publish time. When hidden is
+	// set, the post is held for review at the same time, as HoldPost does.
+	PublishPost(ctx context.Context, postId uuid.UUID, posted time.Time, hidden *time.Time) error
+	// SchedulePost schedules a draft or scheduled post to be published at publishAt. When hidden is
+	// set, the post is held for review at the same time, as HoldPost does.
+	SchedulePost(ctx context.Context, postId uuid.UUID, publishAt time.Time, hidden *time.Time) error
+	// PublishDuePosts publishes up to limit scheduled posts that are due at now and returns them.
+	PublishDuePosts(ctx context.Context, now time.Time, limit int) ([]Post, error)
+	// HidePost hides a post from everyone but moderators. Hiding a hidden post changes nothing.
+	HidePost(ctx context.Context, postId uuid.UUID, hiddenBy uuid.UUID, hidden time.Time) error
+	// HoldPost hides a post the content checks held until a moderator approves it. Holding a hidden
+	// post changes nothing.
+	HoldPost(ctx context.Context, postId uuid.UUID, hidden time.Time) error
+	// ShowPost shows a hidden post to everyone again.
+	ShowPost(ctx context.Context, postId uuid.UUID) error
+	PinPost(ctx context.Context, postId uuid.UUID, pinnedBy uuid.UUID, until time.Time) error
+	UnpinPost(ctx context.Context, postId uuid.UUID) error
+	// SetPrayerState replaces the lifecycle of a prayer post. A new follow-up date is reminded again.
+	SetPrayerState(ctx context.Context, postId uuid.UUID, state PrayerState) error
+	MarkPrayerFollowUpSent(ctx context.Context, postId uuid.UUID) error
+	// MarkPrayerFollowUpFailed records when a reminder last failed to send.
+	MarkPrayerFollowUpFailed(ctx context.Context, postId uuid.UUID, failed time.Time) error
 }
 
 type FeedStore interface {
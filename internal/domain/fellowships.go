@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,24 +11,73 @@ type FellowshipMember struct {
 	FellowshipId uuid.UUID
 	UserId       uuid.UUID
 	Access       AccessLevel
+	// FollowParentNotices opts the member in to the Notices circles of the fellowship's ancestors.
+	FollowParentNotices bool
+	// WorshipLeader lets the member edit the fellowship's song library.
+	WorshipLeader bool
+	// Joined is when the user became a member of the fellowship.
+	Joined time.Time
 }
 
 type Fellowship struct {
-	Id        uuid.UUID `json:"id"`
-	CreatorId uuid.UUID `json:"creatorId"`
-	Name      string    `json:"name"`
+	Id          uuid.UUID  `json:"id"`
+	CreatorId   uuid.UUID  `json:"creatorId"`
+	ParentId    *uuid.UUID `json:"parentId,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	IsPublic    bool       `json:"isPublic"`
+	// Timezone is the IANA name of the time zone the fellowship's events are kept in.
+	Timezone string `json:"timezone"`
+}
+
+// FellowshipSearchCursor is the keyset position of the last fellowship returned by a search.
+type FellowshipSearchCursor struct {
+	Rank float32   `json:"r"`
+	Id   uuid.UUID `json:"i"`
 }
 
 type FellowshipStoreReader interface {
 	GetUserFellowships(ctx context.Context, userId uuid.UUID) ([]Fellowship, error)
 	GetUserFellowshipIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	GetFellowship(ctx context.Context, fellowshipId uuid.UUID) (*Fellowship, error)
+	GetChildFellowships(ctx context.Context, parentId uuid.UUID) ([]Fellowship, error)
+	// GetFellowshipAncestorIDs returns the parent chain of a fellowship, nearest first.
+	GetFellowshipAncestorIDs(ctx context.Context, fellowshipId uuid.UUID) ([]uuid.UUID, error)
+	// GetFellowshipSubtreeHeight returns how many levels of fellowships lie beneath a fellowship,
+	// or 0 when it has no children.
+	GetFellowshipSubtreeHeight(ctx context.Context, fellowshipId uuid.UUID) (int, error)
+	// GetUserAccessLevel returns the user's effective access level, where an Owner or Admin
+	// of an ancestor fellowship has Admin access to every fellowship beneath it.
+	// It returns ErrNotMember when the user has no access.
 	GetUserAccessLevel(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (AccessLevel, error)
 	GetFellowshipMembers(ctx context.Context, fellowshipId uuid.UUID) ([]FellowshipMember, error)
+	// GetFellowshipMembersNamed returns the members of a fellowship whose display names, in lower
+	// case, are among names.
+	GetFellowshipMembersNamed(ctx context.Context, fellowshipId uuid.UUID, names []string) ([]User, error)
+	// GetModeratedFellowshipIDs returns the fellowships where the user's effective access, as
+	// GetUserAccessLevel finds it, is Moderator or above.
+	GetModeratedFellowshipIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	// GetMemberJoined returns when the user joined the fellowship itself.
+	// It returns ErrNotMember when the user is not a member of it.
+	GetMemberJoined(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (time.Time, error)
+	// IsWorshipLeader reports whether the user is a worship leader of the fellowship itself.
+	IsWorshipLeader(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (bool, error)
+	// SearchPublicFellowships returns public fellowships matching query, best match first.
+	// An empty query lists all public fellowships. The returned cursor is nil on the last page.
+	SearchPublicFellowships(ctx context.Context, query string, limit *int, cursor *FellowshipSearchCursor) ([]Fellowship, *FellowshipSearchCursor, error)
 }
 
 type FellowshipStoreWriter interface {
 	CreateFellowship(ctx context.Context, fellowship Fellowship) error
 	AddFellowshipMember(ctx context.Context, member FellowshipMember) error
+	// RemoveFellowshipMember removes a member from a fellowship, its circles and their join requests.
+	RemoveFellowshipMember(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID) error
+	// SetFellowshipParent moves a fellowship under parentId, or makes it top level when parentId is nil.
+	SetFellowshipParent(ctx context.Context, fellowshipId uuid.UUID, parentId *uuid.UUID) error
+	SetFollowParentNotices(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID, follow bool) error
+	SetFellowshipTimezone(ctx context.Context, fellowshipId uuid.UUID, timezone string) error
+	SetFellowshipDetails(ctx context.Context, fellowshipId uuid.UUID, description string, isPublic bool) error
+	SetWorshipLeader(ctx context.Context, fellowshipId uuid.UUID, userId uuid.UUID, leader bool) error
 }
 
 type FellowshipStore interface {
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportStatus is where a report is in the moderation queue.
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportDismissed ReportStatus = "dismissed"
+	ReportActioned  ReportStatus = "actioned"
+)
+
+// ModerationAction is what a moderator did about reported content.
+type ModerationAction string
+
+const (
+	// ModerationDismiss closes reports without acting on the content.
+	ModerationDismiss ModerationAction = "dismiss"
+	// ModerationHide hides the content from everyone but moderators.
+	ModerationHide ModerationAction = "hide"
+	// ModerationWarn emails the author a warning.
+	ModerationWarn ModerationAction = "warn"
+	// ModerationRemove removes the author from the fellowship and its circles.
+	ModerationRemove ModerationAction = "remove"
+)
+
+func (a ModerationAction) IsValid() bool {
+	switch a {
+	case ModerationDismiss, ModerationHide, ModerationWarn, ModerationRemove:
+		return true
+	}
+
+	return false
+}
+
+// Report is a member's complaint about a post, or a comment on it when CommentId is set. Reports are
+// queued for the moderators of the fellowship the content was posted in, or its circle's fellowship.
+type Report struct {
+	Id           uuid.UUID `json:"id"`
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	// ReporterId is nil for posts held for review by the content checks. Dismissing those reports
+	// approves the post.
+	ReporterId *uuid.UUID `json:"reporterId,omitempty"`
+	PostId     uuid.UUID  `json:"postId"`
+	CommentId  *uuid.UUID `json:"commentId,omitempty"`
+	// AuthorId is the author of the reported content.
+	AuthorId   uuid.UUID    `json:"authorId"`
+	Reason     string       `json:"reason"`
+	Created    time.Time    `json:"created"`
+	Status     ReportStatus `json:"status"`
+	ResolvedBy *uuid.UUID   `json:"resolvedBy,omitempty"`
+	Resolved   *time.Time   `json:"resolved,omitempty"`
+}
+
+// ContentOutcome is the result of checking a new post's content.
+type ContentOutcome string
+
+const (
+	ContentAllow ContentOutcome = "allow"
+	// ContentHold hides the post and queues it for the moderators to approve.
+	ContentHold ContentOutcome = "hold"
+	// ContentReject refuses the post.
+	ContentReject ContentOutcome = "reject"
+)
+
+// BlockedWord is a word or phrase a fellowship does not allow in posts, with what happens to posts
+// that use it.
+type BlockedWord struct {
+	Word    string         `json:"word"`
+	Outcome ContentOutcome `json:"outcome"`
+}
+
+// ModerationLogEntry records a moderator action for the fellowship's audit trail.
+type ModerationLogEntry struct {
+	Id           uuid.UUID        `json:"id"`
+	FellowshipId uuid.UUID        `json:"fellowshipId"`
+	ModeratorId  uuid.UUID        `json:"moderatorId"`
+	Action       ModerationAction `json:"action"`
+	ReportId     *uuid.UUID       `json:"reportId,omitempty"`
+	TargetUserId uuid.UUID        `json:"targetUserId"`
+	PostId       *uuid.UUID       `json:"postId,omitempty"`
+	CommentId    *uuid.UUID       `json:"commentId,omitempty"`
+	Note         string           `json:"note"`
+	Created      time.Time        `json:"created"`
+}
+
+// ModerationCursor is the keyset position of the last report or log entry returned in a page.
+type ModerationCursor struct {
+	Created time.Time `json:"c"`
+	Id      uuid.UUID `json:"i"`
+}
+
+type ModerationStoreReader interface {
+	GetReport(ctx context.Context, reportId uuid.UUID) (*Report, error)
+	// GetOpenReports returns a fellowship's open reports, oldest first. The returned cursor is nil on the last page.
+	GetOpenReports(ctx context.Context, fellowshipId uuid.UUID, limit *int, cursor *ModerationCursor) ([]Report, *ModerationCursor, error)
+	// GetModerationLog returns a fellowship's moderator actions, newest first. The returned cursor is nil on the last page.
+	GetModerationLog(ctx context.Context, fellowshipId uuid.UUID, limit *int, cursor *ModerationCursor) ([]ModerationLogEntry, *ModerationCursor, error)
+	// GetBlockedWords returns a fellowship's blocked words in alphabetical order.
+	GetBlockedWords(ctx context.Context, fellowshipId uuid.UUID) ([]BlockedWord, error)
+}
+
+type ModerationStoreWriter interface {
+	// CreateReport queues a report. It returns ErrAlreadyReported if the reporter already has an open
+	// report on the same content.
+	CreateReport(ctx context.Context, report Report) error
+	// ResolveReport resolves the open report entry.ReportId, and every other open report on the same
+	// content, as actioned, or as dismissed when the action is a dismissal, and appends entry to the
+	// audit trail, all at once. It returns sql.ErrNoRows if the report is not open, so that only one
+	// moderator acts on it.
+	ResolveReport(ctx context.Context, entry ModerationLogEntry) error
+	// ReopenReport undoes ResolveReport for an action that could not be carried out: the reports it
+	// resolved return to the queue and entry is removed from the audit trail.
+	ReopenReport(ctx context.Context, entry ModerationLogEntry) error
+	// SetBlockedWords replaces a fellowship's blocked words.
+	SetBlockedWords(ctx context.Context, fellowshipId uuid.UUID, words []BlockedWord) error
+}
+
+type ModerationStore interface {
+	ModerationStoreReader
+	ModerationStoreWriter
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll asks the members who can see a post to vote on its options. The post's heading is the question.
+type Poll struct {
+	Options []string `json:"options"`
+	// MultipleChoice lets members vote for more than one option.
+	MultipleChoice bool `json:"multipleChoice"`
+	// Anonymous polls never show who voted for what.
+	Anonymous bool `json:"anonymous"`
+	// Closes is when voting ends. Polls without it stay open.
+	Closes *time.Time `json:"closes,omitempty"`
+}
+
+// IsClosed reports whether voting on the poll has ended at now.
+func (p Poll) IsClosed(now time.Time) bool {
+	return p.Closes != nil && !now.Before(*p.Closes)
+}
+
+// PollVote is a member's one vote on a poll, listing the indexes of the options chosen.
+type PollVote struct {
+	PostId  uuid.UUID
+	UserId  uuid.UUID
+	Options []int
+	Voted   time.Time
+}
+
+// PollResults are the votes cast on a poll so far.
+type PollResults struct {
+	Options []PollOptionResult `json:"options"`
+	// Voters is how many members have voted.
+	Voters int  `json:"voters"`
+	Closed bool `json:"closed"`
+	// MyVote lists the options the requesting member voted for, and is empty if they have not voted.
+	MyVote []int `json:"myVote"`
+}
+
+type PollOptionResult struct {
+	Option string `json:"option"`
+	Count  int    `json:"count"`
+	// VoterIDs are the members who chose the option, for polls that are not anonymous.
+	VoterIDs []uuid.UUID `json:"voterIds,omitempty"`
+}
+
+type PollStoreReader interface {
+	// GetPollVotes returns every vote cast on a poll, oldest first.
+	GetPollVotes(ctx context.Context, postId uuid.UUID) ([]PollVote, error)
+}
+
+type PollStoreWriter interface {
+	// AddPollVote records a vote. It returns ErrAlreadyVoted if the member has already voted on the poll.
+	AddPollVote(ctx context.Context, vote PollVote) error
+}
+
+type PollStore interface {
+	PollStoreReader
+	PollStoreWriter
+}
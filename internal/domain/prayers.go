@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrayerStatus tracks a prayer request from when it is posted until it is answered or put away.
+type PrayerStatus string
+
+const (
+	PrayerActive   PrayerStatus = "active"
+	PrayerAnswered PrayerStatus = "answered"
+	PrayerArchived PrayerStatus = "archived"
+)
+
+func (s PrayerStatus) IsValid() bool {
+	return s == PrayerActive || s == PrayerAnswered || s == PrayerArchived
+}
+
+// PrayerState is the lifecycle of a prayer post. Testimony is only kept for answered prayers.
+type PrayerState struct {
+	Status    PrayerStatus `json:"status"`
+	Testimony string       `json:"testimony,omitempty"`
+	// FollowUp is when the author is reminded to share an update on an active prayer.
+	FollowUp *time.Time `json:"followUp,omitempty"`
+}
+
+// PrayerFollowUp is a reminder due to be sent to the author of an active prayer request.
+type PrayerFollowUp struct {
+	PostId      uuid.UUID
+	Heading     string
+	AuthorName  string
+	AuthorEmail string
+}
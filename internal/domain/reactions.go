@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReactionKind is a reaction members can add to a post. The kinds allowed on a post are
+// decided by the type of circle it is posted in.
+type ReactionKind string
+
+const (
+	ReactionAmen   ReactionKind = "amen"
+	ReactionHeart  ReactionKind = "heart"
+	ReactionPraise ReactionKind = "praise"
+	ReactionPrayed ReactionKind = "prayed"
+)
+
+type Reaction struct {
+	PostId  uuid.UUID
+	UserId  uuid.UUID
+	Kind    ReactionKind
+	Reacted time.Time
+}
+
+// ReactionCount is the number of users who reacted to a post with a kind of reaction.
+type ReactionCount struct {
+	Kind        ReactionKind `json:"kind"`
+	Count       int          `json:"count"`
+	ReactedByMe bool         `json:"reactedByMe"`
+}
+
+type ReactionStoreReader interface {
+	// GetReactionCounts returns the non-zero reaction counts of each post, marking the reactions made by userId.
+	GetReactionCounts(ctx context.Context, postIDs []uuid.UUID, userId uuid.UUID) (map[uuid.UUID][]ReactionCount, error)
+}
+
+type ReactionStoreWriter interface {
+	// ToggleReaction adds the reaction if the user has not made it yet and removes it otherwise,
+	// keeping the post's count in step. It reports whether the reaction is now present.
+	ToggleReaction(ctx context.Context, reaction Reaction) (bool, error)
+}
+
+type ReactionStore interface {
+	ReactionStoreReader
+	ReactionStoreWriter
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadMarker is the newest post a user has read in a fellowship or circle. Posts after it, in feed
+// order, are unread.
+type ReadMarker struct {
+	UserId uuid.UUID
+	// ContainerId is the fellowship or circle the marker is for.
+	ContainerId uuid.UUID
+	Posted      time.Time
+	PostId      uuid.UUID
+}
+
+// UnreadCount is how many posts a user has not read in a fellowship or circle. Only one of
+// FellowshipId and CircleId is set, and Count stops at UnreadCountMax.
+type UnreadCount struct {
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	CircleId     uuid.UUID `json:"circleId"`
+	Count        int       `json:"count"`
+}
+
+type ReadStoreReader interface {
+	// GetUnreadCounts returns the number of published posts by other users after the user's read
+	// markers in each of fellowshipIDs and circleIDs, counting at most limit posts in each. Fellowship
+	// counts only include posts made to the fellowship itself, not its circles.
+	GetUnreadCounts(ctx context.Context, userId uuid.UUID, fellowshipIDs []uuid.UUID, circleIDs []uuid.UUID, limit int) ([]UnreadCount, error)
+}
+
+type ReadStoreWriter interface {
+	// MarkRead moves the user's read marker forward to marker. Markers never move back to older posts.
+	MarkRead(ctx context.Context, marker ReadMarker) error
+}
+
+type ReadStore interface {
+	ReadStoreReader
+	ReadStoreWriter
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Song is an entry in a fellowship's song library. Its CCLI details are what the fellowship reports
+// under its CCLI licence when the song is used.
+type Song struct {
+	Id           uuid.UUID `json:"id"`
+	FellowshipId uuid.UUID `json:"fellowshipId"`
+	Title        string    `json:"title"`
+	Authors      []string  `json:"authors"`
+	// CCLINumber is the song's number in the CCLI catalogue, and is empty for songs not licensed through CCLI.
+	CCLINumber string `json:"ccliNumber"`
+	Copyright  string `json:"copyright"`
+	// DefaultKey is the key the song is usually played in, such as G, Bb or F#m.
+	DefaultKey string `json:"defaultKey"`
+	// Tempo is in beats per minute, and is 0 when not known.
+	Tempo         int      `json:"tempo"`
+	TimeSignature string   `json:"timeSignature"`
+	Themes        []string `json:"themes"`
+	// Lyrics is left empty in search results.
+	Lyrics    string     `json:"lyrics,omitempty"`
+	CreatorId uuid.UUID  `json:"creatorId"`
+	Created   time.Time  `json:"created"`
+	Edited    *time.Time `json:"edited,omitempty"`
+}
+
+// SongInput describes a new song, or the new details of a song being edited.
+type SongInput struct {
+	FellowshipId  uuid.UUID
+	Title         string
+	Authors       []string
+	CCLINumber    string
+	Copyright     string
+	DefaultKey    string
+	Tempo         int
+	TimeSignature string
+	Themes        []string
+	Lyrics        string
+}
+
+// SongFilter narrows a song search. Query matches titles, authors and lyrics, and Theme is matched
+// exactly. Empty fields match every song.
+type SongFilter struct {
+	Query string
+	Theme string
+}
+
+// SongSearchCursor is the keyset position of the last song returned by a search.
+type SongSearchCursor struct {
+	Rank  float32   `json:"r"`
+	Title string    `json:"t"`
+	Id    uuid.UUID `json:"i"`
+}
+
+type SongStoreReader interface {
+	GetSong(ctx context.Context, songId uuid.UUID) (*Song, error)
+	// SearchSongs returns a fellowship's songs matching filter without their lyrics, best match
+	// first and then by title. The returned cursor is nil on the last page.
+	SearchSongs(ctx context.Context, fellowshipId uuid.UUID, filter SongFilter, limit *int, cursor *SongSearchCursor) ([]Song, *SongSearchCursor, error)
+}
+
+type SongStoreWriter interface {
+	// CreateSong and UpdateSong return ErrDuplicateSong when another song in the fellowship has the
+	// same CCLI number.
+	CreateSong(ctx context.Context, song Song) error
+	UpdateSong(ctx context.Context, song Song) error
+	DeleteSong(ctx context.Context, songId uuid.UUID, deleted time.Time) error
+}
+
+type SongStore interface {
+	SongStoreReader
+	SongStoreWriter
+}
@@ -42,8 +42,19 @@ type UserConnection struct {
 	AuthDetails *string
 }
 
+// UserContact is how to email a user.
+type UserContact struct {
+	UserId      uuid.UUID
+	DisplayName string
+	Email       string
+}
+
 type UserStoreReader interface {
 	GetUser(ctx context.Context, id uuid.UUID) (*User, error)
+	// GetUsers returns the users among ids that have not been deleted.
+	GetUsers(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	// GetUserContacts returns the contacts of the users who sign in with an email address.
+	GetUserContacts(ctx context.Context, ids []uuid.UUID) ([]UserContact, error)
 	GetUserConnection(ctx context.Context, signInType SignInType, accountId string) (*UserConnection, error)
 }
 
@@ -0,0 +1,221 @@
+// Package entities finds the @mentions and #tags in the articles of posts.
+//
+// Mentions and tags only start at the beginning of the text, after whitespace or after an opening
+// bracket or quote, so email addresses and the fragments of URLs are not mistaken for them.
+package entities
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Parse returns the mentions and tags in text, in order. A mention is @ followed by the display
+// name of one of members, matched regardless of case and preferring the longest name. Names shared
+// by several members are ambiguous and are not linked. At most domain.PostMaxMentions members are
+// linked; later mentions of other members are left as text.
+func Parse(text string, members []domain.User) []domain.PostEntity {
+	names := memberNames(members)
+	entities := make([]domain.PostEntity, 0)
+	mentioned := make(map[uuid.UUID]struct{})
+
+	offset := 0 // in UTF-16 code units
+	prev := ' '
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		if (r == '@' || r == '#') && startsEntity(prev) {
+			var entity domain.PostEntity
+			var length int
+
+			if r == '@' {
+				entity, length = mention(text[i+1:], names, mentioned)
+			} else {
+				entity, length = tag(text[i+1:])
+			}
+
+			if length > 0 {
+				match := text[i : i+1+length]
+				entity.Offset = offset
+				entity.Length = utf16Len(match)
+				entities = append(entities, entity)
+
+				offset += entity.Length
+				prev, _ = utf8.DecodeLastRuneInString(match)
+				i += len(match)
+				continue
+			}
+		}
+
+		offset += utf16Len(string(r))
+		prev = r
+		i += size
+	}
+
+	return entities
+}
+
+// MentionNames returns the names, in lower case, that the mentions in text could refer to: for each
+// @ that can start a mention, the words and spaces following it up to each word end within
+// domain.DisplayNameMaxLength characters. Display names are only letters, digits and spaces, so
+// looking up members by these names finds every member Parse could link.
+func MentionNames(text string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]struct{})
+	prev := ' '
+
+	for i, r := range text {
+		if r == '@' && startsEntity(prev) {
+			rest, runes := text[i+1:], 0
+			for j, c := range rest {
+				if runes == domain.DisplayNameMaxLength || (!isWordRune(c) && c != ' ') {
+					break
+				}
+
+				runes++
+				if next, _ := utf8.DecodeRuneInString(rest[j+utf8.RuneLen(c):]); isWordRune(c) && !isWordRune(next) {
+					name := strings.ToLower(rest[:j+utf8.RuneLen(c)])
+					if _, ok := seen[name]; !ok {
+						seen[name] = struct{}{}
+						names = append(names, name)
+					}
+				}
+			}
+		}
+
+		prev = r
+	}
+
+	return names
+}
+
+// NormalizeTag returns text as a tag in the form stored with posts: lower case and without a leading #.
+// It reports false for text that is not a tag.
+func NormalizeTag(text string) (string, bool) {
+	text = strings.TrimPrefix(text, "#")
+
+	entity, length := tag(text)
+	if length == 0 || length != len(text) {
+		return "", false
+	}
+
+	return entity.Tag, true
+}
+
+// name is a display name that mentions can refer to, with the member it refers to or uuid.Nil when
+// members share it.
+type name struct {
+	text   string
+	userId uuid.UUID
+}
+
+// memberNames returns the display names of members, longest first.
+func memberNames(members []domain.User) []name {
+	byName := make(map[string]int)
+	names := make([]name, 0, len(members))
+
+	for _, member := range members {
+		text := strings.TrimSpace(member.DisplayName)
+		if text == "" {
+			continue
+		}
+
+		key := strings.ToLower(text)
+		if i, ok := byName[key]; ok {
+			if names[i].userId != member.Id {
+				names[i].userId = uuid.Nil
+			}
+			continue
+		}
+
+		byName[key] = len(names)
+		names = append(names, name{text: text, userId: member.Id})
+	}
+
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i].text) > len(names[j].text) })
+	return names
+}
+
+// mention matches the longest of names at the start of rest, which follows an @, and returns the
+// mention and its length in bytes, not counting the @. It returns a zero length when nothing matches.
+func mention(rest string, names []name, mentioned map[uuid.UUID]struct{}) (domain.PostEntity, int) {
+	for _, n := range names {
+		if len(rest) < len(n.text) || !strings.EqualFold(rest[:len(n.text)], n.text) {
+			continue
+		}
+
+		// The name must end where a word does, so @Ann does not match the start of @Anna.
+		if next, _ := utf8.DecodeRuneInString(rest[len(n.text):]); isWordRune(next) {
+			continue
+		}
+
+		if n.userId == uuid.Nil {
+			return domain.PostEntity{}, 0
+		}
+
+		if _, ok := mentioned[n.userId]; !ok {
+			if len(mentioned) >= domain.PostMaxMentions {
+				return domain.PostEntity{}, 0
+			}
+
+			mentioned[n.userId] = struct{}{}
+		}
+
+		userId := n.userId
+		return domain.PostEntity{Type: domain.PostEntityMention, UserId: &userId}, len(n.text)
+	}
+
+	return domain.PostEntity{}, 0
+}
+
+// tag matches the tag at the start of rest, which follows a #, and returns it with its length in
+// bytes, not counting the #. Tags are letters, digits and underscores and cannot be only digits,
+// so that "#1" stays a number. It returns a zero length when there is no tag.
+func tag(rest string) (domain.PostEntity, int) {
+	length, runes, letters := 0, 0, false
+
+	for length < len(rest) {
+		r, size := utf8.DecodeRuneInString(rest[length:])
+		if !isWordRune(r) {
+			break
+		}
+
+		letters = letters || !unicode.IsDigit(r)
+		length += size
+		runes++
+	}
+
+	if runes == 0 || runes > domain.TagMaxLength || !letters {
+		return domain.PostEntity{}, 0
+	}
+
+	return domain.PostEntity{Type: domain.PostEntityTag, Tag: strings.ToLower(rest[:length])}, length
+}
+
+// startsEntity reports whether an @ or # after prev can start a mention or tag.
+func startsEntity(prev rune) bool {
+	return unicode.IsSpace(prev) || strings.ContainsRune("([{\"'", prev)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestParse(t *testing.T) {
+	ann, annaLee := uuid.New(), uuid.New()
+	members := []domain.User{
+		{Id: ann, DisplayName: "Ann"},
+		{Id: annaLee, DisplayName: "Anna Lee"},
+	}
+
+	mention := func(offset, length int, userId uuid.UUID) domain.PostEntity {
+		return domain.PostEntity{Type: domain.PostEntityMention, Offset: offset, Length: length, UserId: &userId}
+	}
+	tag := func(offset, length int, text string) domain.PostEntity {
+		return domain.PostEntity{Type: domain.PostEntityTag, Offset: offset, Length: length, Tag: text}
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []domain.PostEntity
+	}{
+		{
+			name: "tag at start and end",
+			text: "#Grace be with you #amen",
+			want: []domain.PostEntity{tag(0, 6, "grace"), tag(19, 5, "amen")},
+		},
+		{
+			name: "mention at start and end",
+			text: "@ann thanks @Anna Lee",
+			want: []domain.PostEntity{mention(0, 4, ann), mention(12, 9, annaLee)},
+		},
+		{
+			name: "longest name preferred",
+			text: "hi @Anna Lee",
+			want: []domain.PostEntity{mention(3, 9, annaLee)},
+		},
+		{
+			name: "name must end at a word end",
+			text: "@Annabel",
+			want: []domain.PostEntity{},
+		},
+		{
+			name: "adjacent punctuation",
+			text: "(#hope), \"@Ann\"! #joy.",
+			want: []domain.PostEntity{tag(1, 5, "hope"), mention(10, 4, ann), tag(17, 4, "joy")},
+		},
+		{
+			name: "at sign in email address",
+			text: "mail ann@example.com or x@Ann",
+			want: []domain.PostEntity{},
+		},
+		{
+			name: "hash inside url",
+			text: "https://example.com/#top",
+			want: []domain.PostEntity{},
+		},
+		{
+			name: "digits only tag",
+			text: "psalm #23 and #23rd",
+			want: []domain.PostEntity{tag(14, 5, "23rd")},
+		},
+		{
+			name: "offsets in utf-16 code units",
+			text: "🙏 é #peace",
+			want: []domain.PostEntity{tag(5, 6, "peace")},
+		},
+		{
+			name: "offsets after an entity with astral characters",
+			text: "#𝒜b @Ann",
+			want: []domain.PostEntity{tag(0, 4, "𝒜b"), mention(5, 4, ann)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Parse(test.text, members); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		wantOk bool
+	}{
+		{text: "#Grace", want: "grace", wantOk: true},
+		{text: "hope_2", want: "hope_2", wantOk: true},
+		{text: "#23"},
+		{text: "two words"},
+		{text: "#joy!"},
+		{text: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got, ok := NormalizeTag(test.text)
+			if got != test.want || ok != test.wantOk {
+				t.Errorf("NormalizeTag(%q) = %q, %v, want %q, %v", test.text, got, ok, test.want, test.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+const productId = "-//Tools of Worship//Events//EN"
+
+// refreshInterval is how often calendar apps are asked to fetch a feed again.
+const refreshInterval = "PT1H"
+
+// timezoneYears is how many years past the latest event start VTIMEZONE components describe.
+const timezoneYears = 5
+
+// Calendar is a feed of events.
+type Calendar struct {
+	// Name is what calendar apps call the calendar.
+	Name   string
+	Events []domain.Event
+}
+
+// Encode writes the calendar as an RFC 5545 VCALENDAR. Events keep the timezone they were saved in,
+// each described by a VTIMEZONE. Cancelled occurrences of recurring events become EXDATEs and moved
+// ones separate VEVENTs with a RECURRENCE-ID.
+func (c Calendar) Encode(out io.Writer, now time.Time) error {
+	locations := make(map[string]*time.Location)
+	var zones []string
+	var first, last time.Time
+
+	for _, event := range c.Events {
+		if _, ok := locations[event.Timezone]; !ok {
+			loc, err := time.LoadLocation(event.Timezone)
+			if err != nil {
+				return fmt.Errorf("failed to load timezone %q of event %s: %v", event.Timezone, event.Id, err)
+			}
+
+			locations[event.Timezone] = loc
+			if loc != time.UTC {
+				zones = append(zones, event.Timezone)
+			}
+		}
+
+		if first.IsZero() || event.Start.Before(first) {
+			first = event.Start
+		}
+
+		if event.Start.After(last) {
+			last = event.Start
+		}
+	}
+
+	if now.After(last) {
+		last = now
+	}
+
+	w := &writer{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", productId)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("NAME", c.Name)
+	w.text("X-WR-CALNAME", c.Name)
+	w.line("REFRESH-INTERVAL", refreshInterval, "VALUE=DURATION")
+	w.line("X-PUBLISHED-TTL", refreshInterval)
+
+	for _, zone := range zones {
+		loc := locations[zone]
+		from := time.Date(first.In(loc).Year(), time.January, 1, 0, 0, 0, 0, loc)
+		w.timezone(loc, from, last.AddDate(timezoneYears, 0, 0))
+	}
+
+	for _, event := range c.Events {
+		w.event(event, locations[event.Timezone])
+	}
+
+	w.line("END", "VCALENDAR")
+
+	_, err := io.WriteString(out, w.b.String())
+	return err
+}
+
+// event writes an event's VEVENT, followed by one for each of its moved occurrences.
+func (w *writer) event(event domain.Event, loc *time.Location) {
+	uid := event.Id.String()
+	modified := event.Created
+	if event.Edited != nil {
+		modified = *event.Edited
+	}
+
+	start, end := event.Start.In(loc), event.End.In(loc)
+	status := ""
+
+	// One-off events take their exception, if any, themselves.
+	if exception := event.Exception(event.Start); event.RRule == "" && exception != nil {
+		if exception.Cancelled {
+			status = "CANCELLED"
+		} else if exception.Start != nil && exception.End != nil {
+			start, end = exception.Start.In(loc), exception.End.In(loc)
+		}
+	}
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", uid)
+	w.dateTime("DTSTAMP", modified.UTC())
+	w.dateTime("CREATED", event.Created.UTC())
+	w.dateTime("LAST-MODIFIED", modified.UTC())
+	w.dateTime("DTSTART", start)
+	w.dateTime("DTEND", end)
+	w.details(event)
+
+	if status != "" {
+		w.line("STATUS", status)
+	}
+
+	if event.RRule != "" {
+		w.line("RRULE", event.RRule)
+
+		for _, exception := range event.Exceptions {
+			if exception.Cancelled {
+				w.dateTime("EXDATE", exception.Occurrence.In(loc))
+			}
+		}
+	}
+
+	w.line("END", "VEVENT")
+
+	if event.RRule == "" {
+		return
+	}
+
+	for _, exception := range event.Exceptions {
+		if exception.Cancelled || exception.Start == nil || exception.End == nil {
+			continue
+		}
+
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", uid)
+		w.dateTime("DTSTAMP", modified.UTC())
+		w.dateTime("RECURRENCE-ID", exception.Occurrence.In(loc))
+		w.dateTime("DTSTART", exception.Start.In(loc))
+		w.dateTime("DTEND", exception.End.In(loc))
+		w.details(event)
+		w.line("END", "VEVENT")
+	}
+}
+
+func (w *writer) details(event domain.Event) {
+	w.text("SUMMARY", event.Title)
+
+	if event.Location != "" {
+		w.text("LOCATION", event.Location)
+	}
+
+	if event.Description != "" {
+		w.text("DESCRIPTION", event.Description)
+	}
+}
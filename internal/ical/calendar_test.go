@@ -0,0 +1,124 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestEncode(t *testing.T) {
+	loc := mustLoadLocation(t, "Africa/Johannesburg")
+	movedStart, movedEnd := time.Date(2026, 3, 15, 11, 0, 0, 0, loc), time.Date(2026, 3, 15, 12, 30, 0, 0, loc)
+	edited := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	calendar := Calendar{
+		Name: "Grace Fellowship, Events",
+		Events: []domain.Event{
+			{
+				Id:          uuid.MustParse("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+				Title:       "Sunday service",
+				Location:    "Main hall; upstairs",
+				Description: "Bring a friend.\nAll welcome, as always, and coffee is served afterwards in the foyer by the café team.",
+				Start:       time.Date(2026, 3, 1, 10, 0, 0, 0, loc),
+				End:         time.Date(2026, 3, 1, 11, 30, 0, 0, loc),
+				Timezone:    "Africa/Johannesburg",
+				RRule:       "FREQ=WEEKLY;COUNT=4",
+				Created:     time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+				Exceptions: []domain.EventException{
+					{Occurrence: time.Date(2026, 3, 8, 10, 0, 0, 0, loc), Cancelled: true},
+					{Occurrence: time.Date(2026, 3, 15, 10, 0, 0, 0, loc), Start: &movedStart, End: &movedEnd},
+				},
+			},
+			{
+				Id:       uuid.MustParse("0a1b2c3d-4e5f-4a6b-8c7d-8e9f0a1b2c3d"),
+				Title:    "Prayer meeting",
+				Start:    time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC),
+				End:      time.Date(2026, 3, 4, 19, 0, 0, 0, time.UTC),
+				Timezone: "UTC",
+				Created:  time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+				Edited:   &edited,
+				Exceptions: []domain.EventException{
+					{Occurrence: time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC), Cancelled: true},
+				},
+			},
+		},
+	}
+
+	want := crlf(
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Tools of Worship//Events//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`NAME:Grace Fellowship\, Events`,
+		`X-WR-CALNAME:Grace Fellowship\, Events`,
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+		"BEGIN:VTIMEZONE",
+		"TZID:Africa/Johannesburg",
+		"BEGIN:STANDARD",
+		"DTSTART:20260101T000000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0200",
+		"TZNAME:SAST",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b",
+		"DTSTAMP:20260201T120000Z",
+		"CREATED:20260201T120000Z",
+		"LAST-MODIFIED:20260201T120000Z",
+		"DTSTART;TZID=Africa/Johannesburg:20260301T100000",
+		"DTEND;TZID=Africa/Johannesburg:20260301T113000",
+		"SUMMARY:Sunday service",
+		`LOCATION:Main hall\; upstairs`,
+		`DESCRIPTION:Bring a friend.\nAll welcome\, as always\, and coffee is served`,
+		"  afterwards in the foyer by the café team.",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE;TZID=Africa/Johannesburg:20260308T100000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b",
+		"DTSTAMP:20260201T120000Z",
+		"RECURRENCE-ID;TZID=Africa/Johannesburg:20260315T100000",
+		"DTSTART;TZID=Africa/Johannesburg:20260315T110000",
+		"DTEND;TZID=Africa/Johannesburg:20260315T123000",
+		"SUMMARY:Sunday service",
+		`LOCATION:Main hall\; upstairs`,
+		`DESCRIPTION:Bring a friend.\nAll welcome\, as always\, and coffee is served`,
+		"  afterwards in the foyer by the café team.",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:0a1b2c3d-4e5f-4a6b-8c7d-8e9f0a1b2c3d",
+		"DTSTAMP:20260203T100000Z",
+		"CREATED:20260202T090000Z",
+		"LAST-MODIFIED:20260203T100000Z",
+		"DTSTART:20260304T180000Z",
+		"DTEND:20260304T190000Z",
+		"SUMMARY:Prayer meeting",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+
+	var b strings.Builder
+	if err := calendar.Encode(&b, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	if got := b.String(); got != want {
+		t.Errorf("Encode =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEncodeUnknownTimezone(t *testing.T) {
+	calendar := Calendar{Events: []domain.Event{{Id: uuid.New(), Timezone: "Nowhere/Special"}}}
+
+	var b strings.Builder
+	if err := calendar.Encode(&b, time.Now()); err == nil {
+		t.Error("Encode of an event in an unknown timezone succeeded")
+	}
+}
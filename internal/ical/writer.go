@@ -0,0 +1,141 @@
+// Package ical writes iCalendar (RFC 5545) feeds of events, with their recurrence rules, exceptions
+// and the VTIMEZONE definitions of their timezones, for calendar apps to subscribe to.
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest a content line can be, excluding its CRLF, before it is folded.
+const maxLineOctets = 75
+
+// writer builds iCalendar content lines, folding long lines and ending each with CRLF.
+type writer struct {
+	b strings.Builder
+}
+
+// line writes a property. params are written as given, so their values must not need quoting.
+func (w *writer) line(name, value string, params ...string) {
+	line := name
+	for _, param := range params {
+		line += ";" + param
+	}
+
+	w.fold(line + ":" + value)
+}
+
+// text writes a property with a TEXT value, escaping it.
+func (w *writer) text(name, value string) {
+	w.line(name, escapeText(value))
+}
+
+// dateTime writes a DATE-TIME property, in UTC or as a local time with the TZID of t's location.
+func (w *writer) dateTime(name string, t time.Time) {
+	if t.Location() == time.UTC {
+		w.line(name, t.Format("20060102T150405Z"))
+		return
+	}
+
+	w.line(name, t.Format("20060102T150405"), "TZID="+t.Location().String())
+}
+
+// fold writes a content line, breaking it before maxLineOctets with CRLF followed by a space, and
+// never within a UTF-8 character.
+func (w *writer) fold(line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // the space starting the continuation counts
+	}
+
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+// escapeText escapes a TEXT value. Line breaks become \n and other control characters, which TEXT
+// cannot hold, are dropped.
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == ';' || r == ',':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r':
+			b.WriteString(`\n`)
+		case r == '\t' || (r >= 0x20 && r != 0x7f):
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// timezone writes a VTIMEZONE for loc with one observance for each change of offset between from
+// and to, which is all calendar apps need to place times in that span.
+func (w *writer) timezone(loc *time.Location, from, to time.Time) {
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", loc.String())
+
+	t := from.In(loc)
+	name, offset := t.Zone()
+	w.observance(t.IsDST(), t, offset, offset, name)
+
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			break
+		}
+
+		previous := offset
+		t = end.In(loc)
+		name, offset = t.Zone()
+
+		// Observances start at the local time of the change, on the clock that was in use before it.
+		w.observance(t.IsDST(), end.UTC().Add(time.Duration(previous)*time.Second), previous, offset, name)
+	}
+
+	w.line("END", "VTIMEZONE")
+}
+
+// observance writes a STANDARD or DAYLIGHT component starting at the wall clock time of start.
+func (w *writer) observance(daylight bool, start time.Time, from, to int, name string) {
+	kind := "STANDARD"
+	if daylight {
+		kind = "DAYLIGHT"
+	}
+
+	w.line("BEGIN", kind)
+	w.line("DTSTART", start.Format("20060102T150405"))
+	w.line("TZOFFSETFROM", utcOffset(from))
+	w.line("TZOFFSETTO", utcOffset(to))
+	w.text("TZNAME", name)
+	w.line("END", kind)
+}
+
+// utcOffset formats an offset in seconds east of UTC as a UTC-OFFSET value such as +0200 or -0330.
+func utcOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+
+	offset := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		offset += fmt.Sprintf("%02d", seconds%60)
+	}
+
+	return offset
+}
@@ -0,0 +1,201 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// crlf joins content lines as they are written, each ending with CRLF.
+func crlf(lines ...string) string {
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "75 octets",
+			line: "DESCRIPTION:" + strings.Repeat("a", 63),
+			want: crlf("DESCRIPTION:" + strings.Repeat("a", 63)),
+		},
+		{
+			name: "76 octets",
+			line: "DESCRIPTION:" + strings.Repeat("a", 64),
+			want: crlf("DESCRIPTION:"+strings.Repeat("a", 63), " a"),
+		},
+		{
+			// Continuation lines hold 74 octets after their leading space.
+			name: "several continuations",
+			line: strings.Repeat("a", 75) + strings.Repeat("b", 74) + strings.Repeat("c", 51),
+			want: crlf(strings.Repeat("a", 75), " "+strings.Repeat("b", 74), " "+strings.Repeat("c", 51)),
+		},
+		{
+			name: "two-octet character across the limit",
+			line: "SUMMARY:" + strings.Repeat("a", 66) + "é" + "b",
+			want: crlf("SUMMARY:"+strings.Repeat("a", 66), " éb"),
+		},
+		{
+			name: "three-octet character across the limit",
+			line: strings.Repeat("a", 73) + "€" + "b",
+			want: crlf(strings.Repeat("a", 73), " €b"),
+		},
+		{
+			name: "three-octet character ending at the limit",
+			line: strings.Repeat("a", 72) + "€" + "b",
+			want: crlf(strings.Repeat("a", 72)+"€", " b"),
+		},
+		{
+			name: "four-octet character across a continuation",
+			line: strings.Repeat("a", 75) + strings.Repeat("b", 72) + "😀" + "c",
+			want: crlf(strings.Repeat("a", 75), " "+strings.Repeat("b", 72), " 😀c"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &writer{}
+			w.fold(test.line)
+
+			got := w.b.String()
+			if got != test.want {
+				t.Errorf("fold = %q, want %q", got, test.want)
+			}
+
+			for _, line := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %q is %d octets", line, len(line))
+				}
+			}
+
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(got, "\r\n"), "\r\n ", ""); unfolded != test.line {
+				t.Errorf("unfolded = %q, want %q", unfolded, test.line)
+			}
+		})
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Hall, room 2; upstairs", want: `Hall\, room 2\; upstairs`},
+		{value: `C:\songs`, want: `C:\\songs`},
+		{value: "Time: 10:00 \"sharp\"", want: "Time: 10:00 \"sharp\""},
+		{value: "one\r\ntwo\nthree\rfour", want: `one\ntwo\nthree\nfour`},
+		{value: "tab\there", want: "tab\there"},
+		{value: "bell\x07null\x00del\x7f", want: "bellnulldel"},
+		{value: "Café ☕", want: "Café ☕"},
+		{value: "", want: ""},
+	}
+
+	for _, test := range tests {
+		if got := escapeText(test.value); got != test.want {
+			t.Errorf("escapeText(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestUTCOffset(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{seconds: 0, want: "+0000"},
+		{seconds: 2 * 3600, want: "+0200"},
+		{seconds: 5*3600 + 30*60, want: "+0530"},
+		{seconds: -(3*3600 + 30*60), want: "-0330"},
+		{seconds: -(4*3600 + 56*60 + 2), want: "-045602"},
+	}
+
+	for _, test := range tests {
+		if got := utcOffset(test.seconds); got != test.want {
+			t.Errorf("utcOffset(%d) = %s, want %s", test.seconds, got, test.want)
+		}
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	johannesburg := mustLoadLocation(t, "Africa/Johannesburg")
+
+	tests := []struct {
+		name     string
+		loc      *time.Location
+		from, to time.Time
+		want     string
+	}{
+		{
+			name: "daylight saving",
+			loc:  newYork,
+			from: time.Date(2026, time.January, 1, 0, 0, 0, 0, newYork),
+			to:   time.Date(2027, time.January, 1, 0, 0, 0, 0, newYork),
+			want: crlf(
+				"BEGIN:VTIMEZONE",
+				"TZID:America/New_York",
+				"BEGIN:STANDARD",
+				"DTSTART:20260101T000000",
+				"TZOFFSETFROM:-0500",
+				"TZOFFSETTO:-0500",
+				"TZNAME:EST",
+				"END:STANDARD",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20260308T020000",
+				"TZOFFSETFROM:-0500",
+				"TZOFFSETTO:-0400",
+				"TZNAME:EDT",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:20261101T020000",
+				"TZOFFSETFROM:-0400",
+				"TZOFFSETTO:-0500",
+				"TZNAME:EST",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			),
+		},
+		{
+			name: "no daylight saving",
+			loc:  johannesburg,
+			from: time.Date(2026, time.January, 1, 0, 0, 0, 0, johannesburg),
+			to:   time.Date(2031, time.January, 1, 0, 0, 0, 0, johannesburg),
+			want: crlf(
+				"BEGIN:VTIMEZONE",
+				"TZID:Africa/Johannesburg",
+				"BEGIN:STANDARD",
+				"DTSTART:20260101T000000",
+				"TZOFFSETFROM:+0200",
+				"TZOFFSETTO:+0200",
+				"TZNAME:SAST",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &writer{}
+			w.timezone(test.loc, test.from, test.to)
+
+			if got := w.b.String(); got != test.want {
+				t.Errorf("timezone =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s is not available: %v", name, err)
+	}
+
+	return loc
+}
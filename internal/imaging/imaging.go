@@ -0,0 +1,130 @@
+// Package imaging prepares uploaded photos for sharing: it strips their metadata and makes the
+// smaller versions shown in feeds.
+//
+// Originals are cleaned without re-encoding them, so their quality is kept. The web version and
+// thumbnail are decoded, resized and encoded again, which leaves no metadata in them at all.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registers GIF with image.Decode
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	// MaxPixels bounds the size of images that are decoded. Decoding needs memory in proportion to
+	// the pixel count, which a small, highly compressed file can make enormous.
+	MaxPixels = 36_000_000
+
+	// MaxSide bounds the width and height of images that are decoded.
+	MaxSide = 12_000
+
+	// WebSize is the longest side of the web version of an image.
+	WebSize = 1600
+
+	// ThumbnailSize is the width and height of the square thumbnail of an image.
+	ThumbnailSize = 320
+
+	webQuality       = 82
+	thumbnailQuality = 80
+)
+
+var (
+	ErrTooLarge    = errors.New("image dimensions too large")
+	ErrUnsupported = errors.New("unsupported image format")
+)
+
+// Result is a processed image.
+type Result struct {
+	// Original is the uploaded file without its metadata.
+	Original []byte
+	// Web is the image scaled to fit WebSize, turned upright.
+	Web []byte
+	// Thumbnail is the middle of the image scaled to ThumbnailSize square, turned upright.
+	Thumbnail []byte
+	// Width and Height are the dimensions of the original as it is displayed.
+	Width  int
+	Height int
+}
+
+// VariantType returns the content type of the web version and thumbnail of an image of contentType.
+// Photos stay JPEG while other images become PNG so that transparency is kept.
+func VariantType(contentType string) string {
+	if contentType == "image/jpeg" {
+		return "image/jpeg"
+	}
+
+	return "image/png"
+}
+
+// Process cleans an uploaded JPEG, PNG or GIF image and makes its web version and thumbnail. The
+// dimensions are checked against MaxPixels and MaxSide before anything is decoded.
+func Process(data []byte) (*Result, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width > MaxSide || config.Height > MaxSide || config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+
+	result := &Result{Width: config.Width, Height: config.Height}
+	orientation := 1
+
+	switch format {
+	case "jpeg":
+		orientation = jpegOrientation(data)
+		result.Original, err = stripJPEG(data, orientation)
+	case "png":
+		result.Original, err = stripPNG(data)
+	case "gif":
+		result.Original, err = stripGIF(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	if orientation >= 5 {
+		result.Width, result.Height = result.Height, result.Width
+	}
+
+	// Only the first frame of an animated GIF is decoded.
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	web := orient(fit(img, WebSize), orientation)
+	if result.Web, err = encode(web, format, webQuality); err != nil {
+		return nil, err
+	}
+
+	thumbnail := orient(resize(img, centreSquare(img.Bounds()), ThumbnailSize, ThumbnailSize), orientation)
+	if result.Thumbnail, err = encode(thumbnail, format, thumbnailQuality); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func encode(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,92 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestProcessRejectsHugeDimensions(t *testing.T) {
+	// A PNG header claiming 100000x100000 pixels with no image data after it. Decoding it would fail
+	// as unsupported, so ErrTooLarge shows the dimensions were rejected before decoding.
+	ihdr := binary.BigEndian.AppendUint32(nil, 100_000)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100_000)
+	ihdr = append(ihdr, 8, 2, 0, 0, 0) // 8-bit RGB
+
+	data := append([]byte{}, pngSignature...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	data = append(data, chunk...)
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+
+	if _, err := Process(data); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Process = %v, want %v", err, ErrTooLarge)
+	}
+}
+
+func TestProcessStripsJPEGLocation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	img.Set(0, 0, color.RGBA{R: 0xFF, A: 0xFF})
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// An EXIF segment whose first IFD points to a GPS IFD holding a latitude reference.
+	exif := []byte("Exif\x00\x00MM\x00\x2A\x00\x00\x00\x08")
+	exif = binary.BigEndian.AppendUint16(exif, 1)
+	exif = binary.BigEndian.AppendUint16(exif, 0x8825) // GPS IFD pointer
+	exif = binary.BigEndian.AppendUint16(exif, 4)      // LONG
+	exif = binary.BigEndian.AppendUint32(exif, 1)
+	exif = binary.BigEndian.AppendUint32(exif, 26)
+	exif = binary.BigEndian.AppendUint32(exif, 0)
+	exif = binary.BigEndian.AppendUint16(exif, 1)
+	exif = binary.BigEndian.AppendUint16(exif, 0x0001) // GPS latitude reference
+	exif = binary.BigEndian.AppendUint16(exif, 2)      // ASCII
+	exif = binary.BigEndian.AppendUint32(exif, 2)
+	exif = append(exif, 'N', 0, 0, 0)
+	exif = binary.BigEndian.AppendUint32(exif, 0)
+
+	var data bytes.Buffer
+	data.Write(encoded.Bytes()[:2])
+	writeJPEGSegment(&data, 0xE1, exif)
+	data.Write(encoded.Bytes()[2:])
+
+	result, err := Process(data.Bytes())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	var markers []byte
+	sos, err := scanJPEG(result.Original, func(marker byte, segment []byte) {
+		markers = append(markers, marker)
+	})
+	if err != nil {
+		t.Fatalf("cleaned original is not a valid JPEG: %v", err)
+	}
+
+	if bytes.IndexByte(markers, 0xE1) >= 0 {
+		t.Errorf("cleaned original kept an APP1 segment, markers %x", markers)
+	}
+
+	if bytes.Contains(result.Original, exif) {
+		t.Errorf("cleaned original still holds the EXIF data")
+	}
+
+	if !bytes.HasSuffix(encoded.Bytes(), result.Original[sos:]) {
+		t.Errorf("cleaned original changed the image data")
+	}
+
+	if result.Width != 16 || result.Height != 8 {
+		t.Errorf("dimensions = %dx%d, want 16x8", result.Width, result.Height)
+	}
+}
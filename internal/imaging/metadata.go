@@ -0,0 +1,291 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformed = errors.New("malformed image")
+
+// jpegKeptMarkers are the segments kept in cleaned JPEGs besides the frame and scan data: JFIF (APP0),
+// ICC colour profiles (APP2) and Adobe colour transforms (APP14). Everything else before the image
+// data, including EXIF, XMP and IPTC metadata and comments, is dropped.
+var jpegKeptMarkers = map[byte]bool{0xE0: true, 0xE2: true, 0xEE: true}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, from 1 to 8, or 1 if it has none.
+func jpegOrientation(data []byte) int {
+	orientation := 1
+
+	_, _ = scanJPEG(data, func(marker byte, segment []byte) {
+		if marker != 0xE1 || !bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return
+		}
+
+		if o := tiffOrientation(segment[6:]); o >= 1 && o <= 8 {
+			orientation = o
+		}
+	})
+
+	return orientation
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of TIFF data.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 0
+}
+
+// scanJPEG calls fn with each marker segment before the first start of scan and returns the offset
+// of that start of scan marker.
+func scanJPEG(data []byte, fn func(marker byte, segment []byte)) (int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 0, errMalformed
+	}
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return 0, errMalformed
+		}
+
+		marker := data[i+1]
+		if marker == 0xFF {
+			// Fill byte before a marker.
+			i++
+			continue
+		}
+
+		if marker == 0xDA {
+			return i, nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 0, errMalformed
+		}
+
+		fn(marker, data[i+4:i+2+length])
+		i += 2 + length
+	}
+
+	return 0, errMalformed
+}
+
+// stripJPEG removes the metadata segments of a JPEG without touching its image data. A non-default
+// orientation is carried over in a minimal EXIF segment so the image still displays upright.
+func stripJPEG(data []byte, orientation int) ([]byte, error) {
+	var out bytes.Buffer
+	out.Write([]byte{0xFF, 0xD8})
+
+	if orientation != 1 {
+		writeJPEGSegment(&out, 0xE1, orientationEXIF(orientation))
+	}
+
+	sos, err := scanJPEG(data, func(marker byte, segment []byte) {
+		isApp := marker >= 0xE0 && marker <= 0xEF
+		if marker == 0xFE || (isApp && !jpegKeptMarkers[marker]) {
+			return
+		}
+
+		writeJPEGSegment(&out, marker, segment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out.Write(data[sos:])
+	return out.Bytes(), nil
+}
+
+func writeJPEGSegment(out *bytes.Buffer, marker byte, segment []byte) {
+	out.Write([]byte{0xFF, marker})
+	_ = binary.Write(out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+}
+
+// orientationEXIF returns an EXIF segment holding only an orientation tag.
+func orientationEXIF(orientation int) []byte {
+	segment := []byte("Exif\x00\x00MM\x00\x2A\x00\x00\x00\x08")
+	segment = binary.BigEndian.AppendUint16(segment, 1)      // one entry
+	segment = binary.BigEndian.AppendUint16(segment, 0x0112) // orientation
+	segment = binary.BigEndian.AppendUint16(segment, 3)      // SHORT
+	segment = binary.BigEndian.AppendUint32(segment, 1)      // one value
+	segment = binary.BigEndian.AppendUint16(segment, uint16(orientation))
+	segment = binary.BigEndian.AppendUint16(segment, 0)
+	segment = binary.BigEndian.AppendUint32(segment, 0) // no further IFDs
+	return segment
+}
+
+// pngKeptChunks are the ancillary chunks kept in cleaned PNGs: those affecting how the image is
+// drawn, and the APNG animation chunks. Text, time and EXIF chunks are dropped.
+var pngKeptChunks = map[string]bool{
+	"tRNS": true, "gAMA": true, "cHRM": true, "sRGB": true, "iCCP": true, "sBIT": true, "pHYs": true, "bKGD": true,
+	"acTL": true, "fcTL": true, "fdAT": true,
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNG removes the metadata chunks of a PNG.
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errMalformed
+	}
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+
+	for i := len(pngSignature); i < len(data); {
+		if i+12 > len(data) {
+			return nil, errMalformed
+		}
+
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if end > len(data) {
+			return nil, errMalformed
+		}
+
+		chunkType := string(data[i+4 : i+8])
+		// Critical chunks start with an upper case letter.
+		if chunkType[0] >= 'A' && chunkType[0] <= 'Z' || pngKeptChunks[chunkType] {
+			out.Write(data[i:end])
+		}
+
+		i = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// gifKeptApplications are the application extensions kept in cleaned GIFs, which control looping.
+var gifKeptApplications = map[string]bool{"NETSCAPE2.0": true, "ANIMEXTS1.0": true}
+
+// stripGIF removes the comment and application extensions of a GIF, other than those controlling
+// animation, without decoding its frames.
+func stripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 || !(bytes.HasPrefix(data, []byte("GIF87a")) || bytes.HasPrefix(data, []byte("GIF89a"))) {
+		return nil, errMalformed
+	}
+
+	i := 13
+	if data[10]&0x80 != 0 {
+		i += 3 << (int(data[10]&0x07) + 1)
+	}
+	if i > len(data) {
+		return nil, errMalformed
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:i])
+
+	for i < len(data) {
+		start := i
+
+		switch data[i] {
+		case 0x3B: // trailer
+			out.WriteByte(0x3B)
+			return out.Bytes(), nil
+
+		case 0x21: // extension
+			if i+2 > len(data) {
+				return nil, errMalformed
+			}
+
+			label := data[i+1]
+			end, err := skipSubBlocks(data, i+2)
+			if err != nil {
+				return nil, err
+			}
+
+			keep := label == 0xF9 || label == 0x01
+			if label == 0xFF && i+3 < len(data) && int(data[i+2]) == 11 && i+14 <= len(data) {
+				keep = gifKeptApplications[string(data[i+3:i+14])]
+			}
+
+			if keep {
+				out.Write(data[start:end])
+			}
+			i = end
+
+		case 0x2C: // image descriptor
+			if i+10 > len(data) {
+				return nil, errMalformed
+			}
+
+			j := i + 10
+			if data[i+9]&0x80 != 0 {
+				j += 3 << (int(data[i+9]&0x07) + 1)
+			}
+			j++ // LZW minimum code size
+			if j > len(data) {
+				return nil, errMalformed
+			}
+
+			end, err := skipSubBlocks(data, j)
+			if err != nil {
+				return nil, err
+			}
+
+			out.Write(data[start:end])
+			i = end
+
+		default:
+			return nil, errMalformed
+		}
+	}
+
+	// Tolerate a missing trailer, as decoders do.
+	out.WriteByte(0x3B)
+	return out.Bytes(), nil
+}
+
+// skipSubBlocks returns the offset after the data sub-blocks starting at i.
+func skipSubBlocks(data []byte, i int) (int, error) {
+	for {
+		if i >= len(data) {
+			return 0, errMalformed
+		}
+
+		size := int(data[i])
+		i++
+		if size == 0 {
+			return i, nil
+		}
+
+		i += size
+	}
+}
@@ -0,0 +1,128 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+)
+
+// fit scales img down so neither side is longer than size. Smaller images are only copied.
+func fit(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	return resize(img, bounds, width, height)
+}
+
+// centreSquare returns the largest square in the middle of bounds.
+func centreSquare(bounds image.Rectangle) image.Rectangle {
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// resize scales the src area of img to width by height. Each output pixel averages the source
+// pixels it covers, which keeps detail when shrinking photos a long way.
+func resize(img image.Image, src image.Rectangle, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	at := pixelReader(img)
+
+	for dy := 0; dy < height; dy++ {
+		y0 := src.Min.Y + dy*src.Dy()/height
+		y1 := max(y0+1, src.Min.Y+(dy+1)*src.Dy()/height)
+
+		for dx := 0; dx < width; dx++ {
+			x0 := src.Min.X + dx*src.Dx()/width
+			x1 := max(x0+1, src.Min.X+(dx+1)*src.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := at(x, y)
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+
+			dst.SetRGBA(dx, dy, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+
+	return dst
+}
+
+// pixelReader returns a function reading the premultiplied 16-bit colour of a pixel, avoiding the
+// per-pixel allocations of image.Image.At for the types decoders produce.
+func pixelReader(img image.Image) func(x, y int) (r, g, b, a uint32) {
+	switch m := img.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.YCbCrAt(x, y).RGBA() }
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.RGBAAt(x, y).RGBA() }
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.NRGBAAt(x, y).RGBA() }
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return m.GrayAt(x, y).RGBA() }
+	case *image.Paletted:
+		palette := make([]color.RGBA64, len(m.Palette))
+		for i, c := range m.Palette {
+			r, g, b, a := c.RGBA()
+			palette[i] = color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+		}
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			i := int(m.ColorIndexAt(x, y))
+			if i >= len(palette) {
+				return 0, 0, 0, 0
+			}
+			return palette[i].RGBA()
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32, uint32) { return img.At(x, y).RGBA() }
+	}
+}
+
+// orient turns img upright according to an EXIF orientation.
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var tx, ty int
+			switch orientation {
+			case 2: // mirrored
+				tx, ty = w-1-x, y
+			case 3: // upside down
+				tx, ty = w-1-x, h-1-y
+			case 4: // mirrored upside down
+				tx, ty = x, h-1-y
+			case 5: // mirrored and turned anticlockwise
+				tx, ty = y, x
+			case 6: // turned anticlockwise
+				tx, ty = h-1-y, x
+			case 7: // mirrored and turned clockwise
+				tx, ty = h-1-y, w-1-x
+			case 8: // turned clockwise
+				tx, ty = y, w-1-x
+			}
+			dst.SetRGBA(tx, ty, img.RGBAAt(x, y))
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,269 @@
+// Package markdown renders the Markdown subset accepted in posts to HTML.
+//
+// The subset is headings, paragraphs, block quotes, bulleted and numbered lists, fenced code blocks,
+// emphasis, strong emphasis, inline code and links. Raw HTML is never passed through: all text is
+// escaped and the only elements produced are the ones listed in the renderer, so the output is safe
+// to show as is. Line breaks inside a paragraph are kept, as people writing posts expect.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxQuoteDepth bounds how deeply block quotes nest before further markers are shown as text.
+const maxQuoteDepth = 3
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,3})\s+(.*?)\s*#*\s*$`)
+	bulletPattern      = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+	quotePattern       = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	fencePattern       = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	continuationPrefix = regexp.MustCompile(`^\s{2,}\S`)
+)
+
+// allowedSchemes are the link schemes rendered as links. Links with any other scheme, or none, are
+// shown as plain text.
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// Render returns the sanitized HTML for source.
+func Render(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(source, "\n"), 0)
+	return b.String()
+}
+
+// renderBlocks renders lines as a sequence of block elements.
+func renderBlocks(b *strings.Builder, lines []string, depth int) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			i = renderFence(b, lines, i)
+
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			// Posts have their own heading, so the levels start below it.
+			tag := [...]string{"h3", "h4", "h5"}[len(m[1])-1]
+			b.WriteString("<" + tag + ">")
+			renderInline(b, m[2], true)
+			b.WriteString("</" + tag + ">\n")
+			i++
+
+		case depth < maxQuoteDepth && quotePattern.MatchString(line):
+			var quoted []string
+			for ; i < len(lines) && quotePattern.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quotePattern.FindStringSubmatch(lines[i])[1])
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted, depth+1)
+			b.WriteString("</blockquote>\n")
+
+		case bulletPattern.MatchString(line):
+			i = renderList(b, lines, i, "ul", bulletPattern)
+
+		case orderedPattern.MatchString(line):
+			i = renderList(b, lines, i, "ol", orderedPattern)
+
+		default:
+			i = renderParagraph(b, lines, i, depth)
+		}
+	}
+}
+
+// renderFence renders the fenced code block starting at lines[start] and returns the index of the
+// line after it. An unclosed fence runs to the end of the text.
+func renderFence(b *strings.Builder, lines []string, start int) int {
+	fence := fencePattern.FindStringSubmatch(lines[start])[1]
+
+	i := start + 1
+	var code []string
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+
+	b.WriteString("<pre><code>")
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderList renders the list starting at lines[start] and returns the index of the line after it.
+// Indented lines continue the item above them.
+func renderList(b *strings.Builder, lines []string, start int, tag string, item *regexp.Regexp) int {
+	b.WriteString("<" + tag + ">\n")
+
+	i := start
+	for i < len(lines) && item.MatchString(lines[i]) {
+		text := []string{item.FindStringSubmatch(lines[i])[1]}
+		for i++; i < len(lines) && continuationPrefix.MatchString(lines[i]) && !item.MatchString(lines[i]); i++ {
+			text = append(text, strings.TrimSpace(lines[i]))
+		}
+
+		b.WriteString("<li>")
+		renderLines(b, text)
+		b.WriteString("</li>\n")
+	}
+
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// renderParagraph renders the paragraph starting at lines[start] and returns the index of the line
+// after it. A paragraph ends at a blank line or the start of another block.
+func renderParagraph(b *strings.Builder, lines []string, start int, depth int) int {
+	i := start + 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || fencePattern.MatchString(line) || headingPattern.MatchString(line) ||
+			bulletPattern.MatchString(line) || orderedPattern.MatchString(line) ||
+			(depth < maxQuoteDepth && quotePattern.MatchString(line)) {
+			break
+		}
+	}
+
+	b.WriteString("<p>")
+	renderLines(b, lines[start:i])
+	b.WriteString("</p>\n")
+	return i
+}
+
+// renderLines renders the inline content of lines separated by line breaks.
+func renderLines(b *strings.Builder, lines []string) {
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("<br>\n")
+		}
+		renderInline(b, strings.TrimSpace(line), true)
+	}
+}
+
+// renderInline renders emphasis, inline code and, when links is set, links in text.
+func renderInline(b *strings.Builder, text string, links bool) {
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && isPunct(text[i+1]):
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end > 0 {
+				b.WriteString("<code>")
+				b.WriteString(html.EscapeString(text[i+1 : i+1+end]))
+				b.WriteString("</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '*' || (c == '_' && (i == 0 || !isWordByte(text[i-1]))):
+			if strings.HasPrefix(text[i:], strings.Repeat(string(c), 2)) {
+				if inner, n, ok := delimited(text[i:], text[i:i+2]); ok {
+					b.WriteString("<strong>")
+					renderInline(b, inner, links)
+					b.WriteString("</strong>")
+					i += n
+					continue
+				}
+			}
+			if inner, n, ok := delimited(text[i:], text[i:i+1]); ok {
+				b.WriteString("<em>")
+				renderInline(b, inner, links)
+				b.WriteString("</em>")
+				i += n
+				continue
+			}
+
+		case c == '[' && links:
+			if label, href, n, ok := link(text[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(href) + `" target="_blank" rel="noopener noreferrer nofollow">`)
+				renderInline(b, label, false)
+				b.WriteString("</a>")
+				i += n
+				continue
+			}
+		}
+
+		// Copy up to the next character that could start markup.
+		end := i + 1
+		for end < len(text) && !strings.ContainsRune("\\`*_[", rune(text[end])) {
+			end++
+		}
+		b.WriteString(html.EscapeString(text[i:end]))
+		i = end
+	}
+}
+
+// delimited finds the text wrapped by delim at the start of text and returns it with the length of
+// text consumed. The wrapped text may not start or end with a space.
+func delimited(text, delim string) (string, int, bool) {
+	rest := text[len(delim):]
+	end := strings.Index(rest, delim)
+	if end <= 0 {
+		return "", 0, false
+	}
+
+	inner := rest[:end]
+	if strings.TrimSpace(inner) != inner {
+		return "", 0, false
+	}
+
+	return inner, len(delim) + end + len(delim), true
+}
+
+// link parses a [label](href) link at the start of text and returns its parts with the length of
+// text consumed. Only absolute links with an allowed scheme are accepted.
+func link(text string) (string, string, int, bool) {
+	closeLabel := strings.IndexByte(text, ']')
+	if closeLabel <= 1 || !strings.HasPrefix(text[closeLabel+1:], "(") {
+		return "", "", 0, false
+	}
+
+	rest := text[closeLabel+2:]
+	closeHref := strings.IndexByte(rest, ')')
+	if closeHref <= 0 {
+		return "", "", 0, false
+	}
+
+	href := strings.TrimSpace(rest[:closeHref])
+	if strings.ContainsAny(href, " \t") {
+		return "", "", 0, false
+	}
+
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", "", 0, false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !allowedSchemes[scheme] || (scheme != "mailto" && u.Host == "") {
+		return "", "", 0, false
+	}
+
+	return text[1:closeLabel], u.String(), closeLabel + 2 + closeHref + 1, true
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("\\`*_{}[]()#+-.!>~|", c) >= 0
+}
+
+// isWordByte reports whether c is part of a word, inside which underscores are not emphasis.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
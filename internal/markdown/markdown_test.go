@@ -0,0 +1,132 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const linkAttrs = `target="_blank" rel="noopener noreferrer nofollow"`
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "https link",
+			source: "[site](https://example.com/a?b=c)",
+			want:   `<p><a href="https://example.com/a?b=c" ` + linkAttrs + ">site</a></p>\n",
+		},
+		{
+			name:   "mailto link",
+			source: "[write](mailto:pastor@example.com)",
+			want:   `<p><a href="mailto:pastor@example.com" ` + linkAttrs + ">write</a></p>\n",
+		},
+		{
+			name:   "javascript link",
+			source: "[click](javascript:alert(1))",
+			want:   "<p>[click](javascript:alert(1))</p>\n",
+		},
+		{
+			name:   "mixed case javascript link",
+			source: "[click](JaVaScRiPt:alert(1))",
+			want:   "<p>[click](JaVaScRiPt:alert(1))</p>\n",
+		},
+		{
+			name:   "data link",
+			source: "[click](data:text/html;base64,PHNjcmlwdD4=)",
+			want:   "<p>[click](data:text/html;base64,PHNjcmlwdD4=)</p>\n",
+		},
+		{
+			name:   "scheme-less link",
+			source: "[click](example.com)",
+			want:   "<p>[click](example.com)</p>\n",
+		},
+		{
+			name:   "protocol-relative link",
+			source: "[click](//example.com)",
+			want:   "<p>[click](//example.com)</p>\n",
+		},
+		{
+			name:   "raw script",
+			source: "<script>alert(1)</script>",
+			want:   "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n",
+		},
+		{
+			name:   "quotes in text",
+			source: `a "b" 'c' & d`,
+			want:   "<p>a &#34;b&#34; &#39;c&#39; &amp; d</p>\n",
+		},
+		{
+			name:   "quotes and markup in link label and href",
+			source: `[a "b" <i>](https://example.com/?q="x"&y=<z>)`,
+			want:   `<p><a href="https://example.com/?q=&#34;x&#34;&amp;y=&lt;z&gt;" ` + linkAttrs + ">a &#34;b&#34; &lt;i&gt;</a></p>\n",
+		},
+		{
+			name:   "script in inline code",
+			source: "`<script>`",
+			want:   "<p><code>&lt;script&gt;</code></p>\n",
+		},
+		{
+			name:   "closed fence",
+			source: "```\n<b>bold</b>\n```\nafter",
+			want:   "<pre><code>&lt;b&gt;bold&lt;/b&gt;</code></pre>\n<p>after</p>\n",
+		},
+		{
+			name:   "unclosed fence",
+			source: "```\n<b>bold\n\n# not a heading",
+			want:   "<pre><code>&lt;b&gt;bold\n\n# not a heading</code></pre>\n",
+		},
+		{
+			name:   "nested emphasis",
+			source: "_a **b** c_",
+			want:   "<p><em>a <strong>b</strong> c</em></p>\n",
+		},
+		{
+			name:   "link inside a link label",
+			source: "[[inner](https://a.example)](https://b.example)",
+			want:   `<p><a href="https://a.example" ` + linkAttrs + ">[inner</a>](https://b.example)</p>\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Render(test.source); got != test.want {
+				t.Errorf("Render(%q) = %q, want %q", test.source, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderLinkRel(t *testing.T) {
+	source := "[a](https://a.example) and **[b](http://b.example)**\n\n> - [c](mailto:c@example.com)"
+	got := Render(source)
+
+	links := regexp.MustCompile(`<a [^>]*>`).FindAllString(got, -1)
+	if len(links) != 3 {
+		t.Fatalf("Render(%q) = %q, want 3 links", source, got)
+	}
+
+	for _, link := range links {
+		if !strings.HasSuffix(link, ` `+linkAttrs+`>`) {
+			t.Errorf("link %q does not open in a new tab without referrer or follow", link)
+		}
+	}
+}
+
+func TestRenderDeeplyNestedEmphasis(t *testing.T) {
+	const depth = 5000
+	source := strings.Repeat("*_", depth) + "x" + strings.Repeat("_*", depth)
+
+	got := Render(source)
+
+	if opened, closed := strings.Count(got, "<em>"), strings.Count(got, "</em>"); opened != closed || opened == 0 {
+		t.Errorf("Render of nested emphasis opened %d and closed %d <em> elements", opened, closed)
+	}
+
+	if !strings.Contains(got, ">x<") {
+		t.Errorf("Render of nested emphasis lost the text")
+	}
+}
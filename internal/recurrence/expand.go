@@ -0,0 +1,356 @@
+package recurrence
+
+import (
+	"slices"
+	"time"
+)
+
+// maxPeriods bounds how many days, weeks, months or years are searched for occurrences, so that rules
+// which rarely or never match cannot loop for long. Searches for later occurrences of series without
+// a COUNT start at the period holding them, so the bound is counted from there.
+const maxPeriods = 10000
+
+// Occurrences returns the starts of up to limit occurrences of a series beginning at start that fall
+// within [from, to), in order. Occurrences keep start's wall clock time in its location across
+// daylight saving changes. As in RFC 5545, start is always the first occurrence.
+func (r *Rule) Occurrences(start, from, to time.Time, limit int) []time.Time {
+	occurrences := make([]time.Time, 0)
+
+	r.each(start, from, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+
+		return len(occurrences) < limit
+	})
+
+	return occurrences
+}
+
+// Occurs reports whether a series beginning at start has an occurrence starting at t.
+func (r *Rule) Occurs(start, t time.Time) bool {
+	found := false
+
+	r.each(start, t, func(occurrence time.Time) bool {
+		found = occurrence.Equal(t)
+		return occurrence.Before(t)
+	})
+
+	return found
+}
+
+// Last returns the start of the last occurrence of a series beginning at start. It reports false for
+// series without an end, and for series with a COUNT that is not reached within maxPeriods.
+func (r *Rule) Last(start time.Time) (time.Time, bool) {
+	if !r.Until.IsZero() {
+		return r.lastUntil(start)
+	}
+
+	if r.Count == 0 {
+		return time.Time{}, false
+	}
+
+	last, count := start, 0
+	r.each(start, start, func(t time.Time) bool {
+		last = t
+		count++
+		return true
+	})
+
+	return last, count == r.Count
+}
+
+// lastUntil searches back from the period holding UNTIL for the last occurrence, so series that end
+// far in the future are not cut short by maxPeriods. It reports false when maxPeriods pass without
+// finding one before the start of the series is reached.
+func (r *Rule) lastUntil(start time.Time) (time.Time, bool) {
+	loc := start.Location()
+	hour, minute, second := start.Clock()
+
+	end := r.periodsBefore(start, r.Until) + 1
+	for period := end; period >= 0 && period > end-maxPeriods; period-- {
+		_, dates := r.period(start, period)
+		for i := len(dates) - 1; i >= 0; i-- {
+			t := time.Date(dates[i].Year(), dates[i].Month(), dates[i].Day(), hour, minute, second, start.Nanosecond(), loc)
+			if t.After(start) && !t.After(r.Until) {
+				return t, true
+			}
+		}
+	}
+
+	if end >= maxPeriods {
+		return time.Time{}, false
+	}
+
+	return start, true
+}
+
+// each calls yield with each occurrence in order until it returns false or the series ends. Only
+// occurrences from the start of the series and those at or after from are certain to be yielded:
+// unless the rule has a COUNT, which needs every occurrence counted, the periods that end before
+// from are skipped.
+func (r *Rule) each(start, from time.Time, yield func(time.Time) bool) {
+	count := 0
+	emit := func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+
+		count++
+		return yield(t) && (r.Count == 0 || count < r.Count)
+	}
+
+	if !emit(start) {
+		return
+	}
+
+	loc := start.Location()
+	hour, minute, second := start.Clock()
+
+	skipped := 0
+	if r.Count == 0 {
+		skipped = r.periodsBefore(start, from)
+	}
+
+	for period := skipped; period < skipped+maxPeriods; period++ {
+		first, dates := r.period(start, period)
+		if !r.Until.IsZero() && time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc).After(r.Until) {
+			return
+		}
+
+		for _, date := range dates {
+			t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, start.Nanosecond(), loc)
+			if !t.After(start) {
+				continue
+			}
+
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// periodsBefore returns how many whole periods of the series beginning at start pass before the day
+// of from, none of which can hold an occurrence at or after from.
+func (r *Rule) periodsBefore(start, from time.Time) int {
+	day, target := civil(start), civil(from.In(start.Location()))
+	if !target.After(day) {
+		return 0
+	}
+
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = int(target.Sub(day).Hours()/24) / r.Interval
+
+	case Weekly:
+		offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+		periods = int(target.Sub(day.AddDate(0, 0, -offset)).Hours()/24) / 7 / r.Interval
+
+	case Monthly:
+		periods = ((target.Year()-day.Year())*12 + int(target.Month()) - int(day.Month())) / r.Interval
+
+	case Yearly:
+		periods = (target.Year() - day.Year()) / r.Interval
+	}
+
+	return periods
+}
+
+// period returns the first day of the nth period of the series, counting in steps of the interval,
+// and the dates of the occurrences within it in order. Dates are midnight UTC, so that date arithmetic
+// is not upset by daylight saving.
+func (r *Rule) period(start time.Time, n int) (time.Time, []time.Time) {
+	day := civil(start)
+	step := n * r.Interval
+
+	var first time.Time
+	var dates []time.Time
+
+	switch r.Freq {
+	case Daily:
+		first = day.AddDate(0, 0, step)
+		if r.matchesMonth(first) && r.matchesMonthDay(first) && r.matchesWeekday(first) {
+			dates = []time.Time{first}
+		}
+
+	case Weekly:
+		offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+		first = day.AddDate(0, 0, step*7-offset)
+
+		weekdays := []time.Weekday{day.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, wd := range r.ByDay {
+				weekdays = append(weekdays, wd.Day)
+			}
+		}
+
+		for _, weekday := range weekdays {
+			date := first.AddDate(0, 0, (int(weekday)-int(r.WeekStart)+7)%7)
+			if r.matchesMonth(date) {
+				dates = append(dates, date)
+			}
+		}
+
+	case Monthly:
+		first = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, step, 0)
+		if r.matchesMonth(first) {
+			dates = r.monthDates(first, day.Day())
+		}
+
+	case Yearly:
+		first = time.Date(day.Year()+step, time.January, 1, 0, 0, 0, 0, time.UTC)
+		dates = r.yearDates(first, day)
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	dates = slices.CompactFunc(dates, func(a, b time.Time) bool { return a.Equal(b) })
+
+	return first, r.setPositions(dates)
+}
+
+// monthDates returns the dates in the month starting at first that the BYMONTHDAY and BYDAY parts
+// select, or the given day of the month when there are neither.
+func (r *Rule) monthDates(first time.Time, defaultDay int) []time.Time {
+	days := daysIn(first)
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if defaultDay > days {
+			return nil
+		}
+
+		return []time.Time{first.AddDate(0, 0, defaultDay-1)}
+	}
+
+	var dates []time.Time
+	for d := 1; d <= days; d++ {
+		date := first.AddDate(0, 0, d-1)
+		if r.matchesMonthDay(date) && r.matchesWeekdayIn(date, first, days) {
+			dates = append(dates, date)
+		}
+	}
+
+	return dates
+}
+
+// yearDates returns the dates in the year starting at first that the rule selects. With BYMONTH,
+// numbered weekdays count within each month, otherwise within the year.
+func (r *Rule) yearDates(first, start time.Time) []time.Time {
+	if len(r.ByMonth) > 0 {
+		var dates []time.Time
+		for _, month := range r.ByMonth {
+			dates = append(dates, r.monthDates(time.Date(first.Year(), month, 1, 0, 0, 0, 0, time.UTC), start.Day())...)
+		}
+
+		return dates
+	}
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		date := time.Date(first.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Month() != start.Month() {
+			return nil // 29 February outside leap years
+		}
+
+		return []time.Time{date}
+	}
+
+	days := first.AddDate(1, 0, -1).YearDay()
+
+	var dates []time.Time
+	for d := 1; d <= days; d++ {
+		date := first.AddDate(0, 0, d-1)
+		if r.matchesMonthDay(date) && r.matchesWeekdayIn(date, first, days) {
+			dates = append(dates, date)
+		}
+	}
+
+	return dates
+}
+
+// setPositions applies BYSETPOS to the ordered dates of a period.
+func (r *Rule) setPositions(dates []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return dates
+	}
+
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(dates) + pos
+		}
+
+		if i >= 0 && i < len(dates) {
+			selected = append(selected, dates[i])
+		}
+	}
+
+	slices.SortFunc(selected, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(selected, func(a, b time.Time) bool { return a.Equal(b) })
+}
+
+func (r *Rule) matchesMonth(date time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, date.Month())
+}
+
+func (r *Rule) matchesMonthDay(date time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	days := daysIn(date)
+	for _, md := range r.ByMonthDay {
+		if md == date.Day() || (md < 0 && days+md+1 == date.Day()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesWeekday checks BYDAY for rules where it only lists weekdays.
+func (r *Rule) matchesWeekday(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(r.ByDay, func(wd WeekdayNum) bool { return wd.Day == date.Weekday() })
+}
+
+// matchesWeekdayIn checks BYDAY for a date within the span of days starting at first, which numbered
+// weekdays count within.
+func (r *Rule) matchesWeekdayIn(date, first time.Time, days int) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	index := int(date.Sub(first).Hours()/24) + 1
+	for _, wd := range r.ByDay {
+		if wd.Day != date.Weekday() {
+			continue
+		}
+
+		if wd.N == 0 || (wd.N > 0 && (index-1)/7+1 == wd.N) || (wd.N < 0 && (days-index)/7+1 == -wd.N) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// civil returns the calendar date of t as midnight UTC.
+func civil(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysIn returns the number of days in the month of date.
+func daysIn(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
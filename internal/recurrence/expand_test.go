@@ -0,0 +1,215 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s is not available: %v", name, err)
+	}
+
+	return loc
+}
+
+// localTimes parses times written as "2006-01-02 15:04" in loc.
+func localTimes(t *testing.T, loc *time.Location, values ...string) []time.Time {
+	t.Helper()
+
+	times := make([]time.Time, len(values))
+	for i, value := range values {
+		var err error
+		if times[i], err = time.ParseInLocation("2006-01-02 15:04", value, loc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return times
+}
+
+func TestOccurrences(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name  string
+		rule  string
+		loc   *time.Location
+		start string
+		want  []string
+	}{
+		{
+			// Daylight saving starts on 8 March 2026 in New York; the wall clock time stays the same.
+			name:  "weekly across DST",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			loc:   newYork,
+			start: "2026-03-01 10:00",
+			want:  []string{"2026-03-01 10:00", "2026-03-08 10:00", "2026-03-15 10:00"},
+		},
+		{
+			name:  "daily across the end of DST",
+			rule:  "FREQ=DAILY;COUNT=3",
+			loc:   newYork,
+			start: "2026-10-31 01:30",
+			want:  []string{"2026-10-31 01:30", "2026-11-01 01:30", "2026-11-02 01:30"},
+		},
+		{
+			name:  "last Friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=4",
+			loc:   time.UTC,
+			start: "2026-01-30 19:00",
+			want:  []string{"2026-01-30 19:00", "2026-02-27 19:00", "2026-03-27 19:00", "2026-04-24 19:00"},
+		},
+		{
+			name:  "last weekday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=4",
+			loc:   time.UTC,
+			start: "2026-01-30 17:00",
+			want:  []string{"2026-01-30 17:00", "2026-02-27 17:00", "2026-03-31 17:00", "2026-04-30 17:00"},
+		},
+		{
+			// The RFC 5545 example: the week start decides which weeks the interval skips.
+			name:  "interval with weeks starting on Monday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			loc:   time.UTC,
+			start: "1997-08-05 09:00",
+			want:  []string{"1997-08-05 09:00", "1997-08-10 09:00", "1997-08-19 09:00", "1997-08-24 09:00"},
+		},
+		{
+			name:  "interval with weeks starting on Sunday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			loc:   time.UTC,
+			start: "1997-08-05 09:00",
+			want:  []string{"1997-08-05 09:00", "1997-08-17 09:00", "1997-08-19 09:00", "1997-08-31 09:00"},
+		},
+		{
+			name:  "31st skips shorter months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4",
+			loc:   time.UTC,
+			start: "2026-01-31 08:00",
+			want:  []string{"2026-01-31 08:00", "2026-03-31 08:00", "2026-05-31 08:00", "2026-07-31 08:00"},
+		},
+		{
+			name:  "monthly from the 31st skips shorter months",
+			rule:  "FREQ=MONTHLY;COUNT=3",
+			loc:   time.UTC,
+			start: "2026-01-31 08:00",
+			want:  []string{"2026-01-31 08:00", "2026-03-31 08:00", "2026-05-31 08:00"},
+		},
+		{
+			name:  "yearly on 29 February",
+			rule:  "FREQ=YEARLY;COUNT=3",
+			loc:   time.UTC,
+			start: "2024-02-29 12:00",
+			want:  []string{"2024-02-29 12:00", "2028-02-29 12:00", "2032-02-29 12:00"},
+		},
+		{
+			name:  "until a time includes an occurrence at it",
+			rule:  "FREQ=DAILY;UNTIL=20260304T090000Z",
+			loc:   time.UTC,
+			start: "2026-03-01 09:00",
+			want:  []string{"2026-03-01 09:00", "2026-03-02 09:00", "2026-03-03 09:00", "2026-03-04 09:00"},
+		},
+		{
+			name:  "until a date includes the whole day",
+			rule:  "FREQ=WEEKLY;UNTIL=20260315",
+			loc:   newYork,
+			start: "2026-03-01 18:00",
+			want:  []string{"2026-03-01 18:00", "2026-03-08 18:00", "2026-03-15 18:00"},
+		},
+		{
+			name:  "until before the time of day",
+			rule:  "FREQ=DAILY;UNTIL=20260303T080000Z",
+			loc:   time.UTC,
+			start: "2026-03-01 09:00",
+			want:  []string{"2026-03-01 09:00", "2026-03-02 09:00"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.rule, test.loc)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", test.rule, err)
+			}
+
+			start := localTimes(t, test.loc, test.start)[0]
+			want := localTimes(t, test.loc, test.want...)
+
+			got := rule.Occurrences(start, start, start.AddDate(50, 0, 0), 100)
+			if len(got) != len(want) {
+				t.Fatalf("Occurrences = %v, want %v", got, want)
+			}
+
+			for i := range want {
+				if !got[i].Equal(want[i]) || got[i].Location() != test.loc {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrencesFrom(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYDAY=-1FR", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := localTimes(t, time.UTC, "2020-01-31 19:00")[0]
+	from, to := localTimes(t, time.UTC, "2026-03-01 00:00")[0], localTimes(t, time.UTC, "2026-06-01 00:00")[0]
+	want := localTimes(t, time.UTC, "2026-03-27 19:00", "2026-04-24 19:00", "2026-05-29 19:00")
+
+	got := rule.Occurrences(start, from, to, 10)
+	if len(got) != len(want) {
+		t.Fatalf("Occurrences = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLast(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		start  string
+		want   string
+		wantOK bool
+	}{
+		{name: "count", rule: "FREQ=WEEKLY;COUNT=3", start: "2026-03-01 10:00", want: "2026-03-15 10:00", wantOK: true},
+		{name: "until", rule: "FREQ=DAILY;UNTIL=20260304T090000Z", start: "2026-03-01 09:00", want: "2026-03-04 09:00", wantOK: true},
+		{name: "until between occurrences", rule: "FREQ=WEEKLY;UNTIL=20260318", start: "2026-03-01 10:00", want: "2026-03-15 10:00", wantOK: true},
+		{name: "until before the second occurrence", rule: "FREQ=MONTHLY;UNTIL=20260320", start: "2026-03-01 10:00", want: "2026-03-01 10:00", wantOK: true},
+		{name: "daily until far beyond maxPeriods", rule: "FREQ=DAILY;UNTIL=20991231T235959Z", start: "2026-03-01 10:00", want: "2099-12-31 10:00", wantOK: true},
+		{name: "sparse until far beyond maxPeriods", rule: "FREQ=DAILY;BYMONTH=2;BYMONTHDAY=29;UNTIL=21991231T235959Z", start: "2024-02-29 10:00", want: "2196-02-29 10:00", wantOK: true},
+		{name: "count beyond maxPeriods", rule: "FREQ=DAILY;BYDAY=MO;COUNT=10000", start: "2026-03-02 10:00", wantOK: false},
+		{name: "no end", rule: "FREQ=DAILY", start: "2026-03-01 10:00", wantOK: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.rule, time.UTC)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", test.rule, err)
+			}
+
+			start := localTimes(t, time.UTC, test.start)[0]
+
+			got, ok := rule.Last(start)
+			if ok != test.wantOK {
+				t.Fatalf("Last = %v, %t, want ok %t", got, ok, test.wantOK)
+			}
+
+			if ok && !got.Equal(localTimes(t, time.UTC, test.want)[0]) {
+				t.Errorf("Last = %v, want %s", got, test.want)
+			}
+		})
+	}
+}
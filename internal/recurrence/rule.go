@@ -0,0 +1,281 @@
+// Package recurrence parses RFC 5545 recurrence rules (RRULE) and expands them into the occurrences
+// of events.
+//
+// Rules repeat daily, weekly, monthly or yearly and can be narrowed with BYDAY, BYMONTHDAY, BYMONTH
+// and BYSETPOS, which covers series such as "every Sunday", "the first Friday of each month" or
+// "the last weekday of the month". Rules that repeat within a day (BYHOUR and finer) and BYWEEKNO and
+// BYYEARDAY are not supported.
+package recurrence
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY value such as MO, 1FR or -1SU.
+type WeekdayNum struct {
+	// N picks one occurrence of the weekday within the month or year, counting back from the end when
+	// negative. Zero means every occurrence.
+	N   int
+	Day time.Weekday
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	// Count limits the series to this many occurrences, or is 0 for no limit.
+	Count int
+	// Until is the latest an occurrence can start, or zero for no limit.
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse parses the value of an RRULE, with or without the "RRULE:" prefix. A date or local time in
+// UNTIL is read in loc.
+func Parse(text string, loc *time.Location) (*Rule, error) {
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	text = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(text)), "RRULE:")
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(text, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, invalid("malformed part %q", part)
+		}
+
+		if seen[key] {
+			return nil, invalid("%s is repeated", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+			if !slices.Contains([]Frequency{Daily, Weekly, Monthly, Yearly}, rule.Freq) {
+				return nil, invalid("unsupported frequency %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseInt(value, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseInt(value, 1, 10000)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value, loc)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(value, 1, 31, true)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(value, 1, 12, false)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "BYSETPOS":
+			rule.BySetPos, err = parseInts(value, 1, 366, true)
+		case "WKST":
+			day, ok := weekdays[value]
+			if !ok {
+				return nil, invalid("unknown weekday %s", value)
+			}
+			rule.WeekStart = day
+		default:
+			return nil, invalid("%s is not supported", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", domain.ErrInvalidRecurrence, key, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, invalid("FREQ is required")
+	}
+
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, invalid("COUNT and UNTIL cannot both be set")
+	}
+
+	if len(rule.ByMonthDay) > 0 && rule.Freq == Weekly {
+		return nil, invalid("BYMONTHDAY cannot be used with weekly rules")
+	}
+
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, invalid("numbered BYDAY values need a monthly or yearly rule")
+		}
+
+		if day.N != 0 && rule.Freq == Monthly && (day.N > 5 || day.N < -5) {
+			return nil, invalid("a month has at most 5 of each weekday")
+		}
+	}
+
+	if len(rule.BySetPos) > 0 && len(rule.ByDay)+len(rule.ByMonthDay)+len(rule.ByMonth) == 0 {
+		return nil, invalid("BYSETPOS needs another BY part")
+	}
+
+	return rule, nil
+}
+
+// String returns the rule as the value of an RRULE, without the "RRULE:" prefix.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	if len(r.ByMonth) > 0 {
+		months := make([]int, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			months[i] = int(month)
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayNames[day.Day]
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", domain.ErrInvalidRecurrence, fmt.Sprintf(format, args...))
+}
+
+func parseInt(value string, low, high int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+
+	if n < low || n > high {
+		return 0, fmt.Errorf("%d is out of range", n)
+	}
+
+	return n, nil
+}
+
+// parseInts parses a list of numbers between low and high, or between -high and -low as well when
+// negative is set.
+func parseInts(value string, low, high int, negative bool) ([]int, error) {
+	var values []int
+
+	for _, item := range strings.Split(value, ",") {
+		n, err := parseInt(item, -high, high)
+		if err != nil {
+			return nil, err
+		}
+
+		if (n < 0 && !negative) || (n >= 0 && n < low) || (n < 0 && -n < low) {
+			return nil, fmt.Errorf("%d is out of range", n)
+		}
+
+		values = append(values, n)
+	}
+
+	return values, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("unknown weekday %q", item)
+		}
+
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", item)
+		}
+
+		n := 0
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			var err error
+			if n, err = parseInt(ordinal, -53, 53); err != nil || n == 0 {
+				return nil, fmt.Errorf("bad weekday number in %q", item)
+			}
+		}
+
+		days = append(days, WeekdayNum{N: n, Day: day})
+	}
+
+	return days, nil
+}
+
+// parseUntil reads a UTC time, a local time or a date, which includes the whole day.
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a date or time", value)
+}
+
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, n := range values {
+		items[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/config"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/imaging"
+	"github.com/google/uuid"
+)
+
+// attachmentTokenAudience marks tokens that grant access to one attachment, so they can never be
+// mistaken for anything else signed by TokensService.
+const attachmentTokenAudience = "attachment"
+
+// attachmentTypes are the content types accepted for upload, as sniffed from the content itself,
+// and whether they are images to process. Only images the image pipeline can clean are accepted.
+var attachmentTypes = map[string]bool{
+	"application/pdf": false,
+	"image/gif":       true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// attachmentUpload is the blob holding an image as uploaded until it has been processed.
+const attachmentUpload domain.AttachmentVariant = "upload"
+
+// attachmentBlobs are all the blobs an attachment can have.
+var attachmentBlobs = []domain.AttachmentVariant{domain.AttachmentOriginal, domain.AttachmentWeb, domain.AttachmentThumbnail, attachmentUpload}
+
+const (
+	// unattachedAttachmentExpiry is how long an upload can wait to be attached to a post before it
+	// is deleted.
+	unattachedAttachmentExpiry = 24 * time.Hour
+	// unattachedAttachmentBatchSize bounds how many unattached uploads are deleted in one pass.
+	unattachedAttachmentBatchSize = 100
+)
+
+// NewAttachmentService creates an attachment service that keeps uploads in blobStore, up to the
+// storage quota of each fellowship. Members upload where feedService lets them post and download the
+// attachments of posts it lets them see.
+func NewAttachmentService(store domain.AttachmentStore, blobStore domain.BlobStore, circleStore domain.CircleStore, tokensService *TokensService, feedService *FeedService, images *ImageProcessor, serverConfig config.ServerConfig, storageConfig config.StorageConfig, logger *slog.Logger) *AttachmentService {
+	return &AttachmentService{attachmentStore: store, blobStore: blobStore, circleStore: circleStore, tokensService: tokensService, feedService: feedService, images: images, serverConfig: serverConfig, quota: storageConfig.GetFellowshipStorageQuota(), logger: logger}
+}
+
+type AttachmentService struct {
+	attachmentStore domain.AttachmentStore
+	blobStore       domain.BlobStore
+	circleStore     domain.CircleStore
+	tokensService   *TokensService
+	feedService     *FeedService
+	images          *ImageProcessor
+	serverConfig    config.ServerConfig
+	quota           int64
+	logger          *slog.Logger
+}
+
+// Upload stores size bytes of content as a new attachment in a fellowship or circle the user can post to.
+// The content type is decided by the content, not by what the client claims. Images are processed in
+// the background and cannot be downloaded until they are ready.
+func (a *AttachmentService) Upload(ctx context.Context, user domain.User, fellowshipId, circleId uuid.UUID, name string, size int64, content io.Reader) (*domain.Attachment, error) {
+	if (fellowshipId == uuid.Nil) == (circleId == uuid.Nil) {
+		return nil, fmt.Errorf("%w: must target either a fellowship or a circle", domain.ErrInvalidAttachment)
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: empty file", domain.ErrInvalidAttachment)
+	} else if size > domain.AttachmentMaxSize {
+		return nil, domain.ErrAttachmentTooLarge
+	}
+
+	attachment := domain.Attachment{FellowshipId: fellowshipId, CircleId: circleId, UploaderId: user.Id, Name: attachmentName(name), Size: size}
+
+	accessLevel, err := a.feedService.postAccessLevel(ctx, user, attachmentTarget(attachment))
+	if err != nil {
+		return nil, err
+	} else if !canPost(accessLevel) {
+		return nil, fmt.Errorf("user %s cannot upload attachments here: %w", user.Id, domain.ErrInsufficientAccess)
+	}
+
+	if circleId != uuid.Nil {
+		circle, err := a.circleStore.GetCircle(ctx, circleId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get circle %s: %w", circleId, err)
+		}
+
+		attachment.FellowshipId = circle.FellowshipId
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	isImage, ok := attachmentTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedAttachment, contentType)
+	}
+
+	attachment.Id, err = uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate attachment ID: %v", err)
+	}
+
+	attachment.ContentType = contentType
+	attachment.Uploaded = time.Now()
+	attachment.Status = domain.AttachmentReady
+	key := attachmentKey(attachment.Id, domain.AttachmentOriginal)
+	if isImage {
+		attachment.Status = domain.AttachmentProcessing
+		key = attachmentKey(attachment.Id, attachmentUpload)
+	}
+
+	// Reserve the space before storing anything so uploads over quota are turned away early.
+	if err := a.attachmentStore.CreateAttachment(ctx, attachment, a.quota); errors.Is(err, domain.ErrStorageQuotaExceeded) {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	if err := a.blobStore.Put(ctx, key, io.MultiReader(bytes.NewReader(head), content), size, contentType); err != nil {
+		if err := a.attachmentStore.DeleteAttachment(context.WithoutCancel(ctx), attachment.Id); err != nil {
+			a.logger.Error("attachments: failed to release failed upload", "attachmentId", attachment.Id, "error", err)
+		}
+
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	if isImage {
+		a.images.Enqueue(attachment.Id)
+	}
+
+	return &attachment, nil
+}
+
+// URL returns a short-lived download link for an attachment. Uploaders can always download their
+// attachments; anyone else needs to be able to see the post carrying it.
+func (a *AttachmentService) URL(ctx context.Context, user domain.User, attachmentId uuid.UUID) (*domain.AttachmentURL, error) {
+	attachment, err := a.getAttachment(ctx, attachmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if attachment.UploaderId != user.Id {
+		if attachment.PostId == nil {
+			return nil, domain.ErrAttachmentNotFound
+		}
+
+		post, err := a.feedService.getPost(ctx, user, *attachment.PostId)
+		if errors.Is(err, domain.ErrPostNotFound) {
+			return nil, domain.ErrAttachmentNotFound
+		} else if err != nil {
+			return nil, err
+		}
+
+		if err := a.feedService.checkCanView(ctx, user, *post); err != nil {
+			return nil, err
+		}
+	}
+
+	expires := time.Now().Add(domain.AttachmentURLExpiry)
+	token, err := a.tokensService.SignJWT(ctx, map[string]any{
+		"iat": time.Now().Unix(),
+		"exp": expires.Unix(),
+		"aud": attachmentTokenAudience,
+		"sub": attachment.Id.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign attachment token: %w", err)
+	}
+
+	link := "https://" + a.serverConfig.GetDomain() + "/api/attachments/" + attachment.Id.String() + "?token=" + url.QueryEscape(token)
+	return &domain.AttachmentURL{URL: link, Expires: expires}, nil
+}
+
+// Open returns an attachment and the content of one of its versions for a download link made by URL.
+// The returned attachment describes the version opened; the size of web versions and thumbnails is
+// not recorded and left zero. The caller closes the content.
+func (a *AttachmentService) Open(ctx context.Context, attachmentId uuid.UUID, token string, variant domain.AttachmentVariant) (*domain.Attachment, io.ReadCloser, error) {
+	data, err := a.tokensService.VerifyJWT(token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidToken, err)
+	}
+
+	if aud, _ := data["aud"].(string); aud != attachmentTokenAudience {
+		return nil, nil, domain.ErrInvalidToken
+	} else if sub, _ := data["sub"].(string); sub != attachmentId.String() {
+		return nil, nil, domain.ErrInvalidToken
+	}
+
+	attachment, err := a.getAttachment(ctx, attachmentId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch attachment.Status {
+	case domain.AttachmentProcessing:
+		return nil, nil, domain.ErrAttachmentNotReady
+	case domain.AttachmentFailed:
+		return nil, nil, domain.ErrAttachmentNotFound
+	}
+
+	if variant != domain.AttachmentOriginal {
+		if variant != domain.AttachmentWeb && variant != domain.AttachmentThumbnail || !attachmentTypes[attachment.ContentType] {
+			return nil, nil, fmt.Errorf("%w: no %s version", domain.ErrAttachmentNotFound, variant)
+		}
+
+		attachment.ContentType = imaging.VariantType(attachment.ContentType)
+		attachment.Size = 0
+	}
+
+	content, err := a.blobStore.Get(ctx, attachmentKey(attachment.Id, variant))
+	if errors.Is(err, domain.ErrBlobNotFound) {
+		return nil, nil, domain.ErrAttachmentNotFound
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment %s: %w", attachmentId, err)
+	}
+
+	return attachment, content, nil
+}
+
+// Delete removes an attachment and frees its storage. Uploaders can delete their own attachments
+// and moderators any attachment in their fellowship or circle.
+func (a *AttachmentService) Delete(ctx context.Context, user domain.User, attachmentId uuid.UUID) error {
+	attachment, err := a.getAttachment(ctx, attachmentId)
+	if err != nil {
+		return err
+	}
+
+	if attachment.UploaderId != user.Id {
+		if err := a.feedService.checkModerator(ctx, user, attachmentTarget(*attachment)); err != nil {
+			return err
+		}
+	}
+
+	if err := a.attachmentStore.DeleteAttachment(ctx, attachmentId); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAttachmentNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete attachment %s: %w", attachmentId, err)
+	}
+
+	a.deleteBlobs(ctx, attachmentId)
+	return nil
+}
+
+// Run deletes uploads that were never attached to a post every interval until ctx is cancelled.
+func (a *AttachmentService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if deleted, err := a.DeleteUnattached(ctx); err != nil {
+			a.logger.Error("attachments: failed to delete unattached uploads", "error", err)
+		} else if deleted > 0 {
+			a.logger.Info("attachments: deleted unattached uploads", "count", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeleteUnattached deletes the uploads that have not been attached to a post within
+// unattachedAttachmentExpiry, freeing their storage, and returns how many it deleted.
+func (a *AttachmentService) DeleteUnattached(ctx context.Context) (int, error) {
+	deleted := 0
+
+	for {
+		attachmentIDs, err := a.attachmentStore.DeleteUnattachedAttachments(ctx, time.Now().Add(-unattachedAttachmentExpiry), unattachedAttachmentBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, attachmentId := range attachmentIDs {
+			a.deleteBlobs(ctx, attachmentId)
+		}
+
+		deleted += len(attachmentIDs)
+		if len(attachmentIDs) < unattachedAttachmentBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// deleteBlobs removes every blob of a deleted attachment. The attachment is gone once its record
+// is; a blob left behind only costs space.
+func (a *AttachmentService) deleteBlobs(ctx context.Context, attachmentId uuid.UUID) {
+	for _, variant := range attachmentBlobs {
+		if err := a.blobStore.Delete(ctx, attachmentKey(attachmentId, variant)); err != nil {
+			a.logger.Error("attachments: failed to delete blob", "attachmentId", attachmentId, "variant", variant, "error", err)
+		}
+	}
+}
+
+func (a *AttachmentService) getAttachment(ctx context.Context, attachmentId uuid.UUID) (*domain.Attachment, error) {
+	attachment, err := a.attachmentStore.GetAttachment(ctx, attachmentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAttachmentNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get attachment %s: %w", attachmentId, err)
+	}
+
+	return attachment, nil
+}
+
+// attachmentKey returns the blob store key of a version of an attachment.
+func attachmentKey(attachmentId uuid.UUID, variant domain.AttachmentVariant) string {
+	if variant == domain.AttachmentOriginal {
+		return attachmentId.String()
+	}
+
+	return attachmentId.String() + "-" + string(variant)
+}
+
+// attachmentTarget returns a post in the same place as attachment, which shares its access rules.
+func attachmentTarget(attachment domain.Attachment) domain.Post {
+	if attachment.CircleId != uuid.Nil {
+		return domain.Post{CircleId: attachment.CircleId}
+	}
+
+	return domain.Post{FellowshipId: attachment.FellowshipId}
+}
+
+// attachmentName reduces a client supplied file name to a printable base name.
+func attachmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	if utf8.RuneCountInString(name) > domain.AttachmentNameMaxLength {
+		name = string([]rune(name)[:domain.AttachmentNameMaxLength])
+	}
+
+	return name
+}
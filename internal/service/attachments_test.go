@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// unattachedStore hands out its unattached uploads in batches, failing once they run out if err is set.
+type unattachedStore struct {
+	domain.AttachmentStore
+	unattached []uuid.UUID
+	err        error
+	calls      int
+}
+
+func (s *unattachedStore) DeleteUnattachedAttachments(ctx context.Context, uploadedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	s.calls++
+
+	if since := time.Since(uploadedBefore); since < unattachedAttachmentExpiry || since > unattachedAttachmentExpiry+time.Minute {
+		return nil, errors.New("deleting uploads that have not expired")
+	}
+
+	if len(s.unattached) == 0 && s.err != nil {
+		return nil, s.err
+	}
+
+	n := min(limit, len(s.unattached))
+	batch := s.unattached[:n]
+	s.unattached = s.unattached[n:]
+	return batch, nil
+}
+
+// deletedBlobs records the keys deleted from it.
+type deletedBlobs struct {
+	domain.BlobStore
+	keys map[string]bool
+}
+
+func (b *deletedBlobs) Delete(ctx context.Context, key string) error {
+	b.keys[key] = true
+	return nil
+}
+
+func TestDeleteUnattached(t *testing.T) {
+	failure := errors.New("database is down")
+
+	tests := []struct {
+		name      string
+		uploads   int
+		err       error
+		wantCalls int
+	}{
+		{name: "none", uploads: 0, wantCalls: 1},
+		{name: "one batch", uploads: 3, wantCalls: 1},
+		{name: "full batches", uploads: 2 * unattachedAttachmentBatchSize, wantCalls: 3},
+		{name: "several batches", uploads: 2*unattachedAttachmentBatchSize + 5, wantCalls: 3},
+		{name: "failure after a batch", uploads: unattachedAttachmentBatchSize, err: failure, wantCalls: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uploads := make([]uuid.UUID, test.uploads)
+			for i := range uploads {
+				uploads[i] = uuid.New()
+			}
+
+			store := &unattachedStore{unattached: uploads, err: test.err}
+			blobs := &deletedBlobs{keys: make(map[string]bool)}
+			attachments := &AttachmentService{attachmentStore: store, blobStore: blobs, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+			deleted, err := attachments.DeleteUnattached(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("DeleteUnattached error = %v, want %v", err, test.err)
+			}
+
+			if deleted != test.uploads || store.calls != test.wantCalls {
+				t.Errorf("DeleteUnattached = %d in %d batches, want %d in %d", deleted, store.calls, test.uploads, test.wantCalls)
+			}
+
+			// Every blob an upload can have is deleted with it, and nothing else.
+			if len(blobs.keys) != len(uploads)*len(attachmentBlobs) {
+				t.Errorf("deleted %d blobs, want %d", len(blobs.keys), len(uploads)*len(attachmentBlobs))
+			}
+
+			for _, attachmentId := range uploads {
+				for _, variant := range attachmentBlobs {
+					if !blobs.keys[attachmentKey(attachmentId, variant)] {
+						t.Errorf("blob %s of %s was not deleted", variant, attachmentId)
+					}
+				}
+			}
+		})
+	}
+}
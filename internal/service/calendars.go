@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/config"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/ical"
+	"github.com/google/uuid"
+)
+
+// calendarFeedTokenSize is how many random bytes make a calendar feed token.
+const calendarFeedTokenSize = 32
+
+// NewCalendarService creates a calendar service. User feeds carry the events eventService shows the
+// feed's user, and fellowship feeds the public fellowship's own events.
+func NewCalendarService(store domain.CalendarStore, eventStore domain.EventStore, userStore domain.UserStore, fellowshipStore domain.FellowshipStore, eventService *EventService, serverConfig config.ServerConfig) *CalendarService {
+	return &CalendarService{calendarStore: store, eventStore: eventStore, userStore: userStore, fellowshipStore: fellowshipStore, eventService: eventService, serverConfig: serverConfig}
+}
+
+// CalendarService publishes events as iCalendar feeds: one per user, reached with a feed token, and
+// one for each public fellowship.
+type CalendarService struct {
+	calendarStore   domain.CalendarStore
+	eventStore      domain.EventStore
+	userStore       domain.UserStore
+	fellowshipStore domain.FellowshipStore
+	eventService    *EventService
+	serverConfig    config.ServerConfig
+}
+
+// NewFeed makes a new link to the user's calendar feed, revoking any earlier one.
+func (c *CalendarService) NewFeed(ctx context.Context, user domain.User) (*domain.CalendarFeed, error) {
+	secret := make([]byte, calendarFeedTokenSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar feed token: %v", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	if err := c.calendarStore.SetCalendarFeedToken(ctx, user.Id, hashFeedToken(token), time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &domain.CalendarFeed{URL: "webcal://" + c.serverConfig.GetDomain() + "/api/calendar/user/" + token + "/events.ics"}, nil
+}
+
+// RevokeFeed stops the user's calendar feed link from working.
+func (c *CalendarService) RevokeFeed(ctx context.Context, user domain.User) error {
+	if err := c.calendarStore.DeleteCalendarFeedToken(ctx, user.Id); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrCalendarFeedNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to revoke calendar feed of user %s: %w", user.Id, err)
+	}
+
+	return nil
+}
+
+// UserFeed returns the calendar of every event the feed token's user can see.
+func (c *CalendarService) UserFeed(ctx context.Context, token string) (*ical.Calendar, error) {
+	userId, err := c.calendarStore.GetCalendarFeedUserId(ctx, hashFeedToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCalendarFeedNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	user, err := c.userStore.GetUser(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userId, err)
+	}
+
+	from, to := feedRange(time.Now())
+	events, err := c.eventService.visibleEvents(ctx, *user, domain.FeedFilter{}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ical.Calendar{Name: "Tools of Worship", Events: events}, nil
+}
+
+// FellowshipFeed returns the calendar of a public fellowship's own events. Its circles' events are
+// left out, as circles can be private. Fellowships that are not public are reported as not found.
+func (c *CalendarService) FellowshipFeed(ctx context.Context, fellowshipId uuid.UUID) (*ical.Calendar, error) {
+	fellowship, err := c.fellowshipStore.GetFellowship(ctx, fellowshipId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrFellowshipNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get fellowship %s: %w", fellowshipId, err)
+	}
+
+	if !fellowship.IsPublic {
+		return nil, domain.ErrFellowshipNotFound
+	}
+
+	from, to := feedRange(time.Now())
+	events, err := c.eventStore.GetEvents(ctx, []uuid.UUID{fellowshipId}, nil, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ical.Calendar{Name: fellowship.Name, Events: events}, nil
+}
+
+// feedRange returns the span of time calendar feeds carry events for.
+func feedRange(now time.Time) (time.Time, time.Time) {
+	return now.Add(-domain.CalendarFeedPast), now.Add(domain.CalendarFeedFuture)
+}
+
+// hashFeedToken returns the hash feed tokens are stored by, so the tokens themselves are never kept.
+func hashFeedToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func NewCircleService(store domain.CircleStore, fellowshipStore domain.FellowshipStore) *CircleService {
+	return &CircleService{circleStore: store, fellowshipStore: fellowshipStore}
+}
+
+type CircleService struct {
+	circleStore     domain.CircleStore
+	fellowshipStore domain.FellowshipStore
+}
+
+func (c *CircleService) List(ctx context.Context, user domain.User) ([]domain.Circle, error) {
+	return c.circleStore.GetUserCircles(ctx, user.Id)
+}
+
+// Join gives a fellowship member access to a circle according to its access mode. Request-to-join
+// circles record a join request for a moderator to approve; invite-only circles cannot be joined, and
+// open circles need not be.
+func (c *CircleService) Join(ctx context.Context, user domain.User, circleId uuid.UUID) error {
+	circle, err := c.getCircle(ctx, circleId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.fellowshipStore.GetUserAccessLevel(ctx, user.Id, circle.FellowshipId); errors.Is(err, domain.ErrNotMember) {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("unable to check user permissions for fellowship %s: %w", circle.FellowshipId, err)
+	}
+
+	// Everyone with access to the fellowship already has access to its open circles.
+	if _, err := circleAccess(ctx, c.circleStore, c.fellowshipStore, user.Id, *circle); err == nil {
+		return domain.ErrAlreadyMember
+	} else if !errors.Is(err, domain.ErrNotMember) {
+		return err
+	}
+
+	switch circle.AccessMode {
+	case domain.CircleRequestToJoin:
+		return c.circleStore.CreateJoinRequest(ctx, domain.CircleJoinRequest{CircleId: circleId, UserId: user.Id, Requested: time.Now()})
+	case domain.CircleInviteOnly:
+		return domain.ErrInsufficientAccess
+	default:
+		return nil
+	}
+}
+
+func (c *CircleService) JoinRequests(ctx context.Context, user domain.User, circleId uuid.UUID) ([]domain.CircleJoinRequest, error) {
+	circle, err := c.getCircle(ctx, circleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkModerator(ctx, user, *circle); err != nil {
+		return nil, err
+	}
+
+	return c.circleStore.GetJoinRequests(ctx, circleId)
+}
+
+// RespondToJoinRequest approves or declines a pending join request. Approved members get read and
+// write access, limited to their access level in the fellowship.
+func (c *CircleService) RespondToJoinRequest(ctx context.Context, user domain.User, circleId uuid.UUID, userId uuid.UUID, approve bool) error {
+	circle, err := c.getCircle(ctx, circleId)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkModerator(ctx, user, *circle); err != nil {
+		return err
+	}
+
+	if !approve {
+		err = c.circleStore.RemoveJoinRequest(ctx, circleId, userId)
+	} else {
+		var access domain.AccessLevel
+		access, err = c.newMemberAccess(ctx, userId, *circle)
+		if err != nil {
+			return err
+		}
+
+		err = c.circleStore.ApproveJoinRequest(ctx, circleId, userId, access)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrJoinRequestNotFound
+	}
+
+	return err
+}
+
+// Invite adds a member of the circle's fellowship to the circle directly.
+func (c *CircleService) Invite(ctx context.Context, user domain.User, circleId uuid.UUID, userId uuid.UUID) error {
+	circle, err := c.getCircle(ctx, circleId)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkModerator(ctx, user, *circle); err != nil {
+		return err
+	}
+
+	if _, err := circleAccess(ctx, c.circleStore, c.fellowshipStore, userId, *circle); err == nil {
+		return domain.ErrAlreadyMember
+	} else if !errors.Is(err, domain.ErrNotMember) {
+		return err
+	}
+
+	access, err := c.newMemberAccess(ctx, userId, *circle)
+	if err != nil {
+		return err
+	}
+
+	return c.circleStore.AddCircleMember(ctx, domain.CircleMember{CircleId: circleId, UserId: userId, Access: access})
+}
+
+func (c *CircleService) SetAccessMode(ctx context.Context, user domain.User, circleId uuid.UUID, mode domain.CircleAccessMode) error {
+	if mode != domain.CircleInviteOnly && mode != domain.CircleRequestToJoin && mode != domain.CircleOpen {
+		return domain.ErrInvalidAccessMode
+	}
+
+	circle, err := c.getCircle(ctx, circleId)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkModerator(ctx, user, *circle); err != nil {
+		return err
+	}
+
+	return c.circleStore.SetCircleAccessMode(ctx, circleId, mode)
+}
+
+func (c *CircleService) getCircle(ctx context.Context, circleId uuid.UUID) (*domain.Circle, error) {
+	circle, err := c.circleStore.GetCircle(ctx, circleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCircleNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get circle %s: %w", circleId, err)
+	}
+
+	return circle, nil
+}
+
+// circleAccess returns the user's access to a circle: their circle membership and, for open circles,
+// their access to the circle's fellowship, including Admin access inherited from a parent fellowship.
+// The most privileged of the two wins. It returns ErrNotMember when the user has neither.
+func circleAccess(ctx context.Context, circleStore domain.CircleStore, fellowshipStore domain.FellowshipStore, userId uuid.UUID, circle domain.Circle) (domain.AccessLevel, error) {
+	accessLevel, err := circleStore.GetUserAccessLevel(ctx, userId, circle.Id)
+	if errors.Is(err, domain.ErrNotMember) {
+		accessLevel = domain.NoAccess
+	} else if err != nil {
+		return domain.NoAccess, fmt.Errorf("unable to check user permissions for circle %s: %w", circle.Id, err)
+	}
+
+	if circle.AccessMode == domain.CircleOpen {
+		fellowshipAccess, err := fellowshipStore.GetUserAccessLevel(ctx, userId, circle.FellowshipId)
+		if err == nil {
+			accessLevel = min(accessLevel, fellowshipAccess)
+		} else if !errors.Is(err, domain.ErrNotMember) {
+			return domain.NoAccess, fmt.Errorf("unable to check user permissions for fellowship %s: %w", circle.FellowshipId, err)
+		}
+	}
+
+	if accessLevel == domain.NoAccess {
+		return domain.NoAccess, domain.ErrNotMember
+	}
+
+	return accessLevel, nil
+}
+
+// checkModerator allows circle moderators and the admins of the circle's fellowship.
+func (c *CircleService) checkModerator(ctx context.Context, user domain.User, circle domain.Circle) error {
+	circleAccess, err := circleAccess(ctx, c.circleStore, c.fellowshipStore, user.Id, circle)
+	if err != nil && !errors.Is(err, domain.ErrNotMember) {
+		return err
+	} else if err == nil && canModerate(circleAccess) {
+		return nil
+	}
+
+	fellowshipAccess, err := c.fellowshipStore.GetUserAccessLevel(ctx, user.Id, circle.FellowshipId)
+	if err != nil && !errors.Is(err, domain.ErrNotMember) {
+		return fmt.Errorf("unable to check user permissions for fellowship %s: %w", circle.FellowshipId, err)
+	} else if err == nil && canManage(fellowshipAccess) {
+		return nil
+	}
+
+	return domain.ErrInsufficientAccess
+}
+
+// newMemberAccess returns the access level given to a user joining the circle: read and write,
+// but never more than they hold in the fellowship.
+func (c *CircleService) newMemberAccess(ctx context.Context, userId uuid.UUID, circle domain.Circle) (domain.AccessLevel, error) {
+	fellowshipAccess, err := c.fellowshipStore.GetUserAccessLevel(ctx, userId, circle.FellowshipId)
+	if errors.Is(err, domain.ErrNotMember) {
+		return domain.NoAccess, domain.ErrInvalidCircleMember
+	} else if err != nil {
+		return domain.NoAccess, fmt.Errorf("unable to check user permissions for fellowship %s: %w", circle.FellowshipId, err)
+	}
+
+	return max(fellowshipAccess, domain.ReadAndWrite), nil
+}
+
+func canModerate(accessLevel domain.AccessLevel) bool {
+	if accessLevel == domain.Owner || accessLevel == domain.Admin || accessLevel == domain.Moderator {
+		return true
+	}
+
+	return false
+}
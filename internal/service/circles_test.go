@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fellowshipAccess is a FellowshipStore of users' effective access to one fellowship.
+type fellowshipAccess struct {
+	domain.FellowshipStore
+	access map[uuid.UUID]domain.AccessLevel
+}
+
+func (f fellowshipAccess) GetUserAccessLevel(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (domain.AccessLevel, error) {
+	access, ok := f.access[userId]
+	if !ok {
+		return domain.NoAccess, domain.ErrNotMember
+	}
+
+	return access, nil
+}
+
+// circleMembers is a CircleStore of circles and their explicit members, recording join requests.
+type circleMembers struct {
+	domain.CircleStore
+	circles  map[uuid.UUID]domain.Circle
+	members  map[uuid.UUID]domain.AccessLevel
+	requests []domain.CircleJoinRequest
+}
+
+func (c *circleMembers) GetCircle(ctx context.Context, circleId uuid.UUID) (*domain.Circle, error) {
+	circle, ok := c.circles[circleId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &circle, nil
+}
+
+func (c *circleMembers) GetUserAccessLevel(ctx context.Context, userId uuid.UUID, circleId uuid.UUID) (domain.AccessLevel, error) {
+	access, ok := c.members[userId]
+	if !ok {
+		return domain.NoAccess, domain.ErrNotMember
+	}
+
+	return access, nil
+}
+
+func (c *circleMembers) CreateJoinRequest(ctx context.Context, request domain.CircleJoinRequest) error {
+	c.requests = append(c.requests, request)
+	return nil
+}
+
+func TestCircleAccess(t *testing.T) {
+	fellowshipId := uuid.New()
+	inheritedAdmin, reader, member, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	fellowships := fellowshipAccess{access: map[uuid.UUID]domain.AccessLevel{inheritedAdmin: domain.Admin, reader: domain.ReadOnly, member: domain.ReadAndWrite}}
+
+	tests := []struct {
+		name    string
+		mode    domain.CircleAccessMode
+		user    uuid.UUID
+		want    domain.AccessLevel
+		wantErr error
+	}{
+		{name: "inherited admin of an open circle", mode: domain.CircleOpen, user: inheritedAdmin, want: domain.Admin},
+		{name: "fellowship reader of an open circle", mode: domain.CircleOpen, user: reader, want: domain.ReadOnly},
+		{name: "circle member of an open circle", mode: domain.CircleOpen, user: member, want: domain.Moderator},
+		{name: "outsider of an open circle", mode: domain.CircleOpen, user: outsider, wantErr: domain.ErrNotMember},
+		{name: "inherited admin of a request-to-join circle", mode: domain.CircleRequestToJoin, user: inheritedAdmin, wantErr: domain.ErrNotMember},
+		{name: "circle member of an invite-only circle", mode: domain.CircleInviteOnly, user: member, want: domain.Moderator},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			circle := domain.Circle{Id: uuid.New(), FellowshipId: fellowshipId, AccessMode: test.mode}
+			circles := &circleMembers{members: map[uuid.UUID]domain.AccessLevel{member: domain.Moderator}}
+
+			got, err := circleAccess(context.Background(), circles, fellowships, test.user, circle)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("circleAccess error = %v, want %v", err, test.wantErr)
+			}
+
+			if err == nil && got != test.want {
+				t.Errorf("circleAccess = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	fellowshipId := uuid.New()
+	inheritedAdmin, member, outsider := uuid.New(), uuid.New(), uuid.New()
+	fellowships := fellowshipAccess{access: map[uuid.UUID]domain.AccessLevel{inheritedAdmin: domain.Admin, member: domain.ReadAndWrite}}
+
+	tests := []struct {
+		name        string
+		mode        domain.CircleAccessMode
+		missing     bool
+		user        uuid.UUID
+		wantErr     error
+		wantRequest bool
+	}{
+		{name: "missing circle", missing: true, user: member, wantErr: domain.ErrCircleNotFound},
+		{name: "missing circle for an outsider", missing: true, user: outsider, wantErr: domain.ErrCircleNotFound},
+		{name: "open circle for an inherited admin", mode: domain.CircleOpen, user: inheritedAdmin, wantErr: domain.ErrAlreadyMember},
+		{name: "open circle for a member", mode: domain.CircleOpen, user: member, wantErr: domain.ErrAlreadyMember},
+		{name: "open circle for an outsider", mode: domain.CircleOpen, user: outsider, wantErr: domain.ErrNotMember},
+		{name: "request-to-join circle", mode: domain.CircleRequestToJoin, user: member, wantRequest: true},
+		{name: "request-to-join circle for an inherited admin", mode: domain.CircleRequestToJoin, user: inheritedAdmin, wantRequest: true},
+		{name: "invite-only circle", mode: domain.CircleInviteOnly, user: member, wantErr: domain.ErrInsufficientAccess},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			circle := domain.Circle{Id: uuid.New(), FellowshipId: fellowshipId, AccessMode: test.mode}
+			circles := &circleMembers{circles: map[uuid.UUID]domain.Circle{}, members: map[uuid.UUID]domain.AccessLevel{}}
+			if !test.missing {
+				circles.circles[circle.Id] = circle
+			}
+
+			err := NewCircleService(circles, fellowships).Join(context.Background(), domain.User{Id: test.user}, circle.Id)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Join = %v, want %v", err, test.wantErr)
+			}
+
+			if requested := len(circles.requests) == 1 && circles.requests[0].UserId == test.user; requested != test.wantRequest {
+				t.Errorf("join requests = %v, want a request from the user %t", circles.requests, test.wantRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// NewCommentService creates a comment service. Comments can be read by whoever feedService lets see
+// their post, and written by those who may post where it was made.
+func NewCommentService(store domain.CommentStore, feedService *FeedService) *CommentService {
+	return &CommentService{commentStore: store, feedService: feedService}
+}
+
+type CommentService struct {
+	commentStore domain.CommentStore
+	feedService  *FeedService
+}
+
+// List returns a page of a post's comments, oldest first, to anyone who can see the post.
+func (c *CommentService) List(ctx context.Context, user domain.User, postId uuid.UUID, limit *int, cursor string) ([]domain.Comment, string, error) {
+	post, err := c.feedService.getPost(ctx, user, postId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := c.feedService.checkCanView(ctx, user, *post); err != nil {
+		return nil, "", err
+	}
+
+	var position *domain.CommentCursor
+	if cursor != "" {
+		position = &domain.CommentCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	moderator, err := c.feedService.canModeratePost(ctx, user, *post)
+	if err != nil {
+		return nil, "", err
+	}
+
+	comments, next, err := c.commentStore.GetComments(ctx, postId, moderator, limit, position)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get comments for post %s: %w", postId, err)
+	}
+
+	if next == nil {
+		return comments, "", nil
+	}
+
+	nextCursor, err := domain.EncodeCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return comments, nextCursor, nil
+}
+
+// Add comments on a post, or replies to a top level comment when parentId is set.
+func (c *CommentService) Add(ctx context.Context, user domain.User, postId uuid.UUID, parentId *uuid.UUID, body string) error {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return err
+	}
+
+	post, err := c.feedService.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if post.State != domain.PostPublished {
+		return domain.ErrPostNotFound
+	}
+
+	accessLevel, err := c.feedService.postAccessLevel(ctx, user, *post)
+	if err != nil {
+		return err
+	}
+
+	if !canPost(accessLevel) {
+		return fmt.Errorf("user %s cannot comment on post %s: %w", user.Id, postId, domain.ErrInsufficientAccess)
+	}
+
+	if parentId != nil {
+		parent, err := c.commentStore.GetComment(ctx, *parentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidCommentParent
+		} else if err != nil {
+			return fmt.Errorf("failed to get comment %s: %w", *parentId, err)
+		}
+
+		// Only one level of threading: replies cannot be replied to.
+		if parent.PostId != postId || parent.ParentId != nil || (parent.Hidden != nil && !canModerate(accessLevel)) {
+			return domain.ErrInvalidCommentParent
+		}
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate comment ID: %v", err)
+	}
+
+	return c.commentStore.CreateComment(ctx, domain.Comment{Id: id, PostId: postId, ParentId: parentId, AuthorId: user.Id, Created: time.Now(), Body: body})
+}
+
+// Edit replaces the body of one of the user's own comments.
+func (c *CommentService) Edit(ctx context.Context, user domain.User, commentId uuid.UUID, body string) error {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return err
+	}
+
+	comment, post, err := c.getComment(ctx, user, commentId)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorId != user.Id {
+		return fmt.Errorf("user %s cannot edit comment %s: %w", user.Id, commentId, domain.ErrInsufficientAccess)
+	}
+
+	accessLevel, err := c.feedService.postAccessLevel(ctx, user, *post)
+	if err != nil {
+		return err
+	}
+
+	if !canPost(accessLevel) {
+		return fmt.Errorf("user %s cannot edit comment %s: %w", user.Id, commentId, domain.ErrInsufficientAccess)
+	}
+
+	if err := c.commentStore.UpdateComment(ctx, commentId, body, time.Now()); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrCommentNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to update comment %s: %w", commentId, err)
+	}
+
+	return nil
+}
+
+// Delete soft deletes a comment. Authors can delete their own comments and moderators any comment on posts they moderate.
+func (c *CommentService) Delete(ctx context.Context, user domain.User, commentId uuid.UUID) error {
+	comment, post, err := c.getComment(ctx, user, commentId)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorId != user.Id {
+		accessLevel, err := c.feedService.postAccessLevel(ctx, user, *post)
+		if err != nil {
+			return err
+		}
+
+		if !canModerate(accessLevel) {
+			return fmt.Errorf("user %s cannot delete comment %s: %w", user.Id, commentId, domain.ErrInsufficientAccess)
+		}
+	}
+
+	if err := c.commentStore.DeleteComment(ctx, commentId, user.Id, time.Now()); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrCommentNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete comment %s: %w", commentId, err)
+	}
+
+	return nil
+}
+
+// getComment returns a comment along with the post it belongs to. Hidden comments are only found
+// for moderators.
+func (c *CommentService) getComment(ctx context.Context, user domain.User, commentId uuid.UUID) (*domain.Comment, *domain.Post, error) {
+	comment, err := c.commentStore.GetComment(ctx, commentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, domain.ErrCommentNotFound
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to get comment %s: %w", commentId, err)
+	}
+
+	post, err := c.feedService.getPost(ctx, user, comment.PostId)
+	if errors.Is(err, domain.ErrPostNotFound) {
+		return nil, nil, domain.ErrCommentNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if comment.Hidden != nil {
+		if moderator, err := c.feedService.canModeratePost(ctx, user, *post); err != nil {
+			return nil, nil, err
+		} else if !moderator {
+			return nil, nil, domain.ErrCommentNotFound
+		}
+	}
+
+	return comment, post, nil
+}
+
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > domain.CommentMaxLength {
+		return "", domain.ErrInvalidComment
+	}
+
+	return body, nil
+}
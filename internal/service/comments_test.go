@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+func TestValidateCommentBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{name: "trimmed", body: "  Amen  ", want: "Amen"},
+		{name: "blank", body: " \n ", wantErr: domain.ErrInvalidComment},
+		{name: "multi-byte characters at the limit", body: strings.Repeat("é", domain.CommentMaxLength), want: strings.Repeat("é", domain.CommentMaxLength)},
+		{name: "over the limit", body: strings.Repeat("é", domain.CommentMaxLength+1), wantErr: domain.ErrInvalidComment},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := validateCommentBody(test.body)
+			if !errors.Is(err, test.wantErr) || body != test.want {
+				t.Errorf("validateCommentBody = %q, %v, want %q, %v", body, err, test.want, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/recurrence"
+	"github.com/google/uuid"
+)
+
+// NewEventService creates an event service. Members add events where feedService lets them post
+// and see those of the fellowships and circles it lets them see. Events keep the timezone of their
+// fellowship.
+func NewEventService(store domain.EventStore, fellowshipStore domain.FellowshipStore, circleStore domain.CircleStore, feedService *FeedService) *EventService {
+	return &EventService{eventStore: store, fellowshipStore: fellowshipStore, circleStore: circleStore, feedService: feedService}
+}
+
+// EventService schedules events in fellowships and circles, expands their recurrence rules into
+// occurrences and takes members' RSVPs.
+type EventService struct {
+	eventStore      domain.EventStore
+	fellowshipStore domain.FellowshipStore
+	circleStore     domain.CircleStore
+	feedService     *FeedService
+}
+
+// Create schedules an event. Members who can post to a fellowship or circle can add events to it.
+func (e *EventService) Create(ctx context.Context, user domain.User, input domain.EventInput) (*domain.Event, error) {
+	fellowshipId, circleId := input.FellowshipId, input.CircleId
+	if (fellowshipId == uuid.Nil) == (circleId == uuid.Nil) {
+		return nil, fmt.Errorf("%w: event must belong to either a fellowship or a circle", domain.ErrInvalidEvent)
+	}
+
+	accessLevel, err := e.feedService.accessLevel(ctx, user, fellowshipId, circleId)
+	if err != nil {
+		return nil, err
+	} else if accessLevel == domain.NoAccess {
+		return nil, domain.ErrNotMember
+	} else if !canPost(accessLevel) {
+		return nil, fmt.Errorf("user %s cannot add events here: %w", user.Id, domain.ErrInsufficientAccess)
+	}
+
+	timezone, err := e.timezone(ctx, fellowshipId, circleId)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate event ID: %v", err)
+	}
+
+	event := domain.Event{Id: id, FellowshipId: fellowshipId, CircleId: circleId, CreatorId: user.Id, Created: time.Now(), Exceptions: make([]domain.EventException, 0)}
+	if err := setEventDetails(&event, input, timezone); err != nil {
+		return nil, err
+	}
+
+	if err := e.eventStore.CreateEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// Get returns an event with its exceptions.
+func (e *EventService) Get(ctx context.Context, user domain.User, eventId uuid.UUID) (*domain.Event, error) {
+	return e.getEvent(ctx, user, eventId)
+}
+
+// Edit replaces an event's details. The event keeps its fellowship or circle, and picks up the
+// fellowship's current timezone. Exceptions for occurrences the new rule no longer has are dropped.
+func (e *EventService) Edit(ctx context.Context, user domain.User, eventId uuid.UUID, input domain.EventInput) (*domain.Event, error) {
+	event, err := e.getManagedEvent(ctx, user, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone, err := e.timezone(ctx, event.FellowshipId, event.CircleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setEventDetails(event, input, timezone); err != nil {
+		return nil, err
+	}
+
+	exceptions := make([]domain.EventException, 0, len(event.Exceptions))
+	for _, exception := range event.Exceptions {
+		if occurs, err := hasOccurrence(*event, exception.Occurrence); err != nil {
+			return nil, err
+		} else if occurs {
+			exceptions = append(exceptions, exception)
+		}
+	}
+
+	now := time.Now()
+	event.Exceptions = exceptions
+	event.Edited = &now
+
+	if err := e.eventStore.UpdateEvent(ctx, *event); errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEventNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to update event %s: %w", eventId, err)
+	}
+
+	return event, nil
+}
+
+// Delete removes an event and all its occurrences.
+func (e *EventService) Delete(ctx context.Context, user domain.User, eventId uuid.UUID) error {
+	if _, err := e.getManagedEvent(ctx, user, eventId); err != nil {
+		return err
+	}
+
+	if err := e.eventStore.DeleteEvent(ctx, eventId, time.Now()); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrEventNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete event %s: %w", eventId, err)
+	}
+
+	return nil
+}
+
+// SetException cancels one occurrence of an event, or moves it to the exception's start and end.
+func (e *EventService) SetException(ctx context.Context, user domain.User, eventId uuid.UUID, exception domain.EventException) error {
+	event, err := e.getManagedEvent(ctx, user, eventId)
+	if err != nil {
+		return err
+	}
+
+	if exception.Cancelled {
+		if exception.Start != nil || exception.End != nil {
+			return fmt.Errorf("%w: a cancelled occurrence has no new time", domain.ErrInvalidEvent)
+		}
+	} else {
+		if exception.Start == nil || exception.End == nil {
+			return fmt.Errorf("%w: a moved occurrence needs a start and end", domain.ErrInvalidEvent)
+		}
+
+		if err := validateEventTimes(*exception.Start, *exception.End); err != nil {
+			return err
+		}
+	}
+
+	if occurs, err := hasOccurrence(*event, exception.Occurrence); err != nil {
+		return err
+	} else if !occurs {
+		return domain.ErrOccurrenceNotFound
+	}
+
+	return e.eventStore.SetEventException(ctx, eventId, exception)
+}
+
+// ClearException restores an occurrence to the time the event's rule has it.
+func (e *EventService) ClearException(ctx context.Context, user domain.User, eventId uuid.UUID, occurrence time.Time) error {
+	if _, err := e.getManagedEvent(ctx, user, eventId); err != nil {
+		return err
+	}
+
+	if err := e.eventStore.DeleteEventException(ctx, eventId, occurrence); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrOccurrenceNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to clear exception of event %s: %w", eventId, err)
+	}
+
+	return nil
+}
+
+// RSVP records whether the user will come to an occurrence, replacing any earlier response.
+func (e *EventService) RSVP(ctx context.Context, user domain.User, eventId uuid.UUID, occurrence time.Time, response domain.RSVPResponse) error {
+	if !response.IsValid() {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidRSVP, response)
+	}
+
+	event, err := e.getEvent(ctx, user, eventId)
+	if err != nil {
+		return err
+	}
+
+	if occurs, err := hasOccurrence(*event, occurrence); err != nil {
+		return err
+	} else if !occurs {
+		return domain.ErrOccurrenceNotFound
+	}
+
+	if exception := event.Exception(occurrence); exception != nil && exception.Cancelled {
+		return fmt.Errorf("%w: occurrence is cancelled", domain.ErrInvalidRSVP)
+	}
+
+	return e.eventStore.SetRSVP(ctx, domain.EventRSVP{EventId: eventId, Occurrence: occurrence, UserId: user.Id, Response: response, Responded: time.Now()})
+}
+
+// RSVPs returns the responses for one occurrence of an event.
+func (e *EventService) RSVPs(ctx context.Context, user domain.User, eventId uuid.UUID, occurrence time.Time) ([]domain.EventRSVP, error) {
+	if _, err := e.getEvent(ctx, user, eventId); err != nil {
+		return nil, err
+	}
+
+	return e.eventStore.GetRSVPs(ctx, eventId, occurrence)
+}
+
+// Occurrences expands the events the user can see into the occurrences that overlap [from, to), with
+// their RSVP counts. The filter's fellowship and circle narrow the events as they do the feed.
+// Cancelled occurrences are left out and moved ones appear at their new time.
+func (e *EventService) Occurrences(ctx context.Context, user domain.User, filter domain.FeedFilter, from time.Time, to time.Time) (*domain.EventOccurrences, error) {
+	if !to.After(from) || to.Sub(from) > domain.EventRangeMax {
+		return nil, fmt.Errorf("%w: the range must end after it starts and span at most %d days", domain.ErrInvalidEventRange, domain.EventRangeMax/(24*time.Hour))
+	}
+
+	events, err := e.visibleEvents(ctx, user, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]domain.EventOccurrence, 0)
+	for _, event := range events {
+		eventOccurrences, err := expandEvent(event, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		occurrences = append(occurrences, eventOccurrences...)
+	}
+
+	slices.SortFunc(occurrences, func(a, b domain.EventOccurrence) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.EventId.String(), b.EventId.String())
+	})
+
+	if len(occurrences) > domain.EventOccurrencesMax {
+		occurrences = occurrences[:domain.EventOccurrencesMax]
+	}
+
+	if err := e.attachRSVPCounts(ctx, user, occurrences); err != nil {
+		return nil, err
+	}
+
+	result := &domain.EventOccurrences{Events: make([]domain.Event, 0), Occurrences: occurrences}
+	for _, event := range events {
+		if slices.ContainsFunc(occurrences, func(o domain.EventOccurrence) bool { return o.EventId == event.Id }) {
+			result.Events = append(result.Events, event)
+		}
+	}
+
+	return result, nil
+}
+
+// visibleEvents returns the events the user can see with occurrences in [from, to), narrowed by the
+// filter's fellowship and circle.
+func (e *EventService) visibleEvents(ctx context.Context, user domain.User, filter domain.FeedFilter, from time.Time, to time.Time) ([]domain.Event, error) {
+	postFilter, err := e.feedService.postFilter(ctx, user, domain.FeedFilter{FellowshipId: filter.FellowshipId, CircleId: filter.CircleId})
+	if err != nil {
+		return nil, err
+	}
+
+	return e.eventStore.GetEvents(ctx, postFilter.FellowshipIDs, postFilter.CircleIDs, from, to)
+}
+
+func (e *EventService) attachRSVPCounts(ctx context.Context, user domain.User, occurrences []domain.EventOccurrence) error {
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	type key struct {
+		eventId    uuid.UUID
+		occurrence int64
+	}
+
+	index := make(map[key]int, len(occurrences))
+	var eventIDs []uuid.UUID
+	times := make([]time.Time, 0, len(occurrences))
+	for i, occurrence := range occurrences {
+		index[key{occurrence.EventId, occurrence.Occurrence.UnixNano()}] = i
+		eventIDs = mergeIDs(eventIDs, []uuid.UUID{occurrence.EventId})
+		times = append(times, occurrence.Occurrence)
+	}
+
+	tallies, err := e.eventStore.GetRSVPTallies(ctx, user.Id, eventIDs, times)
+	if err != nil {
+		return fmt.Errorf("failed to get rsvp counts: %w", err)
+	}
+
+	for _, tally := range tallies {
+		i, ok := index[key{tally.EventId, tally.Occurrence.UnixNano()}]
+		if !ok {
+			continue
+		}
+
+		counts := &occurrences[i].RSVPs
+		switch tally.Response {
+		case domain.RSVPYes:
+			counts.Yes = tally.Count
+		case domain.RSVPNo:
+			counts.No = tally.Count
+		case domain.RSVPMaybe:
+			counts.Maybe = tally.Count
+		}
+
+		if tally.Mine {
+			counts.Mine = tally.Response
+		}
+	}
+
+	return nil
+}
+
+// getEvent returns an event the user can see.
+func (e *EventService) getEvent(ctx context.Context, user domain.User, eventId uuid.UUID) (*domain.Event, error) {
+	event, err := e.eventStore.GetEvent(ctx, eventId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEventNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get event %s: %w", eventId, err)
+	}
+
+	if err := e.feedService.checkCanSee(ctx, user, event.FellowshipId, event.CircleId); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// getManagedEvent returns an event the user can change: one they created, or any event in a
+// fellowship or circle they moderate.
+func (e *EventService) getManagedEvent(ctx context.Context, user domain.User, eventId uuid.UUID) (*domain.Event, error) {
+	event, err := e.getEvent(ctx, user, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorId == user.Id {
+		return event, nil
+	}
+
+	accessLevel, err := e.feedService.accessLevel(ctx, user, event.FellowshipId, event.CircleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canModerate(accessLevel) {
+		return nil, fmt.Errorf("user %s cannot change event %s: %w", user.Id, eventId, domain.ErrInsufficientAccess)
+	}
+
+	return event, nil
+}
+
+// timezone returns the timezone of a fellowship, or of a circle's fellowship.
+func (e *EventService) timezone(ctx context.Context, fellowshipId, circleId uuid.UUID) (string, error) {
+	if circleId != uuid.Nil {
+		circle, err := e.circleStore.GetCircle(ctx, circleId)
+		if err != nil {
+			return "", fmt.Errorf("failed to get circle %s: %w", circleId, err)
+		}
+
+		fellowshipId = circle.FellowshipId
+	}
+
+	fellowship, err := e.fellowshipStore.GetFellowship(ctx, fellowshipId)
+	if err != nil {
+		return "", fmt.Errorf("failed to get fellowship %s: %w", fellowshipId, err)
+	}
+
+	if fellowship.Timezone == "" {
+		return "UTC", nil
+	}
+
+	return fellowship.Timezone, nil
+}
+
+// setEventDetails validates input and copies it into event, with its times in timezone and its
+// recurrence rule in canonical form.
+func setEventDetails(event *domain.Event, input domain.EventInput, timezone string) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" || utf8.RuneCountInString(title) > domain.EventTitleMaxLength {
+		return fmt.Errorf("%w: title must be between 1 and %d characters", domain.ErrInvalidEvent, domain.EventTitleMaxLength)
+	}
+
+	location := strings.TrimSpace(input.Location)
+	if utf8.RuneCountInString(location) > domain.EventLocationMaxLength {
+		return fmt.Errorf("%w: location is longer than %d characters", domain.ErrInvalidEvent, domain.EventLocationMaxLength)
+	}
+
+	if utf8.RuneCountInString(input.Description) > domain.EventDescriptionMaxLength {
+		return fmt.Errorf("%w: description is longer than %d characters", domain.ErrInvalidEvent, domain.EventDescriptionMaxLength)
+	}
+
+	if err := validateEventTimes(input.Start, input.End); err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Errorf("failed to load timezone %q: %v", timezone, err)
+	}
+
+	start, end := input.Start.In(loc), input.End.In(loc)
+	seriesEnd := &end
+	rrule := ""
+
+	if strings.TrimSpace(input.RRule) != "" {
+		rule, err := recurrence.Parse(input.RRule, loc)
+		if err != nil {
+			return err
+		}
+
+		rrule = rule.String()
+		seriesEnd = nil
+		if last, ok := rule.Last(start); ok {
+			lastEnd := last.Add(end.Sub(start))
+			seriesEnd = &lastEnd
+		}
+	}
+
+	event.Title, event.Location, event.Description = title, location, input.Description
+	event.Start, event.End, event.Timezone = start, end, timezone
+	event.RRule, event.SeriesEnd = rrule, seriesEnd
+	return nil
+}
+
+func validateEventTimes(start, end time.Time) error {
+	if start.IsZero() || !end.After(start) {
+		return fmt.Errorf("%w: an event must end after it starts", domain.ErrInvalidEvent)
+	}
+
+	if end.Sub(start) > domain.EventMaxDuration {
+		return fmt.Errorf("%w: an event can last at most %d days", domain.ErrInvalidEvent, domain.EventMaxDuration/(24*time.Hour))
+	}
+
+	return nil
+}
+
+// eventRule returns the event's first start in its timezone, and its recurrence rule, which is nil for
+// events that happen once.
+func eventRule(event domain.Event) (time.Time, *recurrence.Rule, error) {
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to load timezone %q of event %s: %v", event.Timezone, event.Id, err)
+	}
+
+	start := event.Start.In(loc)
+	if event.RRule == "" {
+		return start, nil, nil
+	}
+
+	rule, err := recurrence.Parse(event.RRule, loc)
+	if err != nil {
+		return start, nil, fmt.Errorf("failed to parse rule of event %s: %w", event.Id, err)
+	}
+
+	return start, rule, nil
+}
+
+// hasOccurrence reports whether the event's rule has an occurrence starting at t.
+func hasOccurrence(event domain.Event, t time.Time) (bool, error) {
+	start, rule, err := eventRule(event)
+	if err != nil {
+		return false, err
+	}
+
+	if rule == nil {
+		return start.Equal(t), nil
+	}
+
+	return rule.Occurs(start, t), nil
+}
+
+// expandEvent returns the occurrences of an event that overlap [from, to), applying its exceptions.
+func expandEvent(event domain.Event, from time.Time, to time.Time) ([]domain.EventOccurrence, error) {
+	start, rule, err := eventRule(event)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := event.Duration()
+
+	// Occurrences that start before from can still be running at from.
+	starts := []time.Time{start}
+	if rule != nil {
+		starts = rule.Occurrences(start, from.Add(-duration), to, domain.EventOccurrencesMax)
+	}
+
+	occurrences := make([]domain.EventOccurrence, 0, len(starts))
+	for _, occurrence := range starts {
+		if event.Exception(occurrence) == nil && occurrence.Before(to) && occurrence.Add(duration).After(from) {
+			occurrences = append(occurrences, domain.EventOccurrence{EventId: event.Id, Occurrence: occurrence, Start: occurrence, End: occurrence.Add(duration)})
+		}
+	}
+
+	// Moved occurrences are found by their new time, which may be outside the range of the rule's.
+	loc := start.Location()
+	for _, exception := range event.Exceptions {
+		if exception.Cancelled || exception.Start == nil || exception.End == nil {
+			continue
+		}
+
+		if exception.Start.Before(to) && exception.End.After(from) {
+			occurrences = append(occurrences, domain.EventOccurrence{EventId: event.Id, Occurrence: exception.Occurrence.In(loc),
+				Start: exception.Start.In(loc), End: exception.End.In(loc), Rescheduled: true})
+		}
+	}
+
+	return occurrences, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+func TestSetEventDetailsLengths(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   domain.EventInput
+		wantErr error
+	}{
+		{
+			name: "multi-byte characters at the limits",
+			input: domain.EventInput{
+				Title:       strings.Repeat("é", domain.EventTitleMaxLength),
+				Location:    strings.Repeat("é", domain.EventLocationMaxLength),
+				Description: strings.Repeat("é", domain.EventDescriptionMaxLength),
+			},
+		},
+		{name: "title over the limit", input: domain.EventInput{Title: strings.Repeat("é", domain.EventTitleMaxLength+1)}, wantErr: domain.ErrInvalidEvent},
+		{name: "location over the limit", input: domain.EventInput{Title: "Prayer", Location: strings.Repeat("é", domain.EventLocationMaxLength+1)}, wantErr: domain.ErrInvalidEvent},
+		{name: "description over the limit", input: domain.EventInput{Title: "Prayer", Description: strings.Repeat("é", domain.EventDescriptionMaxLength+1)}, wantErr: domain.ErrInvalidEvent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := test.input
+			input.Start = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+			input.End = input.Start.Add(time.Hour)
+
+			err := setEventDetails(&domain.Event{}, input, "UTC")
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("setEventDetails = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -2,70 +2,1211 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/circletypes"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/contentcheck"
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/entities"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/markdown"
 	"github.com/google/uuid"
 )
 
-func NewFeedService(store domain.FeedStore, fellowshipStore domain.FellowshipStore, circleStore domain.CircleStore) *FeedService {
-	return &FeedService{feedStore: store, fellowshipStore: fellowshipStore, circleStore: circleStore}
+// publishBatchSize bounds how many scheduled posts are published in one pass.
+const publishBatchSize = 100
+
+func NewFeedService(store domain.FeedStore, userStore domain.UserStore, fellowshipStore domain.FellowshipStore, circleStore domain.CircleStore, reactionStore domain.ReactionStore, attachmentStore domain.AttachmentStore, moderationStore domain.ModerationStore, circleTypes *circletypes.Registry, contentChecks *contentcheck.Chain, notifications *NotificationService, logger *slog.Logger) *FeedService {
+	return &FeedService{feedStore: store, userStore: userStore, fellowshipStore: fellowshipStore, circleStore: circleStore, reactionStore: reactionStore, attachmentStore: attachmentStore, moderationStore: moderationStore,
+		circleTypes: circleTypes, contentChecks: contentChecks, notifications: notifications, logger: logger}
 }
 
 type FeedService struct {
 	feedStore       domain.FeedStore
+	userStore       domain.UserStore
 	fellowshipStore domain.FellowshipStore
 	circleStore     domain.CircleStore
+	reactionStore   domain.ReactionStore
+	attachmentStore domain.AttachmentStore
+	moderationStore domain.ModerationStore
+	circleTypes     *circletypes.Registry
+	contentChecks   *contentcheck.Chain
+	notifications   *NotificationService
+	logger          *slog.Logger
 }
 
-func (f *FeedService) List(ctx context.Context, user domain.User, limit *int, before *time.Time, after *time.Time) ([]domain.Post, error) {
-	fellowshipIDs, err := f.fellowshipStore.GetUserFellowshipIDs(ctx, user.Id)
+// List returns a page of the user's feed, newest first, narrowed by filter. The first page also holds
+// the pinned posts. NextCursor pages to older posts and PrevCursor to newer ones, including posts made
+// after this page was read, so clients can poll with it.
+func (f *FeedService) List(ctx context.Context, user domain.User, filter domain.FeedFilter, limit *int, cursor string) (*domain.FeedPage, error) {
+	if filter.PrayerStatus != nil && !filter.PrayerStatus.IsValid() {
+		return nil, domain.ErrInvalidPrayerStatus
+	}
+
+	var position *domain.FeedCursor
+	if cursor != "" {
+		position = &domain.FeedCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, err
+		}
+	}
+
+	postFilter, err := f.postFilter(ctx, user, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.FeedPage{Posts: []domain.Post{}, Pinned: []domain.Post{}}
+	if len(postFilter.FellowshipIDs) == 0 && len(postFilter.CircleIDs) == 0 {
+		return page, nil
+	}
+
+	posts, hasMore, err := f.feedStore.GetPosts(ctx, postFilter, limit, position)
 	if err != nil {
-		return nil, fmt.Errorf("failed get user fellowships: %v", err)
+		return nil, err
+	}
+
+	if err := f.attachReactions(ctx, user, posts); err != nil {
+		return nil, err
+	}
+
+	if err := f.attachAttachments(ctx, posts); err != nil {
+		return nil, err
 	}
 
-	circleIDs, err := f.circleStore.GetUserCircleIDs(ctx, user.Id)
+	page.Posts = posts
+	page.NextCursor, page.PrevCursor, err = feedCursors(posts, position, hasMore)
 	if err != nil {
-		return nil, fmt.Errorf("failed get user circles: %v", err)
+		return nil, err
 	}
 
-	if len(fellowshipIDs) == 0 && len(circleIDs) == 0 {
-		return []domain.Post{}, nil
+	if position == nil {
+		pinned, err := f.feedStore.GetPinnedPosts(ctx, postFilter, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pinned posts: %w", err)
+		}
+
+		if err := f.attachReactions(ctx, user, pinned); err != nil {
+			return nil, err
+		}
+
+		if err := f.attachAttachments(ctx, pinned); err != nil {
+			return nil, err
+		}
+
+		page.Pinned = pinned
 	}
 
-	return f.feedStore.GetPosts(ctx, fellowshipIDs, circleIDs, limit, before, after)
+	return page, nil
 }
 
-func (f *FeedService) Post(ctx context.Context, user domain.User, fellowshipId uuid.UUID, circleId uuid.UUID, heading string, article string) error {
+// Search finds posts the user can see that match query, narrowed by filter, best match first.
+func (f *FeedService) Search(ctx context.Context, user domain.User, query string, filter domain.FeedFilter, limit *int, cursor string) ([]domain.PostSearchResult, string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > domain.SearchQueryMaxLength {
+		return nil, "", domain.ErrInvalidSearchQuery
+	}
+
+	if filter.PrayerStatus != nil && !filter.PrayerStatus.IsValid() {
+		return nil, "", domain.ErrInvalidPrayerStatus
+	}
+
+	var position *domain.PostSearchCursor
+	if cursor != "" {
+		position = &domain.PostSearchCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	postFilter, err := f.postFilter(ctx, user, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(postFilter.FellowshipIDs) == 0 && len(postFilter.CircleIDs) == 0 {
+		return []domain.PostSearchResult{}, "", nil
+	}
+
+	results, next, err := f.feedStore.SearchPosts(ctx, postFilter, query, limit, position)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	posts := make([]domain.Post, len(results))
+	for i, result := range results {
+		posts[i] = result.Post
+	}
+
+	if err := f.attachReactions(ctx, user, posts); err != nil {
+		return nil, "", err
+	}
+
+	if err := f.attachAttachments(ctx, posts); err != nil {
+		return nil, "", err
+	}
+
+	for i := range results {
+		results[i].Post = posts[i]
+	}
+
+	if next == nil {
+		return results, "", nil
+	}
+
+	nextCursor, err := domain.EncodeCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return results, nextCursor, nil
+}
+
+// Post creates a post after running it past the content checks. It reports whether the post was held
+// for the moderators to review, in which case it stays hidden until they approve it.
+func (f *FeedService) Post(ctx context.Context, user domain.User, input domain.PostInput) (bool, error) {
+	fellowshipId, circleId := input.FellowshipId, input.CircleId
 	if fellowshipId != uuid.Nil && circleId != uuid.Nil {
-		return fmt.Errorf("cannot be post to both fellowship and circle")
+		return false, fmt.Errorf("cannot be post to both fellowship and circle: %w", domain.ErrInvalidPostTarget)
 	} else if fellowshipId == uuid.Nil && circleId == uuid.Nil {
-		return fmt.Errorf("post must be associated with either a fellowship or a circle")
+		return false, fmt.Errorf("post must be associated with either a fellowship or a circle: %w", domain.ErrInvalidPostTarget)
 	}
 
 	if fellowshipId != uuid.Nil {
-		if accessLevel, err := f.fellowshipStore.GetUserAccessLevel(ctx, user.Id, fellowshipId); err != nil {
-			return fmt.Errorf("unable to check user permissions for fellowship %s: %v", fellowshipId, err)
+		if accessLevel, err := f.fellowshipStore.GetUserAccessLevel(ctx, user.Id, fellowshipId); errors.Is(err, domain.ErrNotMember) {
+			return false, err
+		} else if err != nil {
+			return false, fmt.Errorf("unable to check user permissions for fellowship %s: %w", fellowshipId, err)
 		} else if !canPost(accessLevel) {
-			return fmt.Errorf("user %s cannot post to fellowship %s", user.Id, fellowshipId)
+			return false, fmt.Errorf("user %s cannot post to fellowship %s: %w", user.Id, fellowshipId, domain.ErrInsufficientAccess)
 		}
 	}
 
 	if circleId != uuid.Nil {
-		if accessLevel, err := f.circleStore.GetUserAccessLevel(ctx, user.Id, circleId); err != nil {
-			return fmt.Errorf("unable to check user permissions for circle %s: %v", circleId, err)
+		circle, err := f.getCircle(ctx, circleId)
+		if err != nil {
+			return false, err
+		}
+
+		if accessLevel, err := circleAccess(ctx, f.circleStore, f.fellowshipStore, user.Id, *circle); err != nil {
+			return false, err
 		} else if !canPost(accessLevel) {
-			return fmt.Errorf("user %s cannot post to circle %s", user.Id, circleId)
+			return false, fmt.Errorf("user %s cannot post to circle %s: %w", user.Id, circleId, domain.ErrInsufficientAccess)
 		}
 	}
 
+	kind, err := f.validateKind(ctx, circleId, input.Kind, input.Details)
+	if err != nil {
+		return false, err
+	}
+
+	format := input.Format
+	if format == "" {
+		format = domain.PostFormatPlain
+	} else if format != domain.PostFormatPlain && format != domain.PostFormatMarkdown {
+		return false, fmt.Errorf("%w: %q", domain.ErrInvalidPostFormat, format)
+	}
+
+	attachments, err := f.postAttachments(ctx, user, fellowshipId, circleId, input.AttachmentIDs)
+	if err != nil {
+		return false, err
+	}
+
+	postEntities, err := f.findEntities(ctx, fellowshipId, circleId, input.Article)
+	if err != nil {
+		return false, err
+	}
+
+	var prayer *domain.PrayerState
+	if kind == domain.PostKindPrayer {
+		prayer = &domain.PrayerState{Status: domain.PrayerActive, FollowUp: input.FollowUp}
+	} else if input.FollowUp != nil {
+		return false, fmt.Errorf("%w: only prayer posts take a follow-up date", domain.ErrInvalidPostDetails)
+	}
+
+	now := time.Now()
+
+	state := domain.PostPublished
+	var scheduledFor *time.Time
+	if input.Draft {
+		state = domain.PostDraft
+	} else if input.PublishAt != nil && input.PublishAt.After(now) {
+		state, scheduledFor = domain.PostScheduled, input.PublishAt
+	}
+
+	// Polls of scheduled posts must still be open when the post goes live. Drafts are checked again
+	// when they are published.
+	published := now
+	if scheduledFor != nil {
+		published = *scheduledFor
+	}
+
+	poll, err := validatePoll(input.Poll, published)
+	if err != nil {
+		return false, err
+	}
+
+	if input.FollowUp != nil && !input.FollowUp.After(published) {
+		return false, fmt.Errorf("%w: follow-up date must be after the post is published", domain.ErrInvalidPostDetails)
+	}
+
 	uuid, err := uuid.NewV7()
 	if err != nil {
-		return fmt.Errorf("failed to generate post ID: %v", err)
+		return false, fmt.Errorf("failed to generate post ID: %v", err)
+	}
+
+	post := domain.Post{Id: uuid, AuthorId: user.Id, FellowshipId: fellowshipId, CircleId: circleId, Posted: now, Kind: kind, Heading: input.Heading, Article: input.Article, Details: input.Details, Prayer: prayer,
+		State: state, ScheduledFor: scheduledFor, Format: format, ArticleHTML: renderArticle(format, input.Article), Entities: postEntities, Attachments: attachments, Poll: poll}
+
+	// Drafts are screened when they are published, as moderators cannot open them before then.
+	verdict := contentcheck.Allow
+	if state != domain.PostDraft {
+		if verdict, err = f.screen(ctx, user, post, false); err != nil {
+			return false, err
+		}
+	}
+
+	if verdict.Outcome == domain.ContentHold {
+		post.Hidden = &now
+	}
+
+	if err := f.feedStore.CreatePost(ctx, post); err != nil {
+		return false, err
+	}
+
+	if verdict.Outcome == domain.ContentHold {
+		return true, f.holdForReview(ctx, post, verdict.Reason)
 	}
 
-	return f.feedStore.CreatePost(ctx, domain.Post{Id: uuid, AuthorId: user.Id, FellowshipId: fellowshipId, CircleId: circleId, Posted: time.Now(), Heading: heading, Article: article})
+	if state == domain.PostPublished {
+		f.notifyMentions(ctx, post, domain.MentionedUsers(post.Entities))
+	}
+
+	return false, nil
+}
+
+// Drafts returns the user's drafts and scheduled posts.
+func (f *FeedService) Drafts(ctx context.Context, user domain.User) ([]domain.Post, error) {
+	posts, err := f.feedStore.GetUnpublishedPosts(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.attachAttachments(ctx, posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Publish publishes one of the user's drafts or scheduled posts straight away, or schedules it when
+// publishAt is in the future. Drafts go through the content checks first, and Publish reports whether
+// the post was held for the moderators to review.
+func (f *FeedService) Publish(ctx context.Context, user domain.User, postId uuid.UUID, publishAt *time.Time) (bool, error) {
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return false, err
+	}
+
+	if post.AuthorId != user.Id || post.State == domain.PostPublished {
+		return false, domain.ErrPostNotFound
+	}
+
+	now := time.Now()
+	screened := *post
+	screened.Posted = now
+
+	published := now
+	if publishAt != nil && publishAt.After(now) {
+		published = *publishAt
+	}
+
+	if post.Poll != nil && post.Poll.Closes != nil && !post.Poll.Closes.After(published) {
+		return false, fmt.Errorf("%w: poll must close after the post is published", domain.ErrInvalidPoll)
+	}
+
+	verdict := contentcheck.Allow
+	if post.State == domain.PostDraft {
+		if verdict, err = f.screen(ctx, user, screened, false); err != nil {
+			return false, err
+		}
+	}
+
+	// Held drafts are hidden as they leave the author's hands, never before.
+	var hidden *time.Time
+	if verdict.Outcome == domain.ContentHold {
+		hidden = &now
+	}
+
+	if publishAt != nil && publishAt.After(now) {
+		err = f.feedStore.SchedulePost(ctx, postId, *publishAt, hidden)
+	} else {
+		err = f.feedStore.PublishPost(ctx, postId, now, hidden)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, domain.ErrPostNotFound
+	} else if err != nil {
+		return false, fmt.Errorf("failed to publish post %s: %w", postId, err)
+	}
+
+	if verdict.Outcome == domain.ContentHold {
+		return true, f.holdForReview(ctx, screened, verdict.Reason)
+	}
+
+	// Held posts are announced when the moderators approve them.
+	if post.Hidden == nil && (publishAt == nil || !publishAt.After(now)) {
+		f.notifyMentions(ctx, *post, domain.MentionedUsers(post.Entities))
+	}
+
+	return false, nil
+}
+
+// PublishDue publishes the scheduled posts whose time has come and reports how many went live.
+func (f *FeedService) PublishDue(ctx context.Context) (int, error) {
+	posts, err := f.feedStore.PublishDuePosts(ctx, time.Now(), publishBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish scheduled posts: %w", err)
+	}
+
+	for _, post := range posts {
+		// Held posts are announced when the moderators approve them.
+		if post.Hidden != nil {
+			continue
+		}
+
+		content := fmt.Sprintf("<p>Your scheduled post <strong>%s</strong> has been published.</p>", html.EscapeString(post.Heading))
+		go func() {
+			if err := f.notifications.EmailUsers(context.WithoutCancel(ctx), []uuid.UUID{post.AuthorId}, "Your post is live", content); err != nil {
+				f.logger.Error("feed: failed to notify author of published post", "postId", post.Id, "error", err)
+			}
+		}()
+
+		f.notifyMentions(ctx, post, domain.MentionedUsers(post.Entities))
+	}
+
+	return len(posts), nil
+}
+
+// Edit replaces the content of one of the user's own posts, keeping the previous content as a revision.
+// The new content goes through the content checks like a new post, and Edit reports whether it was
+// held for the moderators to review, hiding the post until they approve it.
+func (f *FeedService) Edit(ctx context.Context, user domain.User, postId uuid.UUID, edit domain.PostEdit) (bool, error) {
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return false, err
+	}
+
+	if post.AuthorId != user.Id {
+		return false, fmt.Errorf("user %s cannot edit post %s: %w", user.Id, postId, domain.ErrInsufficientAccess)
+	}
+
+	accessLevel, err := f.postAccessLevel(ctx, user, *post)
+	if err != nil {
+		return false, err
+	}
+
+	if !canPost(accessLevel) {
+		return false, fmt.Errorf("user %s cannot edit post %s: %w", user.Id, postId, domain.ErrInsufficientAccess)
+	}
+
+	if _, err := f.validateKind(ctx, post.CircleId, post.Kind, edit.Details); err != nil {
+		return false, err
+	}
+
+	edit.ArticleHTML = renderArticle(post.Format, edit.Article)
+	if edit.Entities, err = f.findEntities(ctx, post.FellowshipId, post.CircleId, edit.Article); err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	edited := *post
+	edited.Heading, edited.Article, edited.ArticleHTML, edited.Entities, edited.Details = edit.Heading, edit.Article, edit.ArticleHTML, edit.Entities, edit.Details
+	edited.Posted = now
+
+	verdict := contentcheck.Allow
+	if post.State != domain.PostDraft {
+		if verdict, err = f.screen(ctx, user, edited, true); err != nil {
+			return false, err
+		}
+	}
+
+	if err := f.feedStore.UpdatePost(ctx, postId, user.Id, edit, now); errors.Is(err, sql.ErrNoRows) {
+		return false, domain.ErrPostNotFound
+	} else if err != nil {
+		return false, fmt.Errorf("failed to update post %s: %w", postId, err)
+	}
+
+	if verdict.Outcome == domain.ContentHold {
+		if err := f.feedStore.HoldPost(ctx, postId, now); err != nil {
+			return false, fmt.Errorf("failed to hold post %s: %w", postId, err)
+		}
+
+		return true, f.holdForReview(ctx, edited, verdict.Reason)
+	}
+
+	// Only members the edit newly mentions are notified; drafts notify when they are published, and
+	// held posts when they are approved.
+	if post.State == domain.PostPublished && post.Hidden == nil {
+		previous := domain.MentionedUsers(post.Entities)
+		var added []uuid.UUID
+		for _, userId := range domain.MentionedUsers(edit.Entities) {
+			if !slices.Contains(previous, userId) {
+				added = append(added, userId)
+			}
+		}
+
+		post.Heading = edit.Heading
+		f.notifyMentions(ctx, *post, added)
+	}
+
+	return false, nil
+}
+
+// Delete soft deletes a post. Authors can delete their own posts and moderators any post in their fellowship or circle.
+func (f *FeedService) Delete(ctx context.Context, user domain.User, postId uuid.UUID) error {
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if post.AuthorId != user.Id {
+		accessLevel, err := f.postAccessLevel(ctx, user, *post)
+		if err != nil {
+			return err
+		}
+
+		if !canModerate(accessLevel) {
+			return fmt.Errorf("user %s cannot delete post %s: %w", user.Id, postId, domain.ErrInsufficientAccess)
+		}
+	}
+
+	if err := f.feedStore.DeletePost(ctx, postId, user.Id, time.Now()); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPostNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete post %s: %w", postId, err)
+	}
+
+	return nil
+}
+
+// Revisions returns the edit history of a post to its author or a moderator.
+func (f *FeedService) Revisions(ctx context.Context, user domain.User, postId uuid.UUID) ([]domain.PostRevision, error) {
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.AuthorId != user.Id {
+		accessLevel, err := f.postAccessLevel(ctx, user, *post)
+		if err != nil {
+			return nil, err
+		}
+
+		if !canModerate(accessLevel) {
+			return nil, fmt.Errorf("user %s cannot view revisions of post %s: %w", user.Id, postId, domain.ErrInsufficientAccess)
+		}
+	}
+
+	return f.feedStore.GetPostRevisions(ctx, postId)
+}
+
+// Pin keeps a post at the top of feeds until the given time. Only moderators can pin, and only in
+// circle types that allow it. Urgent pins also email every member of the circle straight away.
+func (f *FeedService) Pin(ctx context.Context, user domain.User, postId uuid.UUID, until time.Time, urgent bool) error {
+	if !until.After(time.Now()) {
+		return fmt.Errorf("%w: pins must expire in the future", domain.ErrInvalidPin)
+	}
+
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if post.CircleId == uuid.Nil {
+		return domain.ErrInvalidPin
+	}
+
+	circle, err := f.getCircle(ctx, post.CircleId)
+	if err != nil {
+		return err
+	}
+
+	if !f.circleTypes.CanPin(circle.Type) {
+		return domain.ErrInvalidPin
+	}
+
+	if err := f.checkModerator(ctx, user, *post); err != nil {
+		return err
+	}
+
+	if err := f.feedStore.PinPost(ctx, postId, user.Id, until); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPostNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to pin post %s: %w", postId, err)
+	}
+
+	if urgent {
+		recipients, err := f.circleRecipients(ctx, *circle)
+		if err != nil {
+			return err
+		}
+
+		content := fmt.Sprintf("<p>An urgent notice was posted in %s:</p><h3>%s</h3><p>%s</p>",
+			html.EscapeString(circle.Name), html.EscapeString(post.Heading), html.EscapeString(post.Article))
+
+		// Send in the background so the request does not wait on every email.
+		go func() {
+			if err := f.notifications.EmailUsers(context.WithoutCancel(ctx), recipients, "Urgent: "+post.Heading, content); err != nil {
+				f.logger.Error("feed: failed to send urgent notice", "postId", postId, "error", err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (f *FeedService) Unpin(ctx context.Context, user domain.User, postId uuid.UUID) error {
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if err := f.checkModerator(ctx, user, *post); err != nil {
+		return err
+	}
+
+	if err := f.feedStore.UnpinPost(ctx, postId); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPostNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to unpin post %s: %w", postId, err)
+	}
+
+	return nil
+}
+
+// SetPrayerState moves a prayer request through its lifecycle. Only the author or a moderator can do so.
+func (f *FeedService) SetPrayerState(ctx context.Context, user domain.User, postId uuid.UUID, state domain.PrayerState) error {
+	if !state.Status.IsValid() {
+		return domain.ErrInvalidPrayerStatus
+	}
+
+	if state.Testimony != "" && state.Status != domain.PrayerAnswered {
+		return fmt.Errorf("%w: only answered prayers take a testimony", domain.ErrInvalidPrayerStatus)
+	} else if utf8.RuneCountInString(state.Testimony) > domain.TestimonyMaxLength {
+		return fmt.Errorf("%w: testimony is too long", domain.ErrInvalidPrayerStatus)
+	}
+
+	if state.FollowUp != nil && !state.FollowUp.After(time.Now()) {
+		return fmt.Errorf("%w: follow-up date must be in the future", domain.ErrInvalidPrayerStatus)
+	}
+
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if post.Prayer == nil {
+		return domain.ErrNotPrayerRequest
+	}
+
+	if post.AuthorId != user.Id {
+		accessLevel, err := f.postAccessLevel(ctx, user, *post)
+		if err != nil {
+			return err
+		}
+
+		if !canModerate(accessLevel) {
+			return fmt.Errorf("user %s cannot update prayer %s: %w", user.Id, postId, domain.ErrInsufficientAccess)
+		}
+	}
+
+	if err := f.feedStore.SetPrayerState(ctx, postId, state); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPostNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to update prayer %s: %w", postId, err)
+	}
+
+	return nil
+}
+
+// postFilter limits filter to the fellowships and circles the user can see and normalizes its tag. A fellowship filter keeps
+// the fellowship's own posts and those of its circles the user is in. Asking for a fellowship or
+// circle the user cannot see returns ErrNotMember.
+func (f *FeedService) postFilter(ctx context.Context, user domain.User, filter domain.FeedFilter) (domain.PostFilter, error) {
+	postFilter := domain.PostFilter{AuthorId: filter.AuthorId, Kind: filter.Kind, PrayerStatus: filter.PrayerStatus}
+
+	if filter.Tag != "" {
+		tag, ok := entities.NormalizeTag(filter.Tag)
+		if !ok {
+			return postFilter, fmt.Errorf("%w: %q", domain.ErrInvalidTag, filter.Tag)
+		}
+
+		postFilter.Tag = tag
+	}
+
+	fellowshipIDs, err := f.fellowshipStore.GetUserFellowshipIDs(ctx, user.Id)
+	if err != nil {
+		return postFilter, fmt.Errorf("failed get user fellowships: %v", err)
+	}
+
+	var circleIDs []uuid.UUID
+	if filter.FellowshipId != uuid.Nil {
+		// Admins of a parent fellowship can read the fellowship without being its members.
+		if !slices.Contains(fellowshipIDs, filter.FellowshipId) {
+			accessLevel, err := f.accessLevel(ctx, user, filter.FellowshipId, uuid.Nil)
+			if err != nil {
+				return postFilter, err
+			}
+
+			if accessLevel == domain.NoAccess {
+				return postFilter, fmt.Errorf("user %s cannot see fellowship %s: %w", user.Id, filter.FellowshipId, domain.ErrNotMember)
+			}
+		}
+
+		circles, err := f.circleStore.GetUserCircles(ctx, user.Id)
+		if err != nil {
+			return postFilter, fmt.Errorf("failed get user circles: %v", err)
+		}
+
+		fellowshipIDs = []uuid.UUID{filter.FellowshipId}
+		for _, circle := range circles {
+			if circle.FellowshipId == filter.FellowshipId {
+				circleIDs = append(circleIDs, circle.Id)
+			}
+		}
+	} else {
+		circleIDs, err = f.circleStore.GetUserCircleIDs(ctx, user.Id)
+		if err != nil {
+			return postFilter, fmt.Errorf("failed get user circles: %v", err)
+		}
+
+		inheritedCircleIDs, err := f.circleStore.GetInheritedNoticeCircleIDs(ctx, user.Id)
+		if err != nil {
+			return postFilter, fmt.Errorf("failed get inherited notice circles: %v", err)
+		}
+
+		circleIDs = mergeIDs(circleIDs, inheritedCircleIDs)
+	}
+
+	if filter.CircleId != uuid.Nil {
+		if !slices.Contains(circleIDs, filter.CircleId) {
+			return postFilter, fmt.Errorf("user %s cannot see circle %s: %w", user.Id, filter.CircleId, domain.ErrNotMember)
+		}
+
+		fellowshipIDs = nil
+		circleIDs = []uuid.UUID{filter.CircleId}
+	}
+
+	postFilter.FellowshipIDs = fellowshipIDs
+	postFilter.CircleIDs = circleIDs
+	postFilter.ModeratedFellowshipIDs, postFilter.ModeratedCircleIDs, err = f.moderatedIDs(ctx, user)
+	if err != nil {
+		return postFilter, err
+	}
+
+	return postFilter, nil
+}
+
+// moderatedIDs returns the fellowships the user moderates, including those inherited from a parent
+// fellowship, and the circles they moderate directly. The circles of moderated fellowships are
+// moderated too, which the feed store takes care of.
+func (f *FeedService) moderatedIDs(ctx context.Context, user domain.User) ([]uuid.UUID, []uuid.UUID, error) {
+	moderatedFellowshipIDs, err := f.fellowshipStore.GetModeratedFellowshipIDs(ctx, user.Id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed get moderated fellowships: %v", err)
+	}
+
+	moderatedCircleIDs, err := f.circleStore.GetModeratedCircleIDs(ctx, user.Id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed get moderated circles: %v", err)
+	}
+
+	return moderatedFellowshipIDs, moderatedCircleIDs, nil
+}
+
+// React toggles one of the user's reactions to a post and reports whether the reaction is now present.
+func (f *FeedService) React(ctx context.Context, user domain.User, postId uuid.UUID, kind domain.ReactionKind) (bool, error) {
+	post, err := f.getPost(ctx, user, postId)
+	if err != nil {
+		return false, err
+	}
+
+	if post.State != domain.PostPublished {
+		return false, domain.ErrPostNotFound
+	}
+
+	if err := f.checkCanView(ctx, user, *post); err != nil {
+		return false, err
+	}
+
+	if post.CircleId == uuid.Nil {
+		err = circletypes.Fellowship.ValidateReaction(kind)
+	} else {
+		var circle *domain.Circle
+		circle, err = f.circleStore.GetCircle(ctx, post.CircleId)
+		if err != nil {
+			return false, fmt.Errorf("failed to get circle %s: %w", post.CircleId, err)
+		}
+
+		err = f.circleTypes.ValidateReaction(circle.Type, kind)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return f.reactionStore.ToggleReaction(ctx, domain.Reaction{PostId: postId, UserId: user.Id, Kind: kind, Reacted: time.Now()})
+}
+
+// attachReactions fills in the reaction counts of posts from the user's point of view.
+func (f *FeedService) attachReactions(ctx context.Context, user domain.User, posts []domain.Post) error {
+	postIDs := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.Id
+	}
+
+	counts, err := f.reactionStore.GetReactionCounts(ctx, postIDs, user.Id)
+	if err != nil {
+		return fmt.Errorf("failed to get reaction counts: %w", err)
+	}
+
+	for i := range posts {
+		posts[i].Reactions = counts[posts[i].Id]
+		if posts[i].Reactions == nil {
+			posts[i].Reactions = []domain.ReactionCount{}
+		}
+	}
+
+	return nil
+}
+
+// attachAttachments fills in the attachments of posts.
+func (f *FeedService) attachAttachments(ctx context.Context, posts []domain.Post) error {
+	postIDs := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.Id
+	}
+
+	attachments, err := f.attachmentStore.GetPostAttachments(ctx, postIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get post attachments: %w", err)
+	}
+
+	for i := range posts {
+		posts[i].Attachments = attachments[posts[i].Id]
+		if posts[i].Attachments == nil {
+			posts[i].Attachments = []domain.Attachment{}
+		}
+	}
+
+	return nil
+}
+
+// postAttachments returns the attachments named by attachmentIDs after checking that the user
+// uploaded them to the place being posted to and that they are not on another post yet.
+func (f *FeedService) postAttachments(ctx context.Context, user domain.User, fellowshipId, circleId uuid.UUID, attachmentIDs []uuid.UUID) ([]domain.Attachment, error) {
+	if len(attachmentIDs) == 0 {
+		return nil, nil
+	}
+
+	attachmentIDs = mergeIDs(nil, attachmentIDs)
+	if len(attachmentIDs) > domain.PostMaxAttachments {
+		return nil, fmt.Errorf("%w: a post can carry at most %d attachments", domain.ErrInvalidAttachment, domain.PostMaxAttachments)
+	}
+
+	attachments, err := f.attachmentStore.GetAttachments(ctx, attachmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	if len(attachments) != len(attachmentIDs) {
+		return nil, domain.ErrAttachmentNotFound
+	}
+
+	for _, attachment := range attachments {
+		if attachment.UploaderId != user.Id {
+			return nil, domain.ErrAttachmentNotFound
+		}
+
+		if attachment.PostId != nil {
+			return nil, fmt.Errorf("%w: attachment %s is already on a post", domain.ErrInvalidAttachment, attachment.Id)
+		}
+
+		if attachment.CircleId != circleId || (circleId == uuid.Nil && attachment.FellowshipId != fellowshipId) {
+			return nil, fmt.Errorf("%w: attachment %s was uploaded elsewhere", domain.ErrInvalidAttachment, attachment.Id)
+		}
+	}
+
+	return attachments, nil
+}
+
+// findEntities returns the mentions and tags in the article of a post to a fellowship or circle.
+// Mentions resolve only to the members who can see the post, and only the members whose names
+// follow an @ are looked up.
+func (f *FeedService) findEntities(ctx context.Context, fellowshipId, circleId uuid.UUID, article string) ([]domain.PostEntity, error) {
+	names := entities.MentionNames(article)
+	if len(names) == 0 {
+		return entities.Parse(article, nil), nil
+	}
+
+	var (
+		members []domain.User
+		err     error
+	)
+	if circleId != uuid.Nil {
+		if members, err = f.circleStore.GetCircleRecipientsNamed(ctx, circleId, names); err != nil {
+			return nil, fmt.Errorf("failed to get mentioned members of circle %s: %w", circleId, err)
+		}
+	} else {
+		if members, err = f.fellowshipStore.GetFellowshipMembersNamed(ctx, fellowshipId, names); err != nil {
+			return nil, fmt.Errorf("failed to get mentioned members of fellowship %s: %w", fellowshipId, err)
+		}
+	}
+
+	return entities.Parse(article, members), nil
+}
+
+// notifyMentions emails the members mentioned by a published post in the background. Authors are
+// not told about mentioning themselves.
+func (f *FeedService) notifyMentions(ctx context.Context, post domain.Post, userIds []uuid.UUID) {
+	userIds = slices.DeleteFunc(slices.Clone(userIds), func(userId uuid.UUID) bool { return userId == post.AuthorId })
+	if len(userIds) == 0 {
+		return
+	}
+
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+
+		author, err := f.userStore.GetUser(ctx, post.AuthorId)
+		if err != nil {
+			f.logger.Error("feed: failed to get author of post mentioning members", "postId", post.Id, "error", err)
+			return
+		}
+
+		content := fmt.Sprintf("<p>%s mentioned you in <strong>%s</strong>.</p>", html.EscapeString(author.DisplayName), html.EscapeString(post.Heading))
+		if err := f.notifications.EmailUsers(ctx, userIds, author.DisplayName+" mentioned you", content); err != nil {
+			f.logger.Error("feed: failed to notify mentioned members", "postId", post.Id, "error", err)
+		}
+	}()
+}
+
+// checkContent runs a new or edited post past the content checks. Posts by moderators of the
+// fellowship or circle they post to are not checked.
+func (f *FeedService) checkContent(ctx context.Context, user domain.User, post domain.Post, edit bool) (contentcheck.Verdict, error) {
+	moderator, err := f.canModeratePost(ctx, user, post)
+	if err != nil {
+		return contentcheck.Verdict{}, err
+	} else if moderator {
+		return contentcheck.Allow, nil
+	}
+
+	fellowshipId, err := f.postFellowshipId(ctx, post)
+	if err != nil {
+		return contentcheck.Verdict{}, err
+	}
+
+	verdict, err := f.contentChecks.Check(ctx, contentcheck.Submission{Post: post, Author: user, FellowshipId: fellowshipId, Edit: edit})
+	if err != nil {
+		return contentcheck.Verdict{}, fmt.Errorf("failed to check post content: %w", err)
+	}
+
+	return verdict, nil
+}
+
+// screen runs a new or edited post past the content checks. Rejections are returned as errors, so the
+// verdict is either to allow the post or to hold it.
+func (f *FeedService) screen(ctx context.Context, user domain.User, post domain.Post, edit bool) (contentcheck.Verdict, error) {
+	verdict, err := f.checkContent(ctx, user, post, edit)
+	if err != nil {
+		return contentcheck.Verdict{}, err
+	}
+
+	if verdict.Outcome == domain.ContentReject {
+		if verdict.Err != nil {
+			return contentcheck.Verdict{}, fmt.Errorf("%w: %s", verdict.Err, verdict.Reason)
+		}
+
+		return contentcheck.Verdict{}, fmt.Errorf("%w: %s", domain.ErrPostRejected, verdict.Reason)
+	}
+
+	return verdict, nil
+}
+
+// holdForReview queues a held post for the moderators of its fellowship.
+func (f *FeedService) holdForReview(ctx context.Context, post domain.Post, reason string) error {
+	fellowshipId, err := f.postFellowshipId(ctx, post)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate report ID: %v", err)
+	}
+
+	report := domain.Report{Id: id, FellowshipId: fellowshipId, PostId: post.Id, AuthorId: post.AuthorId, Reason: "Held for review: " + reason, Created: post.Posted, Status: domain.ReportOpen}
+	if err := f.moderationStore.CreateReport(ctx, report); err != nil {
+		return fmt.Errorf("failed to queue post %s for review: %w", post.Id, err)
+	}
+
+	return nil
+}
+
+// approveHeldPost shows a post the content checks held once a moderator approves it, and tells the
+// members it mentions if it has been published.
+func (f *FeedService) approveHeldPost(ctx context.Context, postId uuid.UUID) error {
+	post, err := f.feedStore.GetPost(ctx, postId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // deleted by its author in the meantime
+	} else if err != nil {
+		return fmt.Errorf("failed to get post %s: %w", postId, err)
+	}
+
+	if err := f.feedStore.ShowPost(ctx, postId); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to show post %s: %w", postId, err)
+	}
+
+	if post.State == domain.PostPublished {
+		f.notifyMentions(ctx, *post, domain.MentionedUsers(post.Entities))
+	}
+
+	return nil
+}
+
+// postFellowshipId returns the fellowship a post was made in, or its circle's fellowship.
+func (f *FeedService) postFellowshipId(ctx context.Context, post domain.Post) (uuid.UUID, error) {
+	if post.CircleId == uuid.Nil {
+		return post.FellowshipId, nil
+	}
+
+	circle, err := f.circleStore.GetCircle(ctx, post.CircleId)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get circle %s: %w", post.CircleId, err)
+	}
+
+	return circle.FellowshipId, nil
+}
+
+// validatePoll returns a new post's poll with its options trimmed, or nil for posts without one. The
+// poll must close after the post is published.
+func validatePoll(poll *domain.Poll, published time.Time) (*domain.Poll, error) {
+	if poll == nil {
+		return nil, nil
+	}
+
+	if len(poll.Options) < domain.PollMinOptions || len(poll.Options) > domain.PollMaxOptions {
+		return nil, fmt.Errorf("%w: polls need %d to %d options", domain.ErrInvalidPoll, domain.PollMinOptions, domain.PollMaxOptions)
+	}
+
+	if poll.Closes != nil && !poll.Closes.After(published) {
+		return nil, fmt.Errorf("%w: poll must close after the post is published", domain.ErrInvalidPoll)
+	}
+
+	options := make([]string, len(poll.Options))
+	for i, option := range poll.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || utf8.RuneCountInString(option) > domain.PollOptionMaxLength {
+			return nil, fmt.Errorf("%w: options must be 1 to %d characters", domain.ErrInvalidPoll, domain.PollOptionMaxLength)
+		}
+
+		if slices.Contains(options[:i], option) {
+			return nil, fmt.Errorf("%w: duplicate option %q", domain.ErrInvalidPoll, option)
+		}
+
+		options[i] = option
+	}
+
+	return &domain.Poll{Options: options, MultipleChoice: poll.MultipleChoice, Anonymous: poll.Anonymous, Closes: poll.Closes}, nil
+}
+
+// renderArticle returns the HTML for an article written in format, or nothing for plain text.
+func renderArticle(format domain.PostFormat, article string) string {
+	if format != domain.PostFormatMarkdown {
+		return ""
+	}
+
+	return markdown.Render(article)
+}
+
+// getPost returns a post the user may act on. Drafts and scheduled posts are only found for their
+// author, and hidden posts only for moderators.
+func (f *FeedService) getPost(ctx context.Context, user domain.User, postId uuid.UUID) (*domain.Post, error) {
+	post, err := f.feedStore.GetPost(ctx, postId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPostNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get post %s: %w", postId, err)
+	}
+
+	// Scheduled posts the content checks held are also shown to the moderators reviewing them.
+	if post.State != domain.PostPublished && post.AuthorId != user.Id && (post.State == domain.PostDraft || post.Hidden == nil) {
+		return nil, domain.ErrPostNotFound
+	}
+
+	if post.Hidden != nil {
+		if moderator, err := f.canModeratePost(ctx, user, *post); err != nil {
+			return nil, err
+		} else if !moderator {
+			return nil, domain.ErrPostNotFound
+		}
+	}
+
+	return post, nil
+}
+
+// validateKind checks a post's kind and details against the rules of the circle it is posted in,
+// or the fellowship rules when circleId is nil. It returns the kind to store.
+func (f *FeedService) validateKind(ctx context.Context, circleId uuid.UUID, kind domain.PostKind, details json.RawMessage) (domain.PostKind, error) {
+	if circleId == uuid.Nil {
+		return circletypes.Fellowship.ValidatePost(kind, details)
+	}
+
+	circle, err := f.circleStore.GetCircle(ctx, circleId)
+	if err != nil {
+		return "", fmt.Errorf("failed to get circle %s: %w", circleId, err)
+	}
+
+	return f.circleTypes.ValidatePost(circle.Type, kind, details)
+}
+
+// postAccessLevel returns the user's access to the fellowship or circle a post belongs to.
+func (f *FeedService) postAccessLevel(ctx context.Context, user domain.User, post domain.Post) (domain.AccessLevel, error) {
+	return f.accessLevel(ctx, user, post.FellowshipId, post.CircleId)
+}
+
+// accessLevel returns the user's access to a fellowship, or to a circle when circleId is set. For
+// circles, access to the circle's fellowship also counts, so fellowship moderators can moderate its circles.
+func (f *FeedService) accessLevel(ctx context.Context, user domain.User, fellowshipId, circleId uuid.UUID) (domain.AccessLevel, error) {
+	accessLevel := domain.NoAccess
+
+	if circleId != uuid.Nil {
+		circle, err := f.getCircle(ctx, circleId)
+		if err != nil {
+			return domain.NoAccess, err
+		}
+
+		access, err := circleAccess(ctx, f.circleStore, f.fellowshipStore, user.Id, *circle)
+		if err == nil {
+			accessLevel = access
+		} else if !errors.Is(err, domain.ErrNotMember) {
+			return domain.NoAccess, err
+		}
+
+		fellowshipId = circle.FellowshipId
+	}
+
+	fellowshipAccess, err := f.fellowshipStore.GetUserAccessLevel(ctx, user.Id, fellowshipId)
+	if err != nil && !errors.Is(err, domain.ErrNotMember) {
+		return domain.NoAccess, fmt.Errorf("unable to check user permissions for fellowship %s: %w", fellowshipId, err)
+	}
+
+	if err == nil && (circleId == uuid.Nil || canModerate(fellowshipAccess)) {
+		accessLevel = min(accessLevel, fellowshipAccess)
+	}
+
+	return accessLevel, nil
+}
+
+func (f *FeedService) getCircle(ctx context.Context, circleId uuid.UUID) (*domain.Circle, error) {
+	circle, err := f.circleStore.GetCircle(ctx, circleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCircleNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get circle %s: %w", circleId, err)
+	}
+
+	return circle, nil
+}
+
+func (f *FeedService) canModeratePost(ctx context.Context, user domain.User, post domain.Post) (bool, error) {
+	accessLevel, err := f.postAccessLevel(ctx, user, post)
+	if err != nil {
+		return false, err
+	}
+
+	return canModerate(accessLevel), nil
+}
+
+func (f *FeedService) checkModerator(ctx context.Context, user domain.User, post domain.Post) error {
+	moderator, err := f.canModeratePost(ctx, user, post)
+	if err != nil {
+		return err
+	}
+
+	if !moderator {
+		return fmt.Errorf("user %s cannot moderate post %s: %w", user.Id, post.Id, domain.ErrInsufficientAccess)
+	}
+
+	return nil
+}
+
+// circleRecipients returns the users who see a circle's posts: its members, and for open circles
+// every member of its fellowship.
+func (f *FeedService) circleRecipients(ctx context.Context, circle domain.Circle) ([]uuid.UUID, error) {
+	members, err := f.circleStore.GetCircleMembers(ctx, circle.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members of circle %s: %w", circle.Id, err)
+	}
+
+	userIds := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		userIds = append(userIds, member.UserId)
+	}
+
+	if circle.AccessMode == domain.CircleOpen {
+		fellowshipMembers, err := f.fellowshipStore.GetFellowshipMembers(ctx, circle.FellowshipId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get members of fellowship %s: %w", circle.FellowshipId, err)
+		}
+
+		fellowshipUserIds := make([]uuid.UUID, 0, len(fellowshipMembers))
+		for _, member := range fellowshipMembers {
+			fellowshipUserIds = append(fellowshipUserIds, member.UserId)
+		}
+
+		userIds = mergeIDs(userIds, fellowshipUserIds)
+	}
+
+	return userIds, nil
+}
+
+// checkCanView allows anyone with access to the post's fellowship or circle, including followers
+// of a parent fellowship's notices.
+func (f *FeedService) checkCanView(ctx context.Context, user domain.User, post domain.Post) error {
+	return f.checkCanSee(ctx, user, post.FellowshipId, post.CircleId)
+}
+
+// checkCanSee allows anyone with access to a fellowship, or to a circle when circleId is set,
+// including followers of a parent fellowship's notices.
+func (f *FeedService) checkCanSee(ctx context.Context, user domain.User, fellowshipId, circleId uuid.UUID) error {
+	accessLevel, err := f.accessLevel(ctx, user, fellowshipId, circleId)
+	if err != nil {
+		return err
+	} else if accessLevel != domain.NoAccess {
+		return nil
+	}
+
+	if circleId != uuid.Nil {
+		inheritedCircleIDs, err := f.circleStore.GetInheritedNoticeCircleIDs(ctx, user.Id)
+		if err != nil {
+			return fmt.Errorf("failed get inherited notice circles: %v", err)
+		}
+
+		if slices.Contains(inheritedCircleIDs, circleId) {
+			return nil
+		}
+	}
+
+	return domain.ErrNotMember
 }
 
 func canPost(accessLevel domain.AccessLevel) bool {
@@ -75,3 +1216,53 @@ func canPost(accessLevel domain.AccessLevel) bool {
 
 	return false
 }
+
+// feedCursors returns the cursors around a page of posts read from position. Older posts only
+// remain when the store said so or the page was read towards newer posts, while newer posts may
+// always arrive later.
+func feedCursors(posts []domain.Post, position *domain.FeedCursor, hasMore bool) (string, string, error) {
+	if len(posts) == 0 {
+		if position != nil && position.Newer {
+			prevCursor, err := domain.EncodeCursor(position)
+			return "", prevCursor, err
+		}
+
+		return "", "", nil
+	}
+
+	var nextCursor string
+	if hasMore || (position != nil && position.Newer) {
+		last := posts[len(posts)-1]
+		cursor, err := domain.EncodeCursor(domain.FeedCursor{Posted: last.Posted, Id: last.Id})
+		if err != nil {
+			return "", "", err
+		}
+
+		nextCursor = cursor
+	}
+
+	first := posts[0]
+	prevCursor, err := domain.EncodeCursor(domain.FeedCursor{Posted: first.Posted, Id: first.Id, Newer: true})
+	if err != nil {
+		return "", "", err
+	}
+
+	return nextCursor, prevCursor, nil
+}
+
+// mergeIDs appends the IDs in extra that are not already in ids.
+func mergeIDs(ids []uuid.UUID, extra []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+
+	for _, id := range extra {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
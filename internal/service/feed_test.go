@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// noPosts is a FeedStore without any posts.
+type noPosts struct {
+	domain.FeedStore
+}
+
+func (noPosts) GetPost(ctx context.Context, postId uuid.UUID) (*domain.Post, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestSetPrayerStateTestimonyLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		testimony string
+		wantErr   error
+	}{
+		// Testimonies within the limit get as far as looking up the post.
+		{name: "at the limit", testimony: strings.Repeat("a", domain.TestimonyMaxLength), wantErr: domain.ErrPostNotFound},
+		{name: "multi-byte characters at the limit", testimony: strings.Repeat("é", domain.TestimonyMaxLength), wantErr: domain.ErrPostNotFound},
+		{name: "over the limit", testimony: strings.Repeat("é", domain.TestimonyMaxLength+1), wantErr: domain.ErrInvalidPrayerStatus},
+	}
+
+	feed := &FeedService{feedStore: noPosts{}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := domain.PrayerState{Status: domain.PrayerAnswered, Testimony: test.testimony}
+
+			err := feed.SetPrayerState(context.Background(), domain.User{Id: uuid.New()}, uuid.New(), state)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("SetPrayerState = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// onePost is a FeedStore of a single published post.
+type onePost struct {
+	domain.FeedStore
+	post domain.Post
+}
+
+func (o onePost) GetPost(ctx context.Context, postId uuid.UUID) (*domain.Post, error) {
+	if postId != o.post.Id {
+		return nil, sql.ErrNoRows
+	}
+
+	post := o.post
+	return &post, nil
+}
+
+// noCircles is a CircleStore without any circles.
+type noCircles struct {
+	domain.CircleStore
+}
+
+func (noCircles) GetCircle(ctx context.Context, circleId uuid.UUID) (*domain.Circle, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestPinInMissingCircle(t *testing.T) {
+	post := domain.Post{Id: uuid.New(), CircleId: uuid.New(), AuthorId: uuid.New(), State: domain.PostPublished}
+	feed := &FeedService{feedStore: onePost{post: post}, circleStore: noCircles{}}
+
+	err := feed.Pin(context.Background(), domain.User{Id: post.AuthorId}, post.Id, time.Now().Add(time.Hour), false)
+	if !errors.Is(err, domain.ErrCircleNotFound) {
+		t.Errorf("Pin = %v, want ErrCircleNotFound", err)
+	}
+}
+
+func TestValidatePollCloses(t *testing.T) {
+	published := time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		closes  time.Time
+		wantErr error
+	}{
+		{name: "after publishing", closes: published.Add(time.Hour)},
+		{name: "when published", closes: published, wantErr: domain.ErrInvalidPoll},
+		{name: "before publishing", closes: published.Add(-24 * time.Hour), wantErr: domain.ErrInvalidPoll},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			poll := &domain.Poll{Options: []string{"Yes", "No"}, Closes: &test.closes}
+
+			if _, err := validatePoll(poll, published); !errors.Is(err, test.wantErr) {
+				t.Errorf("validatePoll = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetPrayerStateFollowUp(t *testing.T) {
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(24*time.Hour)
+
+	tests := []struct {
+		name     string
+		followUp *time.Time
+		wantErr  error
+	}{
+		// Valid states get as far as looking up the post.
+		{name: "none", wantErr: domain.ErrPostNotFound},
+		{name: "future", followUp: &future, wantErr: domain.ErrPostNotFound},
+		{name: "past", followUp: &past, wantErr: domain.ErrInvalidPrayerStatus},
+	}
+
+	feed := &FeedService{feedStore: noPosts{}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := domain.PrayerState{Status: domain.PrayerActive, FollowUp: test.followUp}
+
+			err := feed.SetPrayerState(context.Background(), domain.User{Id: uuid.New()}, uuid.New(), state)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("SetPrayerState = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
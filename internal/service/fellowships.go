@@ -2,8 +2,15 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
 )
 
 func NewFellowshipService(store domain.FellowshipStore) *FellowshipService {
@@ -17,3 +24,250 @@ type FellowshipService struct {
 func (f *FellowshipService) List(ctx context.Context, user domain.User) ([]domain.Fellowship, error) {
 	return f.fellowshipStore.GetUserFellowships(ctx, user.Id)
 }
+
+// Get returns a fellowship to its members. Anyone else only sees the id, name and description of
+// public fellowships, as public search shows them.
+func (f *FellowshipService) Get(ctx context.Context, user domain.User, fellowshipId uuid.UUID) (*domain.Fellowship, error) {
+	fellowship, err := f.fellowshipStore.GetFellowship(ctx, fellowshipId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrFellowshipNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get fellowship %s: %w", fellowshipId, err)
+	}
+
+	accessLevel, err := f.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return nil, err
+	}
+
+	if accessLevel != domain.NoAccess {
+		return fellowship, nil
+	}
+
+	if !fellowship.IsPublic {
+		return nil, domain.ErrNotMember
+	}
+
+	return &domain.Fellowship{Id: fellowship.Id, Name: fellowship.Name, Description: fellowship.Description, IsPublic: true}, nil
+}
+
+// Search finds public fellowships for discovery. It does not require a signed-in user.
+// cursor is the opaque value returned as the next cursor of a previous page, or empty for the first page.
+func (f *FellowshipService) Search(ctx context.Context, query string, limit *int, cursor string) ([]domain.Fellowship, string, error) {
+	if utf8.RuneCountInString(query) > domain.SearchQueryMaxLength {
+		return nil, "", domain.ErrInvalidSearchQuery
+	}
+
+	var position *domain.FellowshipSearchCursor
+	if cursor != "" {
+		position = &domain.FellowshipSearchCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	fellowships, next, err := f.fellowshipStore.SearchPublicFellowships(ctx, query, limit, position)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if next == nil {
+		return fellowships, "", nil
+	}
+
+	nextCursor, err := domain.EncodeCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fellowships, nextCursor, nil
+}
+
+// Children lists the fellowships directly beneath fellowshipId. Any member of the fellowship,
+// including admins inherited from a parent, may list them.
+func (f *FellowshipService) Children(ctx context.Context, user domain.User, fellowshipId uuid.UUID) ([]domain.Fellowship, error) {
+	if _, err := f.fellowshipStore.GetFellowship(ctx, fellowshipId); errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrFellowshipNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get fellowship %s: %w", fellowshipId, err)
+	}
+
+	accessLevel, err := f.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return nil, err
+	}
+
+	if accessLevel == domain.NoAccess {
+		return nil, domain.ErrInsufficientAccess
+	}
+
+	return f.fellowshipStore.GetChildFellowships(ctx, fellowshipId)
+}
+
+// SetParent moves a fellowship beneath parentId, or detaches it when parentId is nil.
+// The user must be able to manage both the fellowship and the new parent.
+func (f *FellowshipService) SetParent(ctx context.Context, user domain.User, fellowshipId uuid.UUID, parentId *uuid.UUID) error {
+	accessLevel, err := f.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if !canManage(accessLevel) {
+		return domain.ErrInsufficientAccess
+	}
+
+	if parentId != nil {
+		if *parentId == fellowshipId {
+			return domain.ErrInvalidFellowshipParent
+		}
+
+		if _, err := f.fellowshipStore.GetFellowship(ctx, *parentId); errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrFellowshipNotFound
+		} else if err != nil {
+			return fmt.Errorf("failed to get fellowship %s: %w", *parentId, err)
+		}
+
+		parentAccessLevel, err := f.accessLevel(ctx, user.Id, *parentId)
+		if err != nil {
+			return err
+		}
+
+		if !canManage(parentAccessLevel) {
+			return domain.ErrInsufficientAccess
+		}
+
+		ancestorIds, err := f.fellowshipStore.GetFellowshipAncestorIDs(ctx, *parentId)
+		if err != nil {
+			return fmt.Errorf("failed to get ancestors of fellowship %s: %w", *parentId, err)
+		}
+
+		height, err := f.fellowshipStore.GetFellowshipSubtreeHeight(ctx, fellowshipId)
+		if err != nil {
+			return fmt.Errorf("failed to get subtree height of fellowship %s: %w", fellowshipId, err)
+		}
+
+		// Refuse cycles and hierarchies deeper than the store will follow, counting the
+		// fellowships beneath the one being moved.
+		if len(ancestorIds)+1+height >= domain.FellowshipMaxDepth {
+			return domain.ErrInvalidFellowshipParent
+		}
+
+		for _, ancestorId := range ancestorIds {
+			if ancestorId == fellowshipId {
+				return domain.ErrInvalidFellowshipParent
+			}
+		}
+	}
+
+	if err := f.fellowshipStore.SetFellowshipParent(ctx, fellowshipId, parentId); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrFellowshipNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to set parent of fellowship %s: %w", fellowshipId, err)
+	}
+
+	return nil
+}
+
+// SetTimezone sets the time zone a fellowship the user manages keeps its events in.
+func (f *FellowshipService) SetTimezone(ctx context.Context, user domain.User, fellowshipId uuid.UUID, timezone string) error {
+	accessLevel, err := f.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if !canManage(accessLevel) {
+		return domain.ErrInsufficientAccess
+	}
+
+	// LoadLocation also accepts "Local", which means nothing to the fellowship's members.
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" || timezone == "Local" {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidTimezone, timezone)
+	}
+
+	if err := f.fellowshipStore.SetFellowshipTimezone(ctx, fellowshipId, timezone); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrFellowshipNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to set timezone of fellowship %s: %w", fellowshipId, err)
+	}
+
+	return nil
+}
+
+// SetDetails sets the description of a fellowship the user manages, and whether it is public, so
+// that anyone can find it in search and see its calendar.
+func (f *FellowshipService) SetDetails(ctx context.Context, user domain.User, fellowshipId uuid.UUID, description string, isPublic bool) error {
+	accessLevel, err := f.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if !canManage(accessLevel) {
+		return domain.ErrInsufficientAccess
+	}
+
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > domain.FellowshipDescriptionMaxLength {
+		return fmt.Errorf("%w: longer than %d characters", domain.ErrInvalidFellowshipDescription, domain.FellowshipDescriptionMaxLength)
+	}
+
+	if err := f.fellowshipStore.SetFellowshipDetails(ctx, fellowshipId, description, isPublic); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrFellowshipNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to set details of fellowship %s: %w", fellowshipId, err)
+	}
+
+	return nil
+}
+
+// FollowParentNotices opts the user in or out of seeing the Notices circles of the fellowship's ancestors in their feed.
+func (f *FellowshipService) FollowParentNotices(ctx context.Context, user domain.User, fellowshipId uuid.UUID, follow bool) error {
+	err := f.fellowshipStore.SetFollowParentNotices(ctx, fellowshipId, user.Id, follow)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrInsufficientAccess
+	} else if err != nil {
+		return fmt.Errorf("failed to update parent notices for fellowship %s: %w", fellowshipId, err)
+	}
+
+	return nil
+}
+
+// SetWorshipLeader makes a member of a fellowship the user manages a worship leader, who can edit the
+// fellowship's song library, or stops them being one.
+func (f *FellowshipService) SetWorshipLeader(ctx context.Context, user domain.User, fellowshipId uuid.UUID, userId uuid.UUID, leader bool) error {
+	accessLevel, err := f.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if !canManage(accessLevel) {
+		return domain.ErrInsufficientAccess
+	}
+
+	if err := f.fellowshipStore.SetWorshipLeader(ctx, fellowshipId, userId, leader); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotMember
+	} else if err != nil {
+		return fmt.Errorf("failed to set worship leader of fellowship %s: %w", fellowshipId, err)
+	}
+
+	return nil
+}
+
+// accessLevel returns the user's effective access level, treating non-members as NoAccess.
+func (f *FellowshipService) accessLevel(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (domain.AccessLevel, error) {
+	accessLevel, err := f.fellowshipStore.GetUserAccessLevel(ctx, userId, fellowshipId)
+	if errors.Is(err, domain.ErrNotMember) {
+		return domain.NoAccess, nil
+	} else if err != nil {
+		return domain.NoAccess, fmt.Errorf("unable to check user permissions for fellowship %s: %w", fellowshipId, err)
+	}
+
+	return accessLevel, nil
+}
+
+func canManage(accessLevel domain.AccessLevel) bool {
+	if accessLevel == domain.Owner || accessLevel == domain.Admin {
+		return true
+	}
+
+	return false
+}
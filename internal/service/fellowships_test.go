@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// hierarchyStore is a FellowshipStore of fellowships and their parents, all owned by every user.
+type hierarchyStore struct {
+	domain.FellowshipStore
+	parents map[uuid.UUID]*uuid.UUID
+}
+
+func (h *hierarchyStore) GetUserAccessLevel(ctx context.Context, userId uuid.UUID, fellowshipId uuid.UUID) (domain.AccessLevel, error) {
+	return domain.Owner, nil
+}
+
+func (h *hierarchyStore) GetFellowship(ctx context.Context, fellowshipId uuid.UUID) (*domain.Fellowship, error) {
+	if _, ok := h.parents[fellowshipId]; !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &domain.Fellowship{Id: fellowshipId, ParentId: h.parents[fellowshipId]}, nil
+}
+
+func (h *hierarchyStore) GetFellowshipAncestorIDs(ctx context.Context, fellowshipId uuid.UUID) ([]uuid.UUID, error) {
+	ancestorIds := make([]uuid.UUID, 0)
+	for parent := h.parents[fellowshipId]; parent != nil && len(ancestorIds) < domain.FellowshipMaxDepth; parent = h.parents[*parent] {
+		ancestorIds = append(ancestorIds, *parent)
+	}
+
+	return ancestorIds, nil
+}
+
+func (h *hierarchyStore) GetFellowshipSubtreeHeight(ctx context.Context, fellowshipId uuid.UUID) (int, error) {
+	height := 0
+	for id, parent := range h.parents {
+		if parent != nil && *parent == fellowshipId {
+			childHeight, _ := h.GetFellowshipSubtreeHeight(ctx, id)
+			height = max(height, childHeight+1)
+		}
+	}
+
+	return height, nil
+}
+
+func (h *hierarchyStore) SetFellowshipParent(ctx context.Context, fellowshipId uuid.UUID, parentId *uuid.UUID) error {
+	h.parents[fellowshipId] = parentId
+	return nil
+}
+
+func TestSetParentDepth(t *testing.T) {
+	// chain[i] is at depth i, and each fellowship of it is the parent of the next.
+	chain := make([]uuid.UUID, domain.FellowshipMaxDepth-1)
+	for i := range chain {
+		chain[i] = uuid.New()
+	}
+
+	newStore := func() *hierarchyStore {
+		store := &hierarchyStore{parents: make(map[uuid.UUID]*uuid.UUID)}
+		for i := range chain {
+			store.parents[chain[i]] = nil
+			if i > 0 {
+				store.parents[chain[i]] = &chain[i-1]
+			}
+		}
+
+		return store
+	}
+
+	// A fellowship of the given subtree height, with one chain of descendants.
+	addSubtree := func(store *hierarchyStore, height int) uuid.UUID {
+		root := uuid.New()
+		store.parents[root] = nil
+
+		parent := root
+		for range height {
+			child, childParent := uuid.New(), parent
+			store.parents[child] = &childParent
+			parent = child
+		}
+
+		return root
+	}
+
+	deepest := chain[len(chain)-1]
+
+	tests := []struct {
+		name    string
+		height  int
+		parent  *uuid.UUID
+		wantErr error
+	}{
+		{name: "leaf to the deepest level", height: 0, parent: &deepest},
+		{name: "subtree past the deepest level", height: 1, parent: &deepest, wantErr: domain.ErrInvalidFellowshipParent},
+		{name: "subtree to the deepest level", height: 1, parent: &chain[len(chain)-2]},
+		{name: "tall subtree under the top", height: domain.FellowshipMaxDepth - 2, parent: &chain[0]},
+		{name: "too tall subtree under the top", height: domain.FellowshipMaxDepth - 1, parent: &chain[0], wantErr: domain.ErrInvalidFellowshipParent},
+		{name: "tall subtree to the top level", height: domain.FellowshipMaxDepth - 1, parent: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := newStore()
+			fellowshipId := addSubtree(store, test.height)
+
+			err := NewFellowshipService(store).SetParent(context.Background(), domain.User{Id: uuid.New()}, fellowshipId, test.parent)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("SetParent = %v, want %v", err, test.wantErr)
+			}
+
+			if test.wantErr == nil && store.parents[fellowshipId] != test.parent {
+				t.Errorf("parent = %v, want %v", store.parents[fellowshipId], test.parent)
+			}
+		})
+	}
+}
+
+func TestSetParentRefusesCycles(t *testing.T) {
+	top, middle, bottom := uuid.New(), uuid.New(), uuid.New()
+	store := &hierarchyStore{parents: map[uuid.UUID]*uuid.UUID{top: nil, middle: &top, bottom: &middle}}
+	fellowships := NewFellowshipService(store)
+
+	for _, parent := range []uuid.UUID{top, middle, bottom} {
+		err := fellowships.SetParent(context.Background(), domain.User{Id: uuid.New()}, top, &parent)
+		if !errors.Is(err, domain.ErrInvalidFellowshipParent) {
+			t.Errorf("moving the top fellowship under %s = %v, want ErrInvalidFellowshipParent", parent, err)
+		}
+	}
+}
+
+// publicFellowship is a FellowshipStore of one public fellowship in a parent fellowship.
+type publicFellowship struct {
+	fellowshipAccess
+	fellowship domain.Fellowship
+}
+
+func (p publicFellowship) GetFellowship(ctx context.Context, fellowshipId uuid.UUID) (*domain.Fellowship, error) {
+	if fellowshipId != p.fellowship.Id {
+		return nil, sql.ErrNoRows
+	}
+
+	fellowship := p.fellowship
+	return &fellowship, nil
+}
+
+func TestGetPublicFellowship(t *testing.T) {
+	member, stranger := uuid.New(), uuid.New()
+	parentId := uuid.New()
+	fellowship := domain.Fellowship{Id: uuid.New(), CreatorId: member, ParentId: &parentId, Name: "Youth", Description: "Friday nights", IsPublic: true, Timezone: "Africa/Johannesburg"}
+	store := publicFellowship{fellowshipAccess: fellowshipAccess{access: map[uuid.UUID]domain.AccessLevel{member: domain.ReadOnly}}, fellowship: fellowship}
+
+	got, err := NewFellowshipService(store).Get(context.Background(), domain.User{Id: member}, fellowship.Id)
+	if err != nil || got.CreatorId != member || got.ParentId == nil || got.Timezone != fellowship.Timezone {
+		t.Errorf("Get for a member = %+v, %v, want the whole fellowship", got, err)
+	}
+
+	got, err = NewFellowshipService(store).Get(context.Background(), domain.User{Id: stranger}, fellowship.Id)
+	want := domain.Fellowship{Id: fellowship.Id, Name: fellowship.Name, Description: fellowship.Description, IsPublic: true}
+	if err != nil || *got != want {
+		t.Errorf("Get for a non-member = %+v, %v, want only %+v", got, err, want)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/imaging"
+	"github.com/google/uuid"
+)
+
+const (
+	// imageWorkers bounds how many images are decoded at once, and with it the memory they take.
+	imageWorkers = 2
+	// imageQueueSize bounds how many uploaded images wait for a worker. Images that do not fit are
+	// picked up by a later sweep.
+	imageQueueSize = 64
+	// imageUploadGrace is how long sweeps leave new images alone, as their upload may still be arriving.
+	imageUploadGrace = 10 * time.Minute
+)
+
+func NewImageProcessor(store domain.AttachmentStore, blobStore domain.BlobStore, logger *slog.Logger) *ImageProcessor {
+	return &ImageProcessor{attachmentStore: store, blobStore: blobStore, logger: logger, jobs: make(chan uuid.UUID, imageQueueSize), queued: make(map[uuid.UUID]struct{})}
+}
+
+// ImageProcessor cleans uploaded images and makes their web versions and thumbnails in the
+// background, so uploads return without waiting for the work.
+type ImageProcessor struct {
+	attachmentStore domain.AttachmentStore
+	blobStore       domain.BlobStore
+	logger          *slog.Logger
+	jobs            chan uuid.UUID
+
+	mu sync.Mutex
+	// queued holds the attachments waiting for or being processed, so sweeps do not add them twice.
+	queued map[uuid.UUID]struct{}
+}
+
+// Enqueue asks for an attachment to be processed without waiting for a free worker.
+func (p *ImageProcessor) Enqueue(attachmentId uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.queued[attachmentId]; ok {
+		return
+	}
+
+	select {
+	case p.jobs <- attachmentId:
+		p.queued[attachmentId] = struct{}{}
+	default:
+		p.logger.Warn("images: queue full, deferring image to the next sweep", "attachmentId", attachmentId)
+	}
+}
+
+// Run processes queued images until ctx is cancelled. Every interval it also queues images left
+// waiting, such as those uploaded before a restart.
+func (p *ImageProcessor) Run(ctx context.Context, interval time.Duration) {
+	var wg sync.WaitGroup
+	for range imageWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	defer wg.Wait()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		attachments, err := p.attachmentStore.GetProcessingAttachments(ctx, time.Now().Add(-imageUploadGrace), imageQueueSize)
+		if err != nil {
+			p.logger.Error("images: failed to get waiting images", "error", err)
+		}
+
+		for _, attachment := range attachments {
+			p.Enqueue(attachment.Id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ImageProcessor) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case attachmentId := <-p.jobs:
+			p.process(ctx, attachmentId)
+
+			p.mu.Lock()
+			delete(p.queued, attachmentId)
+			p.mu.Unlock()
+		}
+	}
+}
+
+// process turns the raw upload of an image into its cleaned original, web version and thumbnail.
+// Images that cannot be processed are marked failed; other errors leave them for a later sweep.
+func (p *ImageProcessor) process(ctx context.Context, attachmentId uuid.UUID) {
+	logger := p.logger.With("attachmentId", attachmentId)
+	uploadKey := attachmentKey(attachmentId, attachmentUpload)
+
+	content, err := p.blobStore.Get(ctx, uploadKey)
+	if errors.Is(err, domain.ErrBlobNotFound) {
+		logger.Error("images: upload missing")
+		p.fail(ctx, attachmentId)
+		return
+	} else if err != nil {
+		logger.Error("images: failed to open upload", "error", err)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, domain.AttachmentMaxSize+1))
+	content.Close()
+	if err != nil {
+		logger.Error("images: failed to read upload", "error", err)
+		return
+	}
+
+	result, err := imaging.Process(data)
+	if err != nil {
+		logger.Warn("images: rejected image", "error", err)
+		p.fail(ctx, attachmentId)
+		return
+	}
+
+	variants := map[domain.AttachmentVariant][]byte{
+		domain.AttachmentOriginal:  result.Original,
+		domain.AttachmentWeb:       result.Web,
+		domain.AttachmentThumbnail: result.Thumbnail,
+	}
+
+	attachment, err := p.attachmentStore.GetAttachment(ctx, attachmentId)
+	if err != nil {
+		logger.Error("images: failed to get attachment", "error", err)
+		return
+	}
+
+	for variant, data := range variants {
+		contentType := attachment.ContentType
+		if variant != domain.AttachmentOriginal {
+			contentType = imaging.VariantType(attachment.ContentType)
+		}
+
+		if err := p.blobStore.Put(ctx, attachmentKey(attachmentId, variant), bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+			logger.Error("images: failed to store image", "variant", variant, "error", err)
+			return
+		}
+	}
+
+	// The web version and thumbnail count against the quota along with the original.
+	stored := int64(len(result.Original) + len(result.Web) + len(result.Thumbnail))
+	err = p.attachmentStore.FinishAttachment(ctx, attachmentId, domain.AttachmentReady, int64(len(result.Original)), stored, result.Width, result.Height)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted while it was being processed.
+		p.deleteBlobs(ctx, attachmentId)
+		return
+	} else if err != nil {
+		logger.Error("images: failed to finish attachment", "error", err)
+		return
+	}
+
+	if err := p.blobStore.Delete(ctx, uploadKey); err != nil {
+		logger.Error("images: failed to delete upload", "error", err)
+	}
+}
+
+// fail marks an image that cannot be processed as failed, which frees the storage it held.
+func (p *ImageProcessor) fail(ctx context.Context, attachmentId uuid.UUID) {
+	err := p.attachmentStore.FinishAttachment(ctx, attachmentId, domain.AttachmentFailed, 0, 0, 0, 0)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		p.logger.Error("images: failed to mark image failed", "attachmentId", attachmentId, "error", err)
+		return
+	}
+
+	p.deleteBlobs(ctx, attachmentId)
+}
+
+func (p *ImageProcessor) deleteBlobs(ctx context.Context, attachmentId uuid.UUID) {
+	for _, variant := range attachmentBlobs {
+		if err := p.blobStore.Delete(ctx, attachmentKey(attachmentId, variant)); err != nil {
+			p.logger.Error("images: failed to delete blob", "attachmentId", attachmentId, "variant", variant, "error", err)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/contentcheck"
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// NewModerationService creates a moderation service. Members report what feedService lets them see,
+// and approving a held post goes through feedService so the members it mentions are told.
+func NewModerationService(store domain.ModerationStore, commentStore domain.CommentStore, feedStore domain.FeedStore, fellowshipStore domain.FellowshipStore, feedService *FeedService, notifications *NotificationService, logger *slog.Logger) *ModerationService {
+	return &ModerationService{moderationStore: store, commentStore: commentStore, feedStore: feedStore, fellowshipStore: fellowshipStore, feedService: feedService, notifications: notifications, logger: logger}
+}
+
+// ModerationService queues members' reports of posts and comments for the moderators of each
+// fellowship and records what the moderators do about them.
+type ModerationService struct {
+	moderationStore domain.ModerationStore
+	commentStore    domain.CommentStore
+	feedStore       domain.FeedStore
+	fellowshipStore domain.FellowshipStore
+	feedService     *FeedService
+	notifications   *NotificationService
+	logger          *slog.Logger
+}
+
+// Report queues a report on a post, or on a comment on it when commentId is set, for the moderators
+// of the post's fellowship. Members cannot report their own content.
+func (m *ModerationService) Report(ctx context.Context, user domain.User, postId uuid.UUID, commentId *uuid.UUID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > domain.ReportReasonMaxLength {
+		return fmt.Errorf("%w: a reason of up to %d characters is required", domain.ErrInvalidReport, domain.ReportReasonMaxLength)
+	}
+
+	var comment *domain.Comment
+	if commentId != nil {
+		var err error
+		comment, err = m.commentStore.GetComment(ctx, *commentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrCommentNotFound
+		} else if err != nil {
+			return fmt.Errorf("failed to get comment %s: %w", *commentId, err)
+		}
+
+		postId = comment.PostId
+	}
+
+	post, err := m.feedService.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if post.State != domain.PostPublished {
+		return domain.ErrPostNotFound
+	}
+
+	if err := m.feedService.checkCanView(ctx, user, *post); err != nil {
+		return err
+	}
+
+	authorId := post.AuthorId
+	if comment != nil {
+		if comment.Hidden != nil {
+			// Hidden comments have already been dealt with, and only moderators can see them.
+			return domain.ErrCommentNotFound
+		}
+
+		authorId = comment.AuthorId
+	}
+
+	if authorId == user.Id {
+		return fmt.Errorf("%w: members cannot report their own content", domain.ErrInvalidReport)
+	}
+
+	fellowshipId, err := m.feedService.postFellowshipId(ctx, *post)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate report ID: %v", err)
+	}
+
+	report := domain.Report{Id: id, FellowshipId: fellowshipId, ReporterId: &user.Id, PostId: post.Id, CommentId: commentId, AuthorId: authorId, Reason: reason, Created: time.Now(), Status: domain.ReportOpen}
+	return m.moderationStore.CreateReport(ctx, report)
+}
+
+// Reports returns a page of the open reports of a fellowship the user moderates, oldest first.
+func (m *ModerationService) Reports(ctx context.Context, user domain.User, fellowshipId uuid.UUID, limit *int, cursor string) ([]domain.Report, string, error) {
+	if _, err := m.checkFellowshipModerator(ctx, user, fellowshipId); err != nil {
+		return nil, "", err
+	}
+
+	var position *domain.ModerationCursor
+	if cursor != "" {
+		position = &domain.ModerationCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	reports, next, err := m.moderationStore.GetOpenReports(ctx, fellowshipId, limit, position)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get reports for fellowship %s: %w", fellowshipId, err)
+	}
+
+	nextCursor, err := encodeModerationCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return reports, nextCursor, nil
+}
+
+// Log returns a page of the moderator actions taken in a fellowship the user moderates, newest first.
+func (m *ModerationService) Log(ctx context.Context, user domain.User, fellowshipId uuid.UUID, limit *int, cursor string) ([]domain.ModerationLogEntry, string, error) {
+	if _, err := m.checkFellowshipModerator(ctx, user, fellowshipId); err != nil {
+		return nil, "", err
+	}
+
+	var position *domain.ModerationCursor
+	if cursor != "" {
+		position = &domain.ModerationCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	entries, next, err := m.moderationStore.GetModerationLog(ctx, fellowshipId, limit, position)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get moderation log for fellowship %s: %w", fellowshipId, err)
+	}
+
+	nextCursor, err := encodeModerationCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return entries, nextCursor, nil
+}
+
+// Act resolves an open report with a moderator action, which is recorded in the fellowship's audit
+// trail along with the moderator's note. Other open reports on the same content are resolved with it.
+// Moderators can only remove members less privileged than themselves.
+func (m *ModerationService) Act(ctx context.Context, user domain.User, reportId uuid.UUID, action domain.ModerationAction, note string) error {
+	if !action.IsValid() {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidModerationAction, action)
+	}
+
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > domain.ModerationNoteMaxLength {
+		return fmt.Errorf("%w: note is too long", domain.ErrInvalidModerationAction)
+	}
+
+	report, err := m.moderationStore.GetReport(ctx, reportId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrReportNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to get report %s: %w", reportId, err)
+	}
+
+	accessLevel, err := m.checkFellowshipModerator(ctx, user, report.FellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if report.Status != domain.ReportOpen {
+		return domain.ErrReportResolved
+	}
+
+	// Only members with a membership here are removed; those with access only through an ancestor
+	// fellowship have none to remove.
+	removeAuthor := false
+	if action == domain.ModerationRemove {
+		if report.AuthorId == user.Id {
+			return fmt.Errorf("%w: moderators cannot remove themselves", domain.ErrInvalidModerationAction)
+		}
+
+		authorAccess, err := m.fellowshipStore.GetUserAccessLevel(ctx, report.AuthorId, report.FellowshipId)
+		if err != nil && !errors.Is(err, domain.ErrNotMember) {
+			return fmt.Errorf("unable to check user permissions for fellowship %s: %w", report.FellowshipId, err)
+		}
+
+		if err == nil && authorAccess <= accessLevel {
+			return fmt.Errorf("user %s cannot remove user %s from fellowship %s: %w", user.Id, report.AuthorId, report.FellowshipId, domain.ErrInsufficientAccess)
+		}
+
+		removeAuthor = err == nil
+	}
+
+	now := time.Now()
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate moderation log ID: %v", err)
+	}
+
+	entry := domain.ModerationLogEntry{Id: id, FellowshipId: report.FellowshipId, ModeratorId: user.Id, Action: action, ReportId: &report.Id, TargetUserId: report.AuthorId,
+		PostId: &report.PostId, CommentId: report.CommentId, Note: note, Created: now}
+
+	// Claim the report and record the action before acting on it, so that moderators acting on it at
+	// the same time cannot both act and no action goes unrecorded.
+	err = m.moderationStore.ResolveReport(ctx, entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrReportResolved
+	} else if err != nil {
+		return fmt.Errorf("failed to resolve report %s: %w", reportId, err)
+	}
+
+	if err := m.apply(ctx, user, *report, action, note, removeAuthor, now); err != nil {
+		if err := m.moderationStore.ReopenReport(ctx, entry); err != nil {
+			m.logger.Error("moderation: failed to reopen report", "reportId", report.Id, "error", err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// apply carries out a moderator action on the content or author of a claimed report.
+func (m *ModerationService) apply(ctx context.Context, user domain.User, report domain.Report, action domain.ModerationAction, note string, removeAuthor bool, now time.Time) error {
+	switch action {
+	case domain.ModerationHide:
+		var err error
+		if report.CommentId != nil {
+			err = m.commentStore.HideComment(ctx, *report.CommentId, user.Id, now)
+		} else {
+			err = m.feedStore.HidePost(ctx, report.PostId, user.Id, now)
+		}
+
+		// Content deleted since it was reported needs no hiding.
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to hide reported content: %w", err)
+		}
+
+	case domain.ModerationRemove:
+		if !removeAuthor {
+			return nil
+		}
+
+		err := m.fellowshipStore.RemoveFellowshipMember(ctx, report.FellowshipId, report.AuthorId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to remove user %s from fellowship %s: %w", report.AuthorId, report.FellowshipId, err)
+		}
+
+	case domain.ModerationWarn:
+		m.warn(ctx, report, note)
+
+	case domain.ModerationDismiss:
+		// Dismissing the report on a post held by the content checks approves the post.
+		if report.ReporterId == nil {
+			return m.feedService.approveHeldPost(ctx, report.PostId)
+		}
+	}
+
+	return nil
+}
+
+// BlockedWords returns the blocked words of a fellowship the user moderates.
+func (m *ModerationService) BlockedWords(ctx context.Context, user domain.User, fellowshipId uuid.UUID) ([]domain.BlockedWord, error) {
+	if _, err := m.checkFellowshipModerator(ctx, user, fellowshipId); err != nil {
+		return nil, err
+	}
+
+	words, err := m.moderationStore.GetBlockedWords(ctx, fellowshipId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked words for fellowship %s: %w", fellowshipId, err)
+	}
+
+	return words, nil
+}
+
+// SetBlockedWords replaces the blocked words of a fellowship the user manages. Words are stored the way
+// the content checks compare them: in lower case without punctuation.
+func (m *ModerationService) SetBlockedWords(ctx context.Context, user domain.User, fellowshipId uuid.UUID, words []domain.BlockedWord) error {
+	accessLevel, err := m.fellowshipStore.GetUserAccessLevel(ctx, user.Id, fellowshipId)
+	if errors.Is(err, domain.ErrNotMember) {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("unable to check user permissions for fellowship %s: %w", fellowshipId, err)
+	}
+
+	if !canManage(accessLevel) {
+		return fmt.Errorf("user %s cannot manage fellowship %s: %w", user.Id, fellowshipId, domain.ErrInsufficientAccess)
+	}
+
+	if len(words) > domain.BlockedWordsMax {
+		return fmt.Errorf("%w: at most %d words can be blocked", domain.ErrInvalidBlockedWord, domain.BlockedWordsMax)
+	}
+
+	normalized := make([]domain.BlockedWord, 0, len(words))
+	for _, word := range words {
+		text := contentcheck.NormalizeText(word.Word)
+		if text == "" || len(text) > domain.BlockedWordMaxLength {
+			return fmt.Errorf("%w: %q", domain.ErrInvalidBlockedWord, word.Word)
+		}
+
+		if word.Outcome != domain.ContentHold && word.Outcome != domain.ContentReject {
+			return fmt.Errorf("%w: outcome of %q must be hold or reject", domain.ErrInvalidBlockedWord, word.Word)
+		}
+
+		normalized = append(normalized, domain.BlockedWord{Word: text, Outcome: word.Outcome})
+	}
+
+	if err := m.moderationStore.SetBlockedWords(ctx, fellowshipId, normalized); err != nil {
+		return fmt.Errorf("failed to set blocked words for fellowship %s: %w", fellowshipId, err)
+	}
+
+	return nil
+}
+
+// warn emails the author of reported content a warning in the background.
+func (m *ModerationService) warn(ctx context.Context, report domain.Report, note string) {
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+
+		fellowship, err := m.fellowshipStore.GetFellowship(ctx, report.FellowshipId)
+		if err != nil {
+			m.logger.Error("moderation: failed to get fellowship for warning", "reportId", report.Id, "error", err)
+			return
+		}
+
+		what := "a post"
+		if report.CommentId != nil {
+			what = "a comment"
+		}
+
+		content := fmt.Sprintf("<p>The moderators of %s have reviewed %s of yours and are warning you about it.</p>", html.EscapeString(fellowship.Name), what)
+		if note != "" {
+			content += fmt.Sprintf("<p>%s</p>", html.EscapeString(note))
+		}
+
+		if err := m.notifications.EmailUsers(ctx, []uuid.UUID{report.AuthorId}, "A warning from the moderators of "+fellowship.Name, content); err != nil {
+			m.logger.Error("moderation: failed to send warning", "reportId", report.Id, "error", err)
+		}
+	}()
+}
+
+// checkFellowshipModerator returns the user's access to a fellowship they moderate.
+func (m *ModerationService) checkFellowshipModerator(ctx context.Context, user domain.User, fellowshipId uuid.UUID) (domain.AccessLevel, error) {
+	accessLevel, err := m.fellowshipStore.GetUserAccessLevel(ctx, user.Id, fellowshipId)
+	if errors.Is(err, domain.ErrNotMember) {
+		return domain.NoAccess, err
+	} else if err != nil {
+		return domain.NoAccess, fmt.Errorf("unable to check user permissions for fellowship %s: %w", fellowshipId, err)
+	}
+
+	if !canModerate(accessLevel) {
+		return domain.NoAccess, fmt.Errorf("user %s cannot moderate fellowship %s: %w", user.Id, fellowshipId, domain.ErrInsufficientAccess)
+	}
+
+	return accessLevel, nil
+}
+
+func encodeModerationCursor(next *domain.ModerationCursor) (string, error) {
+	if next == nil {
+		return "", nil
+	}
+
+	return domain.EncodeCursor(next)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// reportQueue is a ModerationStore of one report, read as it was before anyone acted on it, as
+// moderators acting at the same time read it.
+type reportQueue struct {
+	domain.ModerationStore
+	report domain.Report
+	status domain.ReportStatus
+	log    []domain.ModerationLogEntry
+}
+
+func (r *reportQueue) GetReport(ctx context.Context, reportId uuid.UUID) (*domain.Report, error) {
+	if reportId != r.report.Id {
+		return nil, sql.ErrNoRows
+	}
+
+	report := r.report
+	return &report, nil
+}
+
+func (r *reportQueue) ResolveReport(ctx context.Context, entry domain.ModerationLogEntry) error {
+	if *entry.ReportId != r.report.Id || r.status != domain.ReportOpen {
+		return sql.ErrNoRows
+	}
+
+	r.status = domain.ReportActioned
+	r.log = append(r.log, entry)
+	return nil
+}
+
+func (r *reportQueue) ReopenReport(ctx context.Context, entry domain.ModerationLogEntry) error {
+	r.status = domain.ReportOpen
+	r.log = slices.DeleteFunc(r.log, func(logged domain.ModerationLogEntry) bool { return logged.Id == entry.Id })
+	return nil
+}
+
+// hiddenPosts counts the posts hidden in it, failing to hide them if err is set.
+type hiddenPosts struct {
+	domain.FeedStore
+	hidden int
+	err    error
+}
+
+func (h *hiddenPosts) HidePost(ctx context.Context, postId uuid.UUID, hiddenBy uuid.UUID, hidden time.Time) error {
+	if h.err != nil {
+		return h.err
+	}
+
+	h.hidden++
+	return nil
+}
+
+func newReportQueue(fellowshipId uuid.UUID) *reportQueue {
+	reporterId := uuid.New()
+	return &reportQueue{
+		report: domain.Report{Id: uuid.New(), FellowshipId: fellowshipId, PostId: uuid.New(), AuthorId: uuid.New(), ReporterId: &reporterId, Status: domain.ReportOpen},
+		status: domain.ReportOpen,
+	}
+}
+
+func TestActOnce(t *testing.T) {
+	fellowshipId := uuid.New()
+	first, second := uuid.New(), uuid.New()
+	fellowships := fellowshipAccess{access: map[uuid.UUID]domain.AccessLevel{first: domain.Moderator, second: domain.Moderator}}
+	reports := newReportQueue(fellowshipId)
+	posts := &hiddenPosts{}
+	moderation := NewModerationService(reports, nil, posts, fellowships, &FeedService{feedStore: posts, fellowshipStore: fellowships}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := moderation.Act(context.Background(), domain.User{Id: first}, reports.report.Id, domain.ModerationHide, ""); err != nil {
+		t.Fatalf("first Act = %v", err)
+	}
+
+	if err := moderation.Act(context.Background(), domain.User{Id: second}, reports.report.Id, domain.ModerationHide, ""); !errors.Is(err, domain.ErrReportResolved) {
+		t.Fatalf("second Act = %v, want ErrReportResolved", err)
+	}
+
+	if posts.hidden != 1 {
+		t.Errorf("post hidden %d times, want once", posts.hidden)
+	}
+
+	if len(reports.log) != 1 || reports.log[0].ModeratorId != first {
+		t.Errorf("moderation log = %v, want the first moderator's action only", reports.log)
+	}
+}
+
+func TestActReopensOnFailure(t *testing.T) {
+	fellowshipId := uuid.New()
+	moderator := uuid.New()
+	fellowships := fellowshipAccess{access: map[uuid.UUID]domain.AccessLevel{moderator: domain.Moderator}}
+	reports := newReportQueue(fellowshipId)
+	posts := &hiddenPosts{err: errors.New("connection lost")}
+	moderation := NewModerationService(reports, nil, posts, fellowships, &FeedService{feedStore: posts, fellowshipStore: fellowships}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := moderation.Act(context.Background(), domain.User{Id: moderator}, reports.report.Id, domain.ModerationHide, ""); err == nil {
+		t.Fatal("Act succeeded without hiding the post")
+	}
+
+	if reports.status != domain.ReportOpen {
+		t.Errorf("report status = %s, want it reopened", reports.status)
+	}
+
+	if len(reports.log) != 0 {
+		t.Errorf("moderation log = %v, want no action recorded", reports.log)
+	}
+}
+
+func TestActNoteLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		note    string
+		wantErr error
+	}{
+		{name: "multi-byte characters at the limit", note: strings.Repeat("é", domain.ModerationNoteMaxLength)},
+		{name: "over the limit", note: strings.Repeat("é", domain.ModerationNoteMaxLength+1), wantErr: domain.ErrInvalidModerationAction},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moderator := uuid.New()
+			fellowships := fellowshipAccess{access: map[uuid.UUID]domain.AccessLevel{moderator: domain.Moderator}}
+			reports := newReportQueue(uuid.New())
+			posts := &hiddenPosts{}
+			moderation := NewModerationService(reports, nil, posts, fellowships, &FeedService{feedStore: posts, fellowshipStore: fellowships}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			err := moderation.Act(context.Background(), domain.User{Id: moderator}, reports.report.Id, domain.ModerationHide, test.note)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Act = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+func NewNotificationService(userStore domain.UserStore, mailService *MailService, logger *slog.Logger) *NotificationService {
+	return &NotificationService{userStore: userStore, mailService: mailService, logger: logger}
+}
+
+// NotificationService emails members about activity that needs their attention.
+type NotificationService struct {
+	userStore   domain.UserStore
+	mailService *MailService
+	logger      *slog.Logger
+}
+
+// EmailUsers sends the same email to each user with an email address. A failed send is logged and
+// does not stop the others.
+func (n *NotificationService) EmailUsers(ctx context.Context, userIds []uuid.UUID, subject, content string) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+
+	contacts, err := n.userStore.GetUserContacts(ctx, userIds)
+	if err != nil {
+		return fmt.Errorf("failed to get user contacts: %w", err)
+	}
+
+	for _, contact := range contacts {
+		if err := n.mailService.SendNoReplyEmail(contact.DisplayName, contact.Email, subject, content); err != nil {
+			n.logger.Error("notifications: failed to email user", "userId", contact.UserId, "error", err)
+		}
+	}
+
+	return nil
+}
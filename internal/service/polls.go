@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// NewPollService creates a poll service. Members vote on the polls of published posts feedService
+// lets them see.
+func NewPollService(store domain.PollStore, feedService *FeedService) *PollService {
+	return &PollService{pollStore: store, feedService: feedService}
+}
+
+// PollService takes and counts the votes on the polls of posts.
+type PollService struct {
+	pollStore   domain.PollStore
+	feedService *FeedService
+}
+
+// Vote casts the user's one vote on a post's poll. Single choice polls take exactly one option.
+func (p *PollService) Vote(ctx context.Context, user domain.User, postId uuid.UUID, options []int) error {
+	post, err := p.getPoll(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if post.Poll.IsClosed(now) {
+		return domain.ErrPollClosed
+	}
+
+	if len(options) == 0 || (!post.Poll.MultipleChoice && len(options) > 1) {
+		return fmt.Errorf("%w: choose one option", domain.ErrInvalidVote)
+	}
+
+	options = slices.Clone(options)
+	slices.Sort(options)
+	for i, option := range options {
+		if option < 0 || option >= len(post.Poll.Options) || (i > 0 && options[i-1] == option) {
+			return fmt.Errorf("%w: option %d", domain.ErrInvalidVote, option)
+		}
+	}
+
+	return p.pollStore.AddPollVote(ctx, domain.PollVote{PostId: postId, UserId: user.Id, Options: options, Voted: now})
+}
+
+// Results counts the votes on a post's poll. Who voted for each option is only shown for polls that
+// are not anonymous.
+func (p *PollService) Results(ctx context.Context, user domain.User, postId uuid.UUID) (*domain.PollResults, error) {
+	post, err := p.getPoll(ctx, user, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := p.pollStore.GetPollVotes(ctx, postId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes on post %s: %w", postId, err)
+	}
+
+	results := &domain.PollResults{Options: make([]domain.PollOptionResult, len(post.Poll.Options)), Voters: len(votes), Closed: post.Poll.IsClosed(time.Now()), MyVote: []int{}}
+	for i, option := range post.Poll.Options {
+		results.Options[i].Option = option
+	}
+
+	for _, vote := range votes {
+		if vote.UserId == user.Id {
+			results.MyVote = vote.Options
+		}
+
+		for _, option := range vote.Options {
+			if option < 0 || option >= len(results.Options) {
+				continue
+			}
+
+			results.Options[option].Count++
+			if !post.Poll.Anonymous {
+				results.Options[option].VoterIDs = append(results.Options[option].VoterIDs, vote.UserId)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// getPoll returns a published post with a poll that the user can see.
+func (p *PollService) getPoll(ctx context.Context, user domain.User, postId uuid.UUID) (*domain.Post, error) {
+	post, err := p.feedService.getPost(ctx, user, postId)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.State != domain.PostPublished {
+		return nil, domain.ErrPostNotFound
+	}
+
+	if err := p.feedService.checkCanView(ctx, user, *post); err != nil {
+		return nil, err
+	}
+
+	if post.Poll == nil {
+		return nil, domain.ErrPollNotFound
+	}
+
+	return post, nil
+}
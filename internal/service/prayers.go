@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"time"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+)
+
+// prayerFollowUpBatchSize bounds how many reminders are sent in one pass.
+const prayerFollowUpBatchSize = 100
+
+// prayerFollowUpRetryDelay is how long a reminder that failed to send waits before it is tried again.
+const prayerFollowUpRetryDelay = 6 * time.Hour
+
+func NewPrayerReminderService(store domain.FeedStore, mailService *MailService, logger *slog.Logger) *PrayerReminderService {
+	return &PrayerReminderService{feedStore: store, mailService: mailService, logger: logger}
+}
+
+// PrayerReminderService emails the authors of active prayer requests once their follow-up date passes.
+type PrayerReminderService struct {
+	feedStore   domain.FeedStore
+	mailService *MailService
+	logger      *slog.Logger
+}
+
+// Run sends due reminders every interval until ctx is cancelled.
+func (p *PrayerReminderService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.SendDue(ctx); err != nil {
+			p.logger.Error("prayer reminders: failed to send", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends the reminders that are due. Reminders that fail to send are retried once
+// prayerFollowUpRetryDelay has passed, so they do not hold up the others.
+func (p *PrayerReminderService) SendDue(ctx context.Context) error {
+	now := time.Now()
+	followUps, err := p.feedStore.GetDuePrayerFollowUps(ctx, now, now.Add(-prayerFollowUpRetryDelay), prayerFollowUpBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due prayer follow-ups: %w", err)
+	}
+
+	for _, followUp := range followUps {
+		content := fmt.Sprintf("<p>Hi %s,</p><p>Any update on your prayer request <strong>%s</strong>? Let your fellowship know how they can keep praying, or share how it was answered.</p>",
+			html.EscapeString(followUp.AuthorName), html.EscapeString(followUp.Heading))
+
+		if err := p.mailService.SendNoReplyEmail(followUp.AuthorName, followUp.AuthorEmail, "Any update on your prayer request?", content); err != nil {
+			p.logger.Error("prayer reminders: failed to email author", "postId", followUp.PostId, "error", err)
+
+			if err := p.feedStore.MarkPrayerFollowUpFailed(ctx, followUp.PostId, now); err != nil {
+				return fmt.Errorf("failed to mark prayer follow-up %s failed: %w", followUp.PostId, err)
+			}
+
+			continue
+		}
+
+		if err := p.feedStore.MarkPrayerFollowUpSent(ctx, followUp.PostId); err != nil {
+			return fmt.Errorf("failed to mark prayer follow-up %s sent: %w", followUp.PostId, err)
+		}
+	}
+
+	return nil
+}
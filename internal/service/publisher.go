@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func NewPostPublisher(feedService *FeedService, logger *slog.Logger) *PostPublisher {
+	return &PostPublisher{feedService: feedService, logger: logger}
+}
+
+// PostPublisher publishes scheduled posts in the background once their time comes.
+type PostPublisher struct {
+	feedService *FeedService
+	logger      *slog.Logger
+}
+
+// Run publishes due posts every interval until ctx is cancelled.
+func (p *PostPublisher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if published, err := p.feedService.PublishDue(ctx); err != nil {
+			p.logger.Error("publisher: failed to publish scheduled posts", "error", err)
+		} else if published > 0 {
+			p.logger.Info("publisher: published scheduled posts", "count", published)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
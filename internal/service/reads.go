@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// NewReadService creates a read tracking service. Unread counts cover the fellowships and circles
+// feedService shows in the user's feed.
+func NewReadService(store domain.ReadStore, feedService *FeedService) *ReadService {
+	return &ReadService{readStore: store, feedService: feedService}
+}
+
+// ReadService tracks how far each user has read the posts of their fellowships and circles.
+type ReadService struct {
+	readStore   domain.ReadStore
+	feedService *FeedService
+}
+
+// MarkRead marks a post and everything before it in its fellowship or circle as read by the user.
+// Marking an older post than one already read changes nothing.
+func (r *ReadService) MarkRead(ctx context.Context, user domain.User, postId uuid.UUID) error {
+	post, err := r.feedService.getPost(ctx, user, postId)
+	if err != nil {
+		return err
+	}
+
+	if post.State != domain.PostPublished {
+		return domain.ErrPostNotFound
+	}
+
+	if err := r.feedService.checkCanView(ctx, user, *post); err != nil {
+		return err
+	}
+
+	containerId := post.FellowshipId
+	if post.CircleId != uuid.Nil {
+		containerId = post.CircleId
+	}
+
+	marker := domain.ReadMarker{UserId: user.Id, ContainerId: containerId, Posted: post.Posted, PostId: post.Id}
+	if err := r.readStore.MarkRead(ctx, marker); err != nil {
+		return fmt.Errorf("failed to mark post %s read: %w", postId, err)
+	}
+
+	return nil
+}
+
+// Unread returns the number of unread posts in each fellowship and circle of the user's feed.
+func (r *ReadService) Unread(ctx context.Context, user domain.User) ([]domain.UnreadCount, error) {
+	filter, err := r.feedService.postFilter(ctx, user, domain.FeedFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := r.readStore.GetUnreadCounts(ctx, user.Id, filter.FellowshipIDs, filter.CircleIDs, domain.UnreadCountMax)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread posts: %w", err)
+	}
+
+	return counts, nil
+}
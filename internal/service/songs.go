@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/FillipMatthew/ToolsOfWorship-Server/internal/domain"
+	"github.com/google/uuid"
+)
+
+// NewSongService creates a song service that checks fellowship access through fellowshipService.
+func NewSongService(store domain.SongStore, fellowshipStore domain.FellowshipStore, fellowshipService *FellowshipService) *SongService {
+	return &SongService{songStore: store, fellowshipStore: fellowshipStore, fellowshipService: fellowshipService}
+}
+
+// SongService keeps each fellowship's song library. Members can browse it, and its Admins and
+// worship leaders can edit it.
+type SongService struct {
+	songStore         domain.SongStore
+	fellowshipStore   domain.FellowshipStore
+	fellowshipService *FellowshipService
+}
+
+// Create adds a song to a fellowship's library.
+func (s *SongService) Create(ctx context.Context, user domain.User, input domain.SongInput) (*domain.Song, error) {
+	if err := s.checkCanEdit(ctx, user, input.FellowshipId); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate song ID: %v", err)
+	}
+
+	song := domain.Song{Id: id, FellowshipId: input.FellowshipId, CreatorId: user.Id, Created: time.Now()}
+	if err := setSongDetails(&song, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.songStore.CreateSong(ctx, song); err != nil {
+		return nil, err
+	}
+
+	return &song, nil
+}
+
+// Get returns a song with its lyrics.
+func (s *SongService) Get(ctx context.Context, user domain.User, songId uuid.UUID) (*domain.Song, error) {
+	song, err := s.getSong(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanView(ctx, user, song.FellowshipId); err != nil {
+		return nil, err
+	}
+
+	return song, nil
+}
+
+// Search lists a fellowship's songs matching filter, without their lyrics. cursor is the opaque
+// value returned as the next cursor of a previous page, or empty for the first page.
+func (s *SongService) Search(ctx context.Context, user domain.User, fellowshipId uuid.UUID, filter domain.SongFilter, limit *int, cursor string) ([]domain.Song, string, error) {
+	if utf8.RuneCountInString(filter.Query) > domain.SearchQueryMaxLength {
+		return nil, "", domain.ErrInvalidSearchQuery
+	}
+
+	if filter.Theme != "" {
+		theme, ok := normalizeTheme(filter.Theme)
+		if !ok {
+			return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidTag, filter.Theme)
+		}
+
+		filter.Theme = theme
+	}
+
+	if err := s.checkCanView(ctx, user, fellowshipId); err != nil {
+		return nil, "", err
+	}
+
+	var position *domain.SongSearchCursor
+	if cursor != "" {
+		position = &domain.SongSearchCursor{}
+		if err := domain.DecodeCursor(cursor, position); err != nil {
+			return nil, "", err
+		}
+	}
+
+	songs, next, err := s.songStore.SearchSongs(ctx, fellowshipId, filter, limit, position)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if next == nil {
+		return songs, "", nil
+	}
+
+	nextCursor, err := domain.EncodeCursor(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return songs, nextCursor, nil
+}
+
+// Edit replaces a song's details. The song stays in its fellowship's library.
+func (s *SongService) Edit(ctx context.Context, user domain.User, songId uuid.UUID, input domain.SongInput) (*domain.Song, error) {
+	song, err := s.getSong(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, user, song.FellowshipId); err != nil {
+		return nil, err
+	}
+
+	if err := setSongDetails(song, input); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	song.Edited = &now
+
+	if err := s.songStore.UpdateSong(ctx, *song); errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSongNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return song, nil
+}
+
+// Delete removes a song from its fellowship's library.
+func (s *SongService) Delete(ctx context.Context, user domain.User, songId uuid.UUID) error {
+	song, err := s.getSong(ctx, songId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCanEdit(ctx, user, song.FellowshipId); err != nil {
+		return err
+	}
+
+	if err := s.songStore.DeleteSong(ctx, songId, time.Now()); errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSongNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to delete song %s: %w", songId, err)
+	}
+
+	return nil
+}
+
+func (s *SongService) getSong(ctx context.Context, songId uuid.UUID) (*domain.Song, error) {
+	song, err := s.songStore.GetSong(ctx, songId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSongNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get song %s: %w", songId, err)
+	}
+
+	return song, nil
+}
+
+// checkCanView returns ErrNotMember unless the user has access to the fellowship.
+func (s *SongService) checkCanView(ctx context.Context, user domain.User, fellowshipId uuid.UUID) error {
+	accessLevel, err := s.fellowshipService.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if accessLevel == domain.NoAccess {
+		return domain.ErrNotMember
+	}
+
+	return nil
+}
+
+// checkCanEdit allows the fellowship's Owners and Admins, including those inherited from a parent
+// fellowship, and its worship leaders.
+func (s *SongService) checkCanEdit(ctx context.Context, user domain.User, fellowshipId uuid.UUID) error {
+	accessLevel, err := s.fellowshipService.accessLevel(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return err
+	}
+
+	if accessLevel == domain.NoAccess {
+		return domain.ErrNotMember
+	} else if canManage(accessLevel) {
+		return nil
+	}
+
+	leader, err := s.fellowshipStore.IsWorshipLeader(ctx, user.Id, fellowshipId)
+	if err != nil {
+		return fmt.Errorf("unable to check worship leaders of fellowship %s: %w", fellowshipId, err)
+	}
+
+	if !leader {
+		return fmt.Errorf("user %s cannot edit songs of fellowship %s: %w", user.Id, fellowshipId, domain.ErrInsufficientAccess)
+	}
+
+	return nil
+}
+
+// setSongDetails validates input and copies it to song.
+func setSongDetails(song *domain.Song, input domain.SongInput) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" || utf8.RuneCountInString(title) > domain.SongTitleMaxLength {
+		return fmt.Errorf("%w: title must be between 1 and %d characters", domain.ErrInvalidSong, domain.SongTitleMaxLength)
+	}
+
+	if len(input.Authors) > domain.SongMaxAuthors {
+		return fmt.Errorf("%w: more than %d authors", domain.ErrInvalidSong, domain.SongMaxAuthors)
+	}
+
+	authors := make([]string, 0, len(input.Authors))
+	for _, author := range input.Authors {
+		author = strings.TrimSpace(author)
+		if author == "" || utf8.RuneCountInString(author) > domain.SongAuthorMaxLength {
+			return fmt.Errorf("%w: author names must be between 1 and %d characters", domain.ErrInvalidSong, domain.SongAuthorMaxLength)
+		}
+
+		authors = append(authors, author)
+	}
+
+	ccliNumber := strings.TrimSpace(input.CCLINumber)
+	if len(ccliNumber) > domain.SongCCLINumberMaxLength || strings.IndexFunc(ccliNumber, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return fmt.Errorf("%w: CCLI number %q", domain.ErrInvalidSong, input.CCLINumber)
+	}
+
+	copyright := strings.TrimSpace(input.Copyright)
+	if utf8.RuneCountInString(copyright) > domain.SongCopyrightMaxLength {
+		return fmt.Errorf("%w: copyright is longer than %d characters", domain.ErrInvalidSong, domain.SongCopyrightMaxLength)
+	}
+
+	key := strings.TrimSpace(input.DefaultKey)
+	if key != "" && !domain.SongKeyRegex.MatchString(key) {
+		return fmt.Errorf("%w: key %q", domain.ErrInvalidSong, input.DefaultKey)
+	}
+
+	if input.Tempo != 0 && (input.Tempo < domain.SongMinTempo || input.Tempo > domain.SongMaxTempo) {
+		return fmt.Errorf("%w: tempo must be between %d and %d", domain.ErrInvalidSong, domain.SongMinTempo, domain.SongMaxTempo)
+	}
+
+	timeSignature := strings.TrimSpace(input.TimeSignature)
+	if timeSignature != "" && !domain.SongTimeSignatureRegex.MatchString(timeSignature) {
+		return fmt.Errorf("%w: time signature %q", domain.ErrInvalidSong, input.TimeSignature)
+	}
+
+	if len(input.Themes) > domain.SongMaxThemes {
+		return fmt.Errorf("%w: more than %d themes", domain.ErrInvalidSong, domain.SongMaxThemes)
+	}
+
+	themes := make([]string, 0, len(input.Themes))
+	for _, theme := range input.Themes {
+		normalized, ok := normalizeTheme(theme)
+		if !ok {
+			return fmt.Errorf("%w: theme %q", domain.ErrInvalidSong, theme)
+		}
+
+		if !slices.Contains(themes, normalized) {
+			themes = append(themes, normalized)
+		}
+	}
+
+	if utf8.RuneCountInString(input.Lyrics) > domain.SongLyricsMaxLength {
+		return fmt.Errorf("%w: lyrics are longer than %d characters", domain.ErrInvalidSong, domain.SongLyricsMaxLength)
+	}
+
+	song.Title = title
+	song.Authors = authors
+	song.CCLINumber = ccliNumber
+	song.Copyright = copyright
+	song.DefaultKey = key
+	song.Tempo = input.Tempo
+	song.TimeSignature = timeSignature
+	song.Themes = themes
+	song.Lyrics = input.Lyrics
+
+	return nil
+}
+
+// normalizeTheme lowercases a theme and collapses its spaces, so "Holy  Spirit" and "holy spirit"
+// are the same theme. It reports false for empty or overlong themes.
+func normalizeTheme(theme string) (string, bool) {
+	theme = strings.ToLower(strings.Join(strings.FieldsFunc(theme, unicode.IsSpace), " "))
+	if theme == "" || utf8.RuneCountInString(theme) > domain.TagMaxLength {
+		return "", false
+	}
+
+	return theme, true
+}